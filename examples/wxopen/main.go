@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 
@@ -220,6 +221,31 @@ func main() {
 		)
 	}
 
+	// 所有授权帐号共用的消息回调地址 /callback/$APPID$
+	http.Handle("/callback/", wxopenApi.NewAuthorizerRouter(&wxopen.AuthorizerRouterConfig{
+		Resolver: wxopen.AuthorizerResolverFunc(func(
+			ctx context.Context, appid, userName string,
+		) (*wxopen.AuthorizerTenant, error) {
+			if appid != wxopenOA.Appid {
+				return nil, wxopen.ErrAuthorizerNotFound
+			}
+			return &wxopen.AuthorizerTenant{
+				Appid:  wxopenOA.Appid,
+				Client: wxopenOA.Client,
+				Handler: func(
+					w http.ResponseWriter, r *http.Request,
+					serverApi *server_api.ServerApi, body []byte,
+				) error {
+					return serveAuthorizerData(serverApi)(w, r, body)
+				},
+			}, nil
+		}),
+		Release: &wxopen.ReleaseConfig{},
+		OnError: func(r *http.Request, err error) {
+			fmt.Printf("serve authorizer data fail %v\n", err)
+		},
+	}))
+
 	http.HandleFunc("/", index(wxopenApi))
 	http.HandleFunc("/auth", auth(wxopenApi, false))
 	http.HandleFunc("/auth/mobile", auth(wxopenApi, true))
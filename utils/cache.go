@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"encoding/json"
 	"time"
 )
 
@@ -14,3 +15,28 @@ type Cache interface {
 	Delete(string) error
 	TTL(string) (int, error)
 }
+
+// CacheGetJSON 读取 json 格式的值, 不存在返回 false
+func CacheGetJSON(cache Cache, key string, value interface{}) (bool, error) {
+	var data string
+	exist, err := cache.Get(key, &data)
+	if err != nil {
+		return false, err
+	}
+	if !exist || data == "" {
+		return false, nil
+	}
+	if err = json.Unmarshal([]byte(data), value); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CacheSetJSON 以 json 格式保存
+func CacheSetJSON(cache Cache, key string, value interface{}, timeout time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return cache.Set(key, string(data), timeout)
+}
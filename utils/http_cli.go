@@ -155,13 +155,12 @@ func (client *Client) HTTPGetRaw(
 		result := &WeixinError{}
 		if err = doWeixinError(resp, result); err != nil {
 			return nil, err
-		} else {
-			// wtf
-			panic(fmt.Errorf(
-				"request (%s) response invalid json response(%d: %s)",
-				req.URL.Path, result.ErrCode, result.ErrMsg,
-			))
 		}
+		// 例如 获取永久素材 图文/视频 返回的是json
+		return nil, fmt.Errorf(
+			"request (%s) response json instead of file, %w",
+			req.URL.Path, ErrorJsonResponse,
+		)
 	}
 
 	return resp, nil
@@ -202,13 +201,12 @@ func (client *Client) HTTPPostDownload(
 		result := &WeixinError{}
 		if err = doWeixinError(resp, result); err != nil {
 			return nil, err
-		} else {
-			// wtf
-			panic(fmt.Errorf(
-				"request (%s) response invalid json response(%d: %s)",
-				req.URL.Path, result.ErrCode, result.ErrMsg,
-			))
 		}
+		// 例如 获取永久素材 图文/视频 返回的是json
+		return nil, fmt.Errorf(
+			"request (%s) response json instead of file, %w",
+			req.URL.Path, ErrorJsonResponse,
+		)
 	}
 
 	return resp, nil
@@ -253,6 +251,15 @@ func (client *Client) HTTPUpload(
 func (client *Client) HttpFile(
 	ctx context.Context, path, key, filename string,
 	content io.Reader, querysFunc func(url.Values), result interface{},
+) (err error) {
+	return client.HttpFileWithFields(ctx, path, key, filename, content, nil, querysFunc, result)
+}
+
+// HttpFileWithFields 上传文件, 同时附带普通的表单字段(例如永久视频素材的description)
+func (client *Client) HttpFileWithFields(
+	ctx context.Context, path, key, filename string,
+	content io.Reader, fields map[string]string,
+	querysFunc func(url.Values), result interface{},
 ) (err error) {
 	r, w := io.Pipe()
 	m := multipart.NewWriter(w)
@@ -260,6 +267,12 @@ func (client *Client) HttpFile(
 		defer w.Close()
 		defer m.Close()
 
+		for k, v := range fields {
+			if err := m.WriteField(k, v); err != nil {
+				return
+			}
+		}
+
 		part, err := m.CreateFormFile(key, filepath.Base(filename))
 		if err != nil {
 			return
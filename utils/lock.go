@@ -1,6 +1,12 @@
 package utils
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrLockTimeout = errors.New("lock timeout")
 
 type Lock interface {
 	// key , 超时时间
@@ -10,3 +16,25 @@ type Lock interface {
 	// key ， 超时时间， 等待总时间， 失败后休眠时长
 	LockTimeout(string, time.Duration, time.Duration, time.Duration) (bool, error)
 }
+
+const (
+	withLockTimeout = 5 * time.Second
+	withLockSleep   = 50 * time.Millisecond
+)
+
+// WithLock 持有 key 的锁执行 fn, locker 为空时直接执行, 用于可选的多进程互斥
+func WithLock(locker Lock, key string, fn func() error) error {
+	if locker == nil {
+		return fn()
+	}
+
+	ok, err := locker.LockTimeout(key, withLockTimeout, withLockTimeout, withLockSleep)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("lock %s, %w", key, ErrLockTimeout)
+	}
+	defer locker.UnLock(key)
+	return fn()
+}
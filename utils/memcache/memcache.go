@@ -0,0 +1,139 @@
+package memcache
+
+// Package memcache 进程内的 utils.Cache 和 utils.Lock 实现, 适用于测试或者单进程
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+type item struct {
+	value    string
+	expireAt time.Time // 零值表示不过期
+}
+
+func (i *item) expired(now time.Time) bool {
+	return !i.expireAt.IsZero() && !now.Before(i.expireAt)
+}
+
+// Memcache 内存缓存, 与 redis.Redis 一样只支持 string 类型的值
+type Memcache struct {
+	mutex sync.Mutex
+	items map[string]*item
+}
+
+// NewMemcache 实例化
+func NewMemcache() *Memcache {
+	return &Memcache{items: map[string]*item{}}
+}
+
+// 调用方持有锁
+func (m *Memcache) load(key string, now time.Time) (*item, bool) {
+	i, ok := m.items[key]
+	if !ok {
+		return nil, false
+	}
+	if i.expired(now) {
+		delete(m.items, key)
+		return nil, false
+	}
+	return i, true
+}
+
+func (m *Memcache) store(key, value string, timeout time.Duration, now time.Time) {
+	i := &item{value: value}
+	if timeout > 0 {
+		i.expireAt = now.Add(timeout)
+	}
+	m.items[key] = i
+}
+
+// Get 获取一个值
+func (m *Memcache) Get(key string, value interface{}) (bool, error) {
+	v, ok := value.(*string)
+	if !ok {
+		return false, fmt.Errorf("value must be pointer to string")
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	i, exist := m.load(key, time.Now())
+	if !exist {
+		return false, nil
+	}
+	*v = i.value
+	return true, nil
+}
+
+// Set 设置一个值, timeout 为 0 时不过期
+func (m *Memcache) Set(key string, val interface{}, timeout time.Duration) error {
+	data, ok := val.(string)
+	if !ok {
+		return fmt.Errorf("val must be string")
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.store(key, data, timeout, time.Now())
+	return nil
+}
+
+// IsExist 判断key是否存在
+func (m *Memcache) IsExist(key string) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	_, ok := m.load(key, time.Now())
+	return ok
+}
+
+// Delete 删除
+func (m *Memcache) Delete(key string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	delete(m.items, key)
+	return nil
+}
+
+// TTL 获得剩余时间(秒), 不存在返回 -2, 不过期返回 -1, 与 redis 一致
+func (m *Memcache) TTL(key string) (int, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	now := time.Now()
+	i, ok := m.load(key, now)
+	if !ok {
+		return -2, nil
+	}
+	if i.expireAt.IsZero() {
+		return -1, nil
+	}
+	return int(i.expireAt.Sub(now) / time.Second), nil
+}
+
+// Lock 不存在时设置(set if absent), 已存在返回 false
+func (m *Memcache) Lock(key string, expire time.Duration) (bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	now := time.Now()
+	if _, ok := m.load(key, now); ok {
+		return false, nil
+	}
+	m.store(key, "1", expire, now)
+	return true, nil
+}
+
+func (m *Memcache) LockTimeout(key string, expire, timeout, sleep time.Duration) (bool, error) {
+	var total time.Duration = 0
+	for total < timeout {
+		if ok, err := m.Lock(key, expire); err != nil || ok {
+			return ok, err
+		}
+		time.Sleep(sleep)
+		total += sleep
+	}
+	return false, nil
+}
+
+func (m *Memcache) UnLock(key string) error {
+	return m.Delete(key)
+}
@@ -0,0 +1,65 @@
+package memcache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lixinio/weixin/utils"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	_ utils.Cache = (*Memcache)(nil)
+	_ utils.Lock  = (*Memcache)(nil)
+)
+
+func TestMemcache(t *testing.T) {
+	cache := NewMemcache()
+
+	var value string
+	exist, err := cache.Get("key", &value)
+	require.Equal(t, nil, err)
+	require.False(t, exist)
+
+	require.Equal(t, nil, cache.Set("key", "value", time.Minute))
+	exist, err = cache.Get("key", &value)
+	require.Equal(t, nil, err)
+	require.True(t, exist)
+	require.Equal(t, "value", value)
+	require.True(t, cache.IsExist("key"))
+
+	ttl, err := cache.TTL("key")
+	require.Equal(t, nil, err)
+	require.True(t, ttl > 0 && ttl <= 60)
+
+	require.Equal(t, nil, cache.Delete("key"))
+	require.False(t, cache.IsExist("key"))
+
+	// 过期
+	require.Equal(t, nil, cache.Set("expire", "value", 10*time.Millisecond))
+	time.Sleep(20 * time.Millisecond)
+	require.False(t, cache.IsExist("expire"))
+
+	require.NotEqual(t, nil, cache.Set("key", 1, time.Minute))
+}
+
+func TestMemcacheLock(t *testing.T) {
+	cache := NewMemcache()
+
+	ok, err := cache.Lock("lock", time.Minute)
+	require.Equal(t, nil, err)
+	require.True(t, ok)
+
+	ok, err = cache.Lock("lock", time.Minute)
+	require.Equal(t, nil, err)
+	require.False(t, ok)
+
+	ok, err = cache.LockTimeout("lock", time.Minute, 20*time.Millisecond, 5*time.Millisecond)
+	require.Equal(t, nil, err)
+	require.False(t, ok)
+
+	require.Equal(t, nil, cache.UnLock("lock"))
+	ok, err = cache.LockTimeout("lock", time.Minute, 20*time.Millisecond, 5*time.Millisecond)
+	require.Equal(t, nil, err)
+	require.True(t, ok)
+}
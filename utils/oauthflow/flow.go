@@ -0,0 +1,309 @@
+package oauthflow
+
+// Package oauthflow 网页授权流程, 负责 state 的生成/校验, 防止 CSRF 和 code 重放
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/lixinio/weixin/utils"
+)
+
+const (
+	defaultStateTTL   = 10 * time.Minute
+	defaultCodeTTL    = 10 * time.Minute // 微信 code 有效期 5 分钟
+	defaultCookieName = "weixin_oauth_state"
+	defaultKeyPrefix  = "weixin:oauth:"
+	returnToParam     = "return_to"
+)
+
+var (
+	ErrInvalidState  = errors.New("invalid oauth state")
+	ErrStateExpired  = errors.New("oauth state expired or used")
+	ErrStateMismatch = errors.New("oauth state mismatch")
+	ErrCodeMissing   = errors.New("oauth code missing")
+	ErrCodeReplayed  = errors.New("oauth code replayed")
+)
+
+type Config struct {
+	Provider Provider
+	Cache    utils.Cache
+	// 用于原子地标记 state/code 已使用(set if absent), 例如 redis.Redis
+	Locker utils.Lock
+	// 签名 state 的密钥
+	Secret []byte
+	// 授权回调的完整地址, 即 CallbackHandler 对应的 url
+	CallbackUrl string
+	// state 有效期, 默认 10 分钟
+	StateTTL time.Duration
+	// 用来绑定浏览器的 cookie 名称前缀, 默认 weixin_oauth_state
+	// 每个 state 使用单独的 cookie, 多个标签页同时发起授权互不覆盖
+	CookieName string
+	// 缓存 key 前缀, 默认 weixin:oauth:
+	KeyPrefix string
+	// 默认回跳地址, 默认 /
+	DefaultReturnTo string
+	// 校验回跳地址, 默认仅允许站内相对路径
+	AllowReturnTo func(returnTo string) bool
+	// 授权成功, 负责建立会话并跳转到 returnTo
+	OnSuccess func(w http.ResponseWriter, r *http.Request, identity *Identity, returnTo string)
+	// 授权失败, 默认返回 400/502
+	OnError func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+type Flow struct {
+	config *Config
+}
+
+type stateData struct {
+	ReturnTo string `json:"return_to"`
+	ExpireAt int64  `json:"expire_at"`
+}
+
+func New(config *Config) *Flow {
+	if config.Provider == nil || config.Cache == nil || config.Locker == nil || config.OnSuccess == nil {
+		panic("oauthflow: Provider, Cache, Locker and OnSuccess are required")
+	}
+	if len(config.Secret) == 0 {
+		panic("oauthflow: Secret is required")
+	}
+	if config.StateTTL <= 0 {
+		config.StateTTL = defaultStateTTL
+	}
+	if config.CookieName == "" {
+		config.CookieName = defaultCookieName
+	}
+	if config.KeyPrefix == "" {
+		config.KeyPrefix = defaultKeyPrefix
+	}
+	if config.DefaultReturnTo == "" {
+		config.DefaultReturnTo = "/"
+	}
+	if config.AllowReturnTo == nil {
+		config.AllowReturnTo = IsLocalReturnTo
+	}
+	if config.OnError == nil {
+		config.OnError = defaultOnError
+	}
+	return &Flow{config: config}
+}
+
+// IsLocalReturnTo 仅允许站内相对路径, 防止 open redirect
+func IsLocalReturnTo(returnTo string) bool {
+	if !strings.HasPrefix(returnTo, "/") {
+		return false
+	}
+	// 排除 //host 和 /\host
+	if len(returnTo) > 1 && (returnTo[1] == '/' || returnTo[1] == '\\') {
+		return false
+	}
+	return true
+}
+
+func defaultOnError(w http.ResponseWriter, r *http.Request, err error) {
+	for _, e := range []error{
+		ErrInvalidState, ErrStateExpired, ErrStateMismatch, ErrCodeMissing, ErrCodeReplayed,
+	} {
+		if errors.Is(err, e) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	http.Error(w, err.Error(), http.StatusBadGateway)
+}
+
+func (flow *Flow) sign(nonce string) string {
+	mac := hmac.New(sha256.New, flow.config.Secret)
+	mac.Write([]byte(nonce))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (flow *Flow) stateKey(nonce string) string {
+	return flow.config.KeyPrefix + "state:" + nonce
+}
+
+func (flow *Flow) codeKey(code string) string {
+	return flow.config.KeyPrefix + "code:" + code
+}
+
+func (flow *Flow) cookieName(nonce string) string {
+	return flow.config.CookieName + "_" + nonce
+}
+
+// 原子地标记 key 已使用, 已经被标记返回 false
+func (flow *Flow) claim(key string, ttl time.Duration) (bool, error) {
+	return flow.config.Locker.Lock(key+":used", ttl)
+}
+
+// 生成 state 并写入缓存, 返回 state 和 nonce
+func (flow *Flow) newState(returnTo string) (string, string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	nonce := hex.EncodeToString(buf)
+
+	data, err := json.Marshal(&stateData{
+		ReturnTo: returnTo,
+		ExpireAt: time.Now().Add(flow.config.StateTTL).Unix(),
+	})
+	if err != nil {
+		return "", "", err
+	}
+	if err = flow.config.Cache.Set(
+		flow.stateKey(nonce), string(data), flow.config.StateTTL,
+	); err != nil {
+		return "", "", err
+	}
+	return nonce + "." + flow.sign(nonce), nonce, nil
+}
+
+// 校验 state 签名, 读取并删除缓存(一次性)
+func (flow *Flow) consumeState(state string) (*stateData, string, error) {
+	parts := strings.SplitN(state, ".", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return nil, "", ErrInvalidState
+	}
+	nonce := parts[0]
+	if !hmac.Equal([]byte(parts[1]), []byte(flow.sign(nonce))) {
+		return nil, "", ErrInvalidState
+	}
+
+	// 并发的回调只有一个能使用 state
+	key := flow.stateKey(nonce)
+	ok, err := flow.claim(key, flow.config.StateTTL)
+	if err != nil {
+		return nil, "", err
+	}
+	if !ok {
+		return nil, "", ErrStateExpired
+	}
+
+	var value string
+	exist, err := flow.config.Cache.Get(key, &value)
+	if err != nil {
+		return nil, "", err
+	}
+	if !exist || value == "" {
+		return nil, "", ErrStateExpired
+	}
+	if err = flow.config.Cache.Delete(key); err != nil {
+		return nil, "", err
+	}
+
+	data := &stateData{}
+	if err = json.Unmarshal([]byte(value), data); err != nil {
+		return nil, "", fmt.Errorf("%w, %s", ErrInvalidState, err.Error())
+	}
+	if time.Now().Unix() > data.ExpireAt {
+		return nil, "", ErrStateExpired
+	}
+	return data, nonce, nil
+}
+
+// 标记 code 已使用, 同一个 code 只允许兑换一次
+func (flow *Flow) markCode(code string) error {
+	ok, err := flow.claim(flow.codeKey(code), defaultCodeTTL)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrCodeReplayed
+	}
+	return nil
+}
+
+// AuthorizeUrl 生成 state, 写 cookie 并返回授权跳转地址
+func (flow *Flow) AuthorizeUrl(
+	w http.ResponseWriter, r *http.Request, returnTo string,
+) (string, error) {
+	if returnTo == "" || !flow.config.AllowReturnTo(returnTo) {
+		returnTo = flow.config.DefaultReturnTo
+	}
+
+	state, nonce, err := flow.newState(returnTo)
+	if err != nil {
+		return "", err
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     flow.cookieName(nonce),
+		Value:    nonce,
+		Path:     "/",
+		MaxAge:   int(flow.config.StateTTL / time.Second),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return flow.config.Provider.AuthorizeUrl(flow.config.CallbackUrl, state), nil
+}
+
+// StartHandler 发起授权, 支持通过 return_to 参数指定授权完成之后的回跳地址
+func (flow *Flow) StartHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorizeUrl, err := flow.AuthorizeUrl(w, r, r.URL.Query().Get(returnToParam))
+		if err != nil {
+			flow.config.OnError(w, r, err)
+			return
+		}
+		http.Redirect(w, r, authorizeUrl, http.StatusFound)
+	})
+}
+
+// CallbackHandler 授权回调, 校验 state 之后通过 code 换取用户身份
+func (flow *Flow) CallbackHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identity, returnTo, err := flow.HandleCallback(w, r)
+		if err != nil {
+			flow.config.OnError(w, r, err)
+			return
+		}
+		flow.config.OnSuccess(w, r, identity, returnTo)
+	})
+}
+
+// HandleCallback 处理授权回调, 返回用户身份和回跳地址
+func (flow *Flow) HandleCallback(
+	w http.ResponseWriter, r *http.Request,
+) (*Identity, string, error) {
+	query := r.URL.Query()
+	data, nonce, err := flow.consumeState(query.Get("state"))
+	if err != nil {
+		return nil, "", err
+	}
+
+	// state 必须由当前浏览器发起
+	cookie, err := r.Cookie(flow.cookieName(nonce))
+	if err != nil || !hmac.Equal([]byte(cookie.Value), []byte(nonce)) {
+		return nil, "", ErrStateMismatch
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:   flow.cookieName(nonce),
+		Value:  "",
+		Path:   "/",
+		MaxAge: -1,
+	})
+
+	// 用户拒绝授权时没有 code
+	code := query.Get("code")
+	if code == "" {
+		return nil, "", ErrCodeMissing
+	}
+	if err = flow.markCode(code); err != nil {
+		return nil, "", err
+	}
+
+	identity, err := flow.config.Provider.Exchange(r.Context(), code)
+	if err != nil {
+		return nil, "", err
+	}
+	return identity, data.ReturnTo, nil
+}
@@ -0,0 +1,141 @@
+package oauthflow
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/lixinio/weixin/utils/memcache"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeProvider struct{}
+
+func (p *fakeProvider) AuthorizeUrl(redirectUri, state string) string {
+	params := url.Values{}
+	params.Add("redirect_uri", redirectUri)
+	params.Add("state", state)
+	return "https://open.weixin.qq.com/authorize?" + params.Encode()
+}
+
+func (p *fakeProvider) Exchange(ctx context.Context, code string) (*Identity, error) {
+	return &Identity{OpenID: "openid_" + code}, nil
+}
+
+func TestFlow(t *testing.T) {
+	cache := memcache.NewMemcache()
+	var identity *Identity
+	var returnTo string
+	flow := New(&Config{
+		Provider:    &fakeProvider{},
+		Cache:       cache,
+		Locker:      cache,
+		Secret:      []byte("secret"),
+		CallbackUrl: "https://example.com/oauth/callback",
+		OnSuccess: func(w http.ResponseWriter, r *http.Request, i *Identity, to string) {
+			identity, returnTo = i, to
+			http.Redirect(w, r, to, http.StatusFound)
+		},
+	})
+
+	start := func(returnTo string) (string, *http.Cookie) {
+		w := httptest.NewRecorder()
+		flow.StartHandler().ServeHTTP(w, httptest.NewRequest(
+			"GET", "/oauth/start?return_to="+url.QueryEscape(returnTo), nil,
+		))
+		require.Equal(t, http.StatusFound, w.Code)
+
+		location, err := url.Parse(w.Header().Get("Location"))
+		require.Equal(t, nil, err)
+		require.Equal(t, "https://example.com/oauth/callback", location.Query().Get("redirect_uri"))
+		return location.Query().Get("state"), w.Result().Cookies()[0]
+	}
+
+	callback := func(state, code string, cookie *http.Cookie) int {
+		r := httptest.NewRequest("GET", "/oauth/callback?"+url.Values{
+			"state": {state}, "code": {code},
+		}.Encode(), nil)
+		if cookie != nil {
+			r.AddCookie(cookie)
+		}
+		w := httptest.NewRecorder()
+		flow.CallbackHandler().ServeHTTP(w, r)
+		return w.Code
+	}
+
+	// 正常流程
+	state, cookie := start("/orders?id=1")
+	require.Equal(t, http.StatusFound, callback(state, "code1", cookie))
+	require.Equal(t, "openid_code1", identity.OpenID)
+	require.Equal(t, "/orders?id=1", returnTo)
+
+	// state 只能使用一次
+	require.Equal(t, http.StatusBadRequest, callback(state, "code2", cookie))
+
+	// code 不能重放
+	state, cookie = start("/")
+	require.Equal(t, http.StatusBadRequest, callback(state, "code1", cookie))
+
+	// 缺少 cookie (其他浏览器发起)
+	state, _ = start("/")
+	require.Equal(t, http.StatusBadRequest, callback(state, "code3", nil))
+
+	// 篡改 state
+	state, cookie = start("/")
+	require.Equal(t, http.StatusBadRequest, callback(state+"0", "code4", cookie))
+
+	// 站外地址回跳到默认地址
+	state, cookie = start("//evil.com/path")
+	require.Equal(t, http.StatusFound, callback(state, "code5", cookie))
+	require.Equal(t, "/", returnTo)
+
+	// 两个标签页同时发起授权, cookie 互不覆盖
+	state1, cookie1 := start("/tab1")
+	state2, cookie2 := start("/tab2")
+	require.NotEqual(t, cookie1.Name, cookie2.Name)
+	require.Equal(t, http.StatusFound, callback(state2, "code6", cookie2))
+	require.Equal(t, "/tab2", returnTo)
+	require.Equal(t, http.StatusFound, callback(state1, "code7", cookie1))
+	require.Equal(t, "/tab1", returnTo)
+
+	// 并发回调同一个 code, 只有一个成功
+	states := make([]string, 10)
+	cookies := make([]*http.Cookie, 10)
+	for i := range states {
+		states[i], cookies[i] = start("/")
+	}
+	codes := make([]int, 10)
+	wg := sync.WaitGroup{}
+	for i := range states {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			codes[i] = callback(states[i], "code8", cookies[i])
+		}(i)
+	}
+	wg.Wait()
+	success := 0
+	for _, code := range codes {
+		if code == http.StatusFound {
+			success++
+		}
+	}
+	require.Equal(t, 1, success)
+}
+
+func TestIsLocalReturnTo(t *testing.T) {
+	for returnTo, allow := range map[string]bool{
+		"/":                  true,
+		"/a/b?c=d":           true,
+		"":                   false,
+		"//evil.com":         false,
+		"/\\evil.com":        false,
+		"https://evil.com/":  false,
+		"javascript:alert()": false,
+	} {
+		require.Equal(t, allow, IsLocalReturnTo(returnTo), returnTo)
+	}
+}
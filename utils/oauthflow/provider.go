@@ -0,0 +1,34 @@
+package oauthflow
+
+import (
+	"context"
+)
+
+// Identity 授权完成之后归一化的用户身份
+type Identity struct {
+	AppID   string `json:"appid,omitempty"`   // 公众号/网站应用 appid, 企业微信为空
+	CorpID  string `json:"corpid,omitempty"`  // 企业微信 corpid
+	OpenID  string `json:"openid,omitempty"`  // 公众号/网站应用 openid
+	UnionID string `json:"unionid,omitempty"` // 公众号/网站应用 unionid
+	UserID  string `json:"userid,omitempty"`  // 企业微信成员 userid
+	// 企业微信第三方应用 全局唯一的 open_userid
+	OpenUserID string `json:"open_userid,omitempty"`
+	Scope      string `json:"scope,omitempty"`
+
+	// 网页授权 access_token, 企业微信为空
+	AccessToken  string `json:"-"`
+	RefreshToken string `json:"-"`
+	ExpiresIn    int    `json:"-"`
+	// 企业微信第三方应用 user_ticket, 用于获取成员敏感信息
+	UserTicket string `json:"-"`
+}
+
+// Provider 网页授权后端, 由各个帐号类型实现, 例如
+// official_account.OfficialAccount.OAuthProvider, web_sso.WebSSO.OAuthProvider,
+// wxopen.WxOpen.OAuthProvider, agent.Agent.OAuthProvider, wxwork_suite.WxWorkSuite.OAuthProvider
+type Provider interface {
+	// 构造授权跳转链接
+	AuthorizeUrl(redirectUri, state string) string
+	// 通过 code 换取用户身份
+	Exchange(ctx context.Context, code string) (*Identity, error)
+}
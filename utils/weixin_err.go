@@ -9,6 +9,8 @@ var (
 	ErrorAccessToken = errors.New("access token error")
 	ErrorSystemBusy  = errors.New("system busy")
 	ErrorWeixinError = errors.New("system busy")
+	// 下载接口返回了 json (没有错误码), 而不是文件内容
+	ErrorJsonResponse = errors.New("unexpected json response")
 )
 
 type WeixinErrorInterface interface {
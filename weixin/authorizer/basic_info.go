@@ -0,0 +1,182 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/lixinio/weixin/utils"
+)
+
+const (
+	apiGetAccountBasicInfo   = "/cgi-bin/account/getaccountbasicinfo"
+	apiSetNickname           = "/wxa/setnickname"
+	apiQueryNickname         = "/wxa/api_wxa_querynickname"
+	apiCheckWxVerifyNickname = "/cgi-bin/wxverify/checkwxverifynickname"
+	apiModifyHeadImage       = "/cgi-bin/account/modifyheadimage"
+	apiModifySignature       = "/cgi-bin/account/modifysignature"
+)
+
+// 名称审核状态, 同时也是名称审核结果推送 EventWxaNickNameAudit.Ret 的取值
+const (
+	NicknameAuditStatusAuditing = 1 // 审核中
+	NicknameAuditStatusFail     = 2 // 审核失败
+	NicknameAuditStatusSuccess  = 3 // 审核成功
+)
+
+type ModifyQuota struct {
+	ModifyUsedCount int `json:"modify_used_count"` // 已用次数
+	ModifyQuota     int `json:"modify_quota"`      // 次数上限
+}
+
+type AccountBasicInfo struct {
+	utils.WeixinError
+	Appid          string `json:"appid"`
+	AccountType    int    `json:"account_type"`    // 帐号类型 1 订阅号 2 服务号 3 小程序
+	PrincipalType  int    `json:"principal_type"`  // 主体类型
+	PrincipalName  string `json:"principal_name"`  // 主体名称
+	Credential     string `json:"credential"`      // 主体标识
+	RealnameStatus int    `json:"realname_status"` // 实名验证状态 1 实名验证成功 2 实名验证中 3 实名验证失败
+	Nickname       string `json:"nickname"`
+	WxVerifyInfo   struct {
+		QualificationVerify   bool  `json:"qualification_verify"`     // 是否资质认证
+		NamingVerify          bool  `json:"naming_verify"`            // 是否名称认证
+		AnnualReview          bool  `json:"annual_review"`            // 是否需要年审
+		AnnualReviewBeginTime int64 `json:"annual_review_begin_time"` // 年审开始时间
+		AnnualReviewEndTime   int64 `json:"annual_review_end_time"`   // 年审截止时间
+	} `json:"wx_verify_info"`
+	SignatureInfo struct {
+		ModifyQuota
+		Signature string `json:"signature"` // 功能介绍
+	} `json:"signature_info"`
+	HeadImageInfo struct {
+		ModifyQuota
+		HeadImageUrl string `json:"head_image_url"` // 头像 url
+	} `json:"head_image_info"`
+	NicknameInfo struct {
+		ModifyQuota
+		Nickname string `json:"nickname"`
+	} `json:"nickname_info"`
+	RegisteredCountry int `json:"registered_country"` // 注册国家
+}
+
+/*
+获取基本信息
+https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/api/Mini_Program_Basic_Info/Mini_Program_Information_Settings.html
+GET https://api.weixin.qq.com/cgi-bin/account/getaccountbasicinfo?access_token=ACCESS_TOKEN
+*/
+func (api *AuthorizerApi) GetAccountBasicInfo(ctx context.Context) (*AccountBasicInfo, error) {
+	result := &AccountBasicInfo{}
+	if err := api.Client.HTTPGet(ctx, apiGetAccountBasicInfo, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type SetNicknameParams struct {
+	NickName          string `json:"nick_name"`                      // 昵称
+	IDCard            string `json:"id_card,omitempty"`              // 身份证照片 mediaid, 个人号必填
+	License           string `json:"license,omitempty"`              // 组织机构代码证或营业执照 mediaid, 组织号必填
+	NamingOtherStuff1 string `json:"naming_other_stuff_1,omitempty"` // 其他证明材料 mediaid
+	NamingOtherStuff2 string `json:"naming_other_stuff_2,omitempty"`
+	NamingOtherStuff3 string `json:"naming_other_stuff_3,omitempty"`
+	NamingOtherStuff4 string `json:"naming_other_stuff_4,omitempty"`
+	NamingOtherStuff5 string `json:"naming_other_stuff_5,omitempty"`
+}
+
+type SetNicknameResult struct {
+	utils.WeixinError
+	Wording string `json:"wording"`  // 材料说明
+	AuditID int32  `json:"audit_id"` // 审核单 id, 若接口未返回 audit_id，说明名称已直接设置成功，无需审核
+}
+
+/*
+设置名称
+审核结果通过 EventWxaNickNameAudit 推送
+https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/api/Mini_Program_Basic_Info/setnickname.html
+POST https://api.weixin.qq.com/wxa/setnickname?access_token=ACCESS_TOKEN
+*/
+func (api *AuthorizerApi) SetNickname(
+	ctx context.Context, params *SetNicknameParams,
+) (*SetNicknameResult, error) {
+	result := &SetNicknameResult{}
+	if err := api.Client.HTTPPostJson(ctx, apiSetNickname, params, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type NicknameAuditStatus struct {
+	utils.WeixinError
+	Nickname   string `json:"nickname"`    // 审核昵称
+	AuditStat  int    `json:"audit_stat"`  // 审核状态 NicknameAuditStatus*
+	FailReason string `json:"fail_reason"` // 失败原因
+	CreateTime int64  `json:"create_time"` // 审核提交时间
+	AuditTime  int64  `json:"audit_time"`  // 审核完成时间
+}
+
+/*
+查询改名审核状态
+https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/api/Mini_Program_Basic_Info/api_wxa_querynickname.html
+POST https://api.weixin.qq.com/wxa/api_wxa_querynickname?access_token=ACCESS_TOKEN
+*/
+func (api *AuthorizerApi) QueryNickname(ctx context.Context, auditID int32) (*NicknameAuditStatus, error) {
+	result := &NicknameAuditStatus{}
+	if err := api.Client.HTTPPostJson(ctx, apiQueryNickname, map[string]int32{
+		"audit_id": auditID,
+	}, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type CheckNicknameResult struct {
+	utils.WeixinError
+	HitCondition bool   `json:"hit_condition"` // 是否命中关键字策略。若命中，可以选填关键字材料
+	Wording      string `json:"wording"`       // 命中关键字的说明描述
+}
+
+/*
+微信认证名称检测
+https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/api/Mini_Program_Basic_Info/wxverify_checknickname.html
+POST https://api.weixin.qq.com/cgi-bin/wxverify/checkwxverifynickname?access_token=ACCESS_TOKEN
+*/
+func (api *AuthorizerApi) CheckWxVerifyNickname(
+	ctx context.Context, nickname string,
+) (*CheckNicknameResult, error) {
+	result := &CheckNicknameResult{}
+	if err := api.Client.HTTPPostJson(ctx, apiCheckWxVerifyNickname, map[string]string{
+		"nick_name": nickname,
+	}, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type ModifyHeadImageParams struct {
+	HeadImgMediaID string  `json:"head_img_media_id"` // 头像素材 media_id (临时素材)
+	X1             float64 `json:"x1"`                // 裁剪框左上角 x 坐标（取值范围：[0, 1]）
+	Y1             float64 `json:"y1"`                // 裁剪框左上角 y 坐标（取值范围：[0, 1]）
+	X2             float64 `json:"x2"`                // 裁剪框右下角 x 坐标（取值范围：[0, 1]）
+	Y2             float64 `json:"y2"`                // 裁剪框右下角 y 坐标（取值范围：[0, 1]）
+}
+
+/*
+修改头像
+图片格式只支持：jpg，图片大小不超过2M，一个月仅可修改5次
+https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/api/Mini_Program_Basic_Info/modifyheadimage.html
+POST https://api.weixin.qq.com/cgi-bin/account/modifyheadimage?access_token=ACCESS_TOKEN
+*/
+func (api *AuthorizerApi) ModifyHeadImage(ctx context.Context, params *ModifyHeadImageParams) error {
+	return api.Client.HTTPPostJson(ctx, apiModifyHeadImage, params, nil)
+}
+
+/*
+修改功能介绍
+功能介绍 4-120 个字符，一个月仅可修改5次
+https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/api/Mini_Program_Basic_Info/modifysignature.html
+POST https://api.weixin.qq.com/cgi-bin/account/modifysignature?access_token=ACCESS_TOKEN
+*/
+func (api *AuthorizerApi) ModifySignature(ctx context.Context, signature string) error {
+	return api.Client.HTTPPostJson(ctx, apiModifySignature, map[string]string{
+		"signature": signature,
+	}, nil)
+}
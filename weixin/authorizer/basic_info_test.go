@@ -0,0 +1,48 @@
+package authorizer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBasicInfo(t *testing.T) {
+	api := NewApi(initAuthorizer())
+	ctx := context.Background()
+
+	info, err := api.GetAccountBasicInfo(ctx)
+	require.Empty(t, err)
+	require.Equal(t, 3, info.AccountType)
+
+	_, err = api.CheckWxVerifyNickname(ctx, info.Nickname)
+	require.Empty(t, err)
+}
+
+func TestCategory(t *testing.T) {
+	api := NewApi(initAuthorizer())
+	ctx := context.Background()
+
+	categories, err := api.GetAllCategories(ctx)
+	require.Empty(t, err)
+	require.NotEmpty(t, categories)
+
+	settings, err := api.GetSettingCategories(ctx)
+	require.Empty(t, err)
+	require.LessOrEqual(t, len(settings.Categories), settings.CategoryLimit)
+}
+
+func TestPrivacySetting(t *testing.T) {
+	api := NewApi(initAuthorizer())
+	ctx := context.Background()
+
+	setting, err := api.GetPrivacySetting(ctx, PrivacyVerCurrent)
+	require.Empty(t, err)
+
+	err = api.SetPrivacySetting(ctx, &SetPrivacySettingParams{
+		PrivacyVer:   PrivacyVerDev,
+		OwnerSetting: setting.OwnerSetting,
+		SettingList:  setting.SettingList,
+	})
+	require.Empty(t, err)
+}
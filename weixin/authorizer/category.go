@@ -0,0 +1,133 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/lixinio/weixin/utils"
+)
+
+const (
+	apiGetAllCategories   = "/cgi-bin/wxopen/getallcategories"
+	apiGetSettingCategory = "/cgi-bin/wxopen/getcategory"
+	apiAddCategory        = "/cgi-bin/wxopen/addcategory"
+	apiDeleteCategory     = "/cgi-bin/wxopen/deletecategory"
+	apiModifyCategory     = "/cgi-bin/wxopen/modifycategory"
+)
+
+// 类目审核状态, 类目审核结果推送 EventWxaCategoryAudit.Ret 使用 2 驳回, 3 通过
+const (
+	CategoryAuditStatusAuditing = 1 // 审核中
+	CategoryAuditStatusReject   = 2 // 审核不通过
+	CategoryAuditStatusPass     = 3 // 审核通过
+)
+
+type CategoryNode struct {
+	ID            int    `json:"id"`
+	Name          string `json:"name"`
+	Level         int    `json:"level"`          // 类目层级
+	Father        int    `json:"father"`         // 父类目 id
+	Children      []int  `json:"children"`       // 子类目 id
+	SensitiveType int    `json:"sensitive_type"` // 是否为敏感类目（1 为敏感类目，需要提供相应资质审核；0 为非敏感类目，无需审核）
+	Qualify       struct {
+		ExterList []struct {
+			InnerList []struct {
+				Name string `json:"name"` // 资质文件名称
+				Url  string `json:"url"`  // 资质文件示例
+			} `json:"inner_list"`
+		} `json:"exter_list"`
+		Remark string `json:"remark"`
+	} `json:"qualify"` // 敏感类目需要的资质
+}
+
+/*
+获取可以设置的所有类目
+https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/api/category/getallcategories.html
+GET https://api.weixin.qq.com/cgi-bin/wxopen/getallcategories?access_token=ACCESS_TOKEN
+*/
+func (api *AuthorizerApi) GetAllCategories(ctx context.Context) ([]CategoryNode, error) {
+	result := &struct {
+		utils.WeixinError
+		CategoriesList struct {
+			Categories []CategoryNode `json:"categories"`
+		} `json:"categories_list"`
+	}{}
+	if err := api.Client.HTTPGet(ctx, apiGetAllCategories, result); err != nil {
+		return nil, err
+	}
+	return result.CategoriesList.Categories, nil
+}
+
+type SettingCategory struct {
+	First       int    `json:"first"`        // 一级类目 ID
+	FirstName   string `json:"first_name"`   // 一级类目名称
+	Second      int    `json:"second"`       // 二级类目 ID
+	SecondName  string `json:"second_name"`  // 二级类目名称
+	AuditStatus int    `json:"audit_status"` // 审核状态 CategoryAuditStatus*
+	AuditReason string `json:"audit_reason"` // 审核不通过的原因
+}
+
+type SettingCategories struct {
+	utils.WeixinError
+	Categories    []SettingCategory `json:"categories"`
+	Limit         int               `json:"limit"`          // 一个更改周期内可以添加类目的次数
+	Quota         int               `json:"quota"`          // 本更改周期内还可以添加类目的次数
+	CategoryLimit int               `json:"category_limit"` // 最多可以设置的类目数量
+}
+
+/*
+获取已设置的所有类目
+https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/api/category/getcategory.html
+GET https://api.weixin.qq.com/cgi-bin/wxopen/getcategory?access_token=ACCESS_TOKEN
+*/
+func (api *AuthorizerApi) GetSettingCategories(ctx context.Context) (*SettingCategories, error) {
+	result := &SettingCategories{}
+	if err := api.Client.HTTPGet(ctx, apiGetSettingCategory, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// 资质信息, key 为资质名称, value 为资质文件的 media_id
+type CategoryCert struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type CategoryParams struct {
+	First      int            `json:"first"`                // 一级类目 ID
+	Second     int            `json:"second"`               // 二级类目 ID
+	Certicates []CategoryCert `json:"certicates,omitempty"` // 资质信息列表(字段名与官方一致)
+}
+
+/*
+添加类目
+敏感类目需要审核, 审核结果通过 EventWxaCategoryAudit 推送
+https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/api/category/addcategory.html
+POST https://api.weixin.qq.com/cgi-bin/wxopen/addcategory?access_token=ACCESS_TOKEN
+*/
+func (api *AuthorizerApi) AddCategory(ctx context.Context, categories ...*CategoryParams) error {
+	return api.Client.HTTPPostJson(ctx, apiAddCategory, map[string]interface{}{
+		"categories": categories,
+	}, nil)
+}
+
+/*
+删除类目
+https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/api/category/deletecategory.html
+POST https://api.weixin.qq.com/cgi-bin/wxopen/deletecategory?access_token=ACCESS_TOKEN
+*/
+func (api *AuthorizerApi) DeleteCategory(ctx context.Context, first, second int) error {
+	return api.Client.HTTPPostJson(ctx, apiDeleteCategory, map[string]int{
+		"first":  first,
+		"second": second,
+	}, nil)
+}
+
+/*
+修改类目资质信息
+https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/api/category/modifycategory.html
+POST https://api.weixin.qq.com/cgi-bin/wxopen/modifycategory?access_token=ACCESS_TOKEN
+*/
+func (api *AuthorizerApi) ModifyCategory(ctx context.Context, category *CategoryParams) error {
+	return api.Client.HTTPPostJson(ctx, apiModifyCategory, category, nil)
+}
@@ -0,0 +1,179 @@
+package authorizer
+
+import (
+	"context"
+	"strings"
+
+	"github.com/lixinio/weixin/utils"
+	"github.com/lixinio/weixin/weixin/server_api"
+)
+
+const (
+	apiGetPage              = "/wxa/get_page"
+	apiGetCategory          = "/wxa/get_category"
+	apiGetAuditStatus       = "/wxa/get_auditstatus"
+	apiGetLatestAuditStatus = "/wxa/get_latest_auditstatus"
+	apiUndoCodeAudit        = "/wxa/undocodeaudit"
+	apiSpeedUpAudit         = "/wxa/speedupaudit"
+	apiQueryQuota           = "/wxa/queryquota"
+)
+
+// 审核状态
+const (
+	AuditStatusSuccess  = 0 // 审核成功
+	AuditStatusFail     = 1 // 审核被拒绝
+	AuditStatusAuditing = 2 // 审核中
+	AuditStatusUndo     = 3 // 已撤回
+	AuditStatusDelay    = 4 // 审核延后
+)
+
+/*
+获取已上传的代码的页面列表
+https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/api/code/get_page.html
+GET https://api.weixin.qq.com/wxa/get_page?access_token=ACCESS_TOKEN
+*/
+func (api *AuthorizerApi) GetPage(ctx context.Context) ([]string, error) {
+	result := &struct {
+		utils.WeixinError
+		PageList []string `json:"page_list"`
+	}{}
+	if err := api.Client.HTTPGet(ctx, apiGetPage, result); err != nil {
+		return nil, err
+	}
+	return result.PageList, nil
+}
+
+type Category struct {
+	FirstClass  string `json:"first_class"`
+	SecondClass string `json:"second_class"`
+	ThirdClass  string `json:"third_class"`
+	FirstID     int    `json:"first_id"`
+	SecondID    int    `json:"second_id"`
+	ThirdID     int    `json:"third_id"`
+}
+
+/*
+获取审核时可填写的类目信息
+https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/api/category/get_category.html
+GET https://api.weixin.qq.com/wxa/get_category?access_token=ACCESS_TOKEN
+*/
+func (api *AuthorizerApi) GetCategory(ctx context.Context) ([]Category, error) {
+	result := &struct {
+		utils.WeixinError
+		CategoryList []Category `json:"category_list"`
+	}{}
+	if err := api.Client.HTTPGet(ctx, apiGetCategory, result); err != nil {
+		return nil, err
+	}
+	return result.CategoryList, nil
+}
+
+type AuditStatus struct {
+	utils.WeixinError
+	AuditID         int32  `json:"auditid"`           // 仅 GetLatestAuditStatus 返回
+	Status          int    `json:"status"`            // 审核状态 AuditStatus*
+	Reason          string `json:"reason"`            // 当审核被拒绝时，返回的拒绝原因
+	ScreenShot      string `json:"screenshot"`        // 当审核被拒绝时，会返回审核失败的小程序截图示例。用 | 分隔的 media_id 的列表
+	UserVersion     string `json:"user_version"`      // 仅 GetLatestAuditStatus 返回
+	UserDesc        string `json:"user_desc"`         // 仅 GetLatestAuditStatus 返回
+	SubmitAuditTime int64  `json:"submit_audit_time"` // 仅 GetLatestAuditStatus 返回
+}
+
+/*
+查询指定发布审核单的审核状态
+https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/api/code/get_auditstatus.html
+POST https://api.weixin.qq.com/wxa/get_auditstatus?access_token=ACCESS_TOKEN
+*/
+func (api *AuthorizerApi) GetAuditStatus(ctx context.Context, auditID int32) (*AuditStatus, error) {
+	result := &AuditStatus{}
+	if err := api.Client.HTTPPostJson(ctx, apiGetAuditStatus, map[string]int32{
+		"auditid": auditID,
+	}, result); err != nil {
+		return nil, err
+	}
+	result.AuditID = auditID
+	return result, nil
+}
+
+/*
+查询最新一次提交的审核状态
+https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/api/code/get_latest_auditstatus.html
+GET https://api.weixin.qq.com/wxa/get_latest_auditstatus?access_token=ACCESS_TOKEN
+*/
+func (api *AuthorizerApi) GetLatestAuditStatus(ctx context.Context) (*AuditStatus, error) {
+	result := &struct {
+		AuditStatus
+		// 该接口返回的截图字段为大写
+		ScreenShot string `json:"ScreenShot"`
+	}{}
+	if err := api.Client.HTTPGet(ctx, apiGetLatestAuditStatus, result); err != nil {
+		return nil, err
+	}
+	if result.AuditStatus.ScreenShot == "" {
+		result.AuditStatus.ScreenShot = result.ScreenShot
+	}
+	return &result.AuditStatus, nil
+}
+
+/*
+小程序审核撤回
+单个帐号每天审核撤回次数最多不超过 5 次（每天的额度从0点开始生效），一个月不超过 10 次。
+https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/api/code/undocodeaudit.html
+GET https://api.weixin.qq.com/wxa/undocodeaudit?access_token=ACCESS_TOKEN
+*/
+func (api *AuthorizerApi) UndoCodeAudit(ctx context.Context) error {
+	return api.Client.HTTPGet(ctx, apiUndoCodeAudit, nil)
+}
+
+/*
+加急审核申请
+有加急次数的第三方可以通过该接口，对已经提审的小程序进行加急操作，加急后的小程序预计2-12小时内审完。
+https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/api/code/speedup_audit.html
+POST https://api.weixin.qq.com/wxa/speedupaudit?access_token=ACCESS_TOKEN
+*/
+func (api *AuthorizerApi) SpeedUpAudit(ctx context.Context, auditID int32) error {
+	return api.Client.HTTPPostJson(ctx, apiSpeedUpAudit, map[string]int32{
+		"auditid": auditID,
+	}, nil)
+}
+
+type AuditQuota struct {
+	utils.WeixinError
+	Rest         int `json:"rest"`          // quota剩余值
+	Limit        int `json:"limit"`         // 当月分配quota
+	SpeedUpRest  int `json:"speedup_rest"`  // 剩余加急次数
+	SpeedUpLimit int `json:"speedup_limit"` // 当月分配加急次数
+}
+
+/*
+查询服务商的当月提审限额（quota）和加急次数
+https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/api/code/query_quota.html
+GET https://api.weixin.qq.com/wxa/queryquota?access_token=ACCESS_TOKEN
+*/
+func (api *AuthorizerApi) QueryQuota(ctx context.Context) (*AuditQuota, error) {
+	result := &AuditQuota{}
+	if err := api.Client.HTTPGet(ctx, apiQueryQuota, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// AuditEventResult 将代码审核结果推送转换为审核状态
+// 推送中不包含 auditid, 同一个小程序同时只有一个审核单, 因此对应最近一次 SubmitAudit 返回的 auditid
+// 非审核结果推送返回 false
+func AuditEventResult(event interface{}) (*AuditStatus, bool) {
+	switch e := event.(type) {
+	case *server_api.EventWeappAuditSuccess:
+		return &AuditStatus{Status: AuditStatusSuccess}, true
+	case *server_api.EventWeappAuditFail:
+		return &AuditStatus{
+			Status:     AuditStatusFail,
+			Reason:     e.Reason,
+			ScreenShot: strings.TrimSpace(e.ScreenShot),
+		}, true
+	case *server_api.EventWeappAuditDelay:
+		return &AuditStatus{Status: AuditStatusDelay, Reason: e.Reason}, true
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,87 @@
+package authorizer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lixinio/weixin/weixin/server_api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditEventResult(t *testing.T) {
+	serverApi := &server_api.ServerApi{}
+	for _, item := range []struct {
+		body   string
+		result *AuditStatus
+	}{
+		{
+			body: `<xml>
+  <ToUserName><![CDATA[gh_fb9688c2a4b2]]></ToUserName>
+  <FromUserName><![CDATA[od1P50M-fNQI5Gcq-trm4a7apsU8]]></FromUserName>
+  <CreateTime>1488856741</CreateTime>
+  <MsgType><![CDATA[event]]></MsgType>
+  <Event><![CDATA[weapp_audit_success]]></Event>
+  <SuccTime>1488856741</SuccTime>
+</xml>`,
+			result: &AuditStatus{Status: AuditStatusSuccess},
+		},
+		{
+			body: `<xml>
+  <ToUserName><![CDATA[gh_fb9688c2a4b2]]></ToUserName>
+  <FromUserName><![CDATA[od1P50M-fNQI5Gcq-trm4a7apsU8]]></FromUserName>
+  <CreateTime>1488856591</CreateTime>
+  <MsgType><![CDATA[event]]></MsgType>
+  <Event><![CDATA[weapp_audit_fail]]></Event>
+  <Reason><![CDATA[账号信息不符合规范]]></Reason>
+  <FailTime>1488856591</FailTime>
+  <ScreenShot>xxx|yyy|zzz</ScreenShot>
+</xml>`,
+			result: &AuditStatus{
+				Status: AuditStatusFail, Reason: "账号信息不符合规范", ScreenShot: "xxx|yyy|zzz",
+			},
+		},
+		{
+			body: `<xml>
+  <ToUserName><![CDATA[gh_fb9688c2a4b2]]></ToUserName>
+  <FromUserName><![CDATA[od1P50M-fNQI5Gcq-trm4a7apsU8]]></FromUserName>
+  <CreateTime>1488856591</CreateTime>
+  <MsgType><![CDATA[event]]></MsgType>
+  <Event><![CDATA[weapp_audit_delay]]></Event>
+  <Reason><![CDATA[审核延后]]></Reason>
+  <DelayTime>1488856591</DelayTime>
+</xml>`,
+			result: &AuditStatus{Status: AuditStatusDelay, Reason: "审核延后"},
+		},
+		{
+			body: `<xml>
+  <ToUserName><![CDATA[toUser]]></ToUserName>
+  <FromUserName><![CDATA[FromUser]]></FromUserName>
+  <CreateTime>123456789</CreateTime>
+  <MsgType><![CDATA[event]]></MsgType>
+  <Event><![CDATA[subscribe]]></Event>
+</xml>`,
+		},
+	} {
+		event, err := serverApi.ParseXML([]byte(item.body))
+		require.Equal(t, nil, err)
+
+		result, ok := AuditEventResult(event)
+		require.Equal(t, item.result != nil, ok)
+		require.Equal(t, item.result, result)
+	}
+}
+
+func TestAuditStatus(t *testing.T) {
+	api := NewApi(initAuthorizer())
+	ctx := context.Background()
+
+	latest, err := api.GetLatestAuditStatus(ctx)
+	require.Empty(t, err)
+
+	status, err := api.GetAuditStatus(ctx, latest.AuditID)
+	require.Empty(t, err)
+	require.Equal(t, latest.Status, status.Status)
+
+	_, err = api.QueryQuota(ctx)
+	require.Empty(t, err)
+}
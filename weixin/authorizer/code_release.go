@@ -0,0 +1,166 @@
+package authorizer
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/lixinio/weixin/utils"
+)
+
+const (
+	apiRevertCodeRelease      = "/wxa/revertcoderelease"
+	apiGrayRelease            = "/wxa/grayrelease"
+	apiGetGrayReleasePlan     = "/wxa/getgrayreleaseplan"
+	apiRevertGrayRelease      = "/wxa/revertgrayrelease"
+	apiChangeVisitStatus      = "/wxa/change_visitstatus"
+	apiGetWeappSupportVersion = "/cgi-bin/wxopen/getweappsupportversion"
+	apiSetWeappSupportVersion = "/cgi-bin/wxopen/setweappsupportversion"
+)
+
+const (
+	VisitStatusOpen  = "open"  // 服务可用
+	VisitStatusClose = "close" // 服务不可用
+)
+
+/*
+版本回退
+调用本接口可以将小程序的线上版本进行回退, appVersion 为空时回退到上一个版本
+https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/api/code/revertcoderelease.html
+GET https://api.weixin.qq.com/wxa/revertcoderelease?access_token=ACCESS_TOKEN
+*/
+func (api *AuthorizerApi) RevertCodeRelease(ctx context.Context, appVersion string) error {
+	return api.Client.HTTPGetWithParams(ctx, apiRevertCodeRelease, func(params url.Values) {
+		if appVersion != "" {
+			params.Add("app_version", appVersion)
+		}
+	}, nil)
+}
+
+type HistoryVersion struct {
+	AppVersion  int    `json:"app_version"`
+	UserVersion string `json:"user_version"`
+	UserDesc    string `json:"user_desc"`
+	CommitTime  int64  `json:"commit_time"`
+}
+
+/*
+获取可回退的小程序版本
+https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/api/code/get_history_version.html
+GET https://api.weixin.qq.com/wxa/revertcoderelease?access_token=ACCESS_TOKEN&action=get_history_version
+*/
+func (api *AuthorizerApi) GetHistoryVersion(ctx context.Context) ([]HistoryVersion, error) {
+	result := &struct {
+		utils.WeixinError
+		VersionList []HistoryVersion `json:"version_list"`
+	}{}
+	if err := api.Client.HTTPGetWithParams(ctx, apiRevertCodeRelease, func(params url.Values) {
+		params.Add("action", "get_history_version")
+	}, result); err != nil {
+		return nil, err
+	}
+	return result.VersionList, nil
+}
+
+type GrayReleaseParams struct {
+	GrayPercentage     int  `json:"gray_percentage"`               // 灰度的百分比 0~100 的整数
+	SupportDebuger     bool `json:"support_debuger,omitempty"`     // 是否按照项目成员灰度
+	SupportExperiencer bool `json:"support_experiencer,omitempty"` // 是否按照体验者灰度
+}
+
+/*
+分阶段发布
+https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/api/code/grayrelease.html
+POST https://api.weixin.qq.com/wxa/grayrelease?access_token=ACCESS_TOKEN
+*/
+func (api *AuthorizerApi) GrayRelease(ctx context.Context, params *GrayReleaseParams) error {
+	return api.Client.HTTPPostJson(ctx, apiGrayRelease, params, nil)
+}
+
+// 分阶段发布状态
+const (
+	GrayReleaseStatusInit     = 0 // 初始状态
+	GrayReleaseStatusRunning  = 1 // 执行中
+	GrayReleaseStatusPaused   = 2 // 暂停中
+	GrayReleaseStatusFinished = 3 // 执行完毕
+	GrayReleaseStatusDeleted  = 4 // 被删除
+)
+
+type GrayReleasePlan struct {
+	Status                  int   `json:"status"`
+	CreateTimestamp         int64 `json:"create_timestamp"`
+	GrayPercentage          int   `json:"gray_percentage"`
+	SupportExperiencerFirst bool  `json:"support_experiencer_first"`
+	SupportDebugerFirst     bool  `json:"support_debuger_first"`
+}
+
+/*
+查询当前分阶段发布详情
+https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/api/code/getgrayreleaseplan.html
+GET https://api.weixin.qq.com/wxa/getgrayreleaseplan?access_token=ACCESS_TOKEN
+*/
+func (api *AuthorizerApi) GetGrayReleasePlan(ctx context.Context) (*GrayReleasePlan, error) {
+	result := &struct {
+		utils.WeixinError
+		GrayReleasePlan GrayReleasePlan `json:"gray_release_plan"`
+	}{}
+	if err := api.Client.HTTPGet(ctx, apiGetGrayReleasePlan, result); err != nil {
+		return nil, err
+	}
+	return &result.GrayReleasePlan, nil
+}
+
+/*
+取消分阶段发布
+https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/api/code/revertgrayrelease.html
+GET https://api.weixin.qq.com/wxa/revertgrayrelease?access_token=ACCESS_TOKEN
+*/
+func (api *AuthorizerApi) RevertGrayRelease(ctx context.Context) error {
+	return api.Client.HTTPGet(ctx, apiRevertGrayRelease, nil)
+}
+
+/*
+修改小程序服务状态
+action 设置可访问状态，发布后默认可访问，close 为不可见，open 为可见
+https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/api/code/change_visitstatus.html
+POST https://api.weixin.qq.com/wxa/change_visitstatus?access_token=ACCESS_TOKEN
+*/
+func (api *AuthorizerApi) ChangeVisitStatus(ctx context.Context, action string) error {
+	return api.Client.HTTPPostJson(ctx, apiChangeVisitStatus, map[string]string{
+		"action": action,
+	}, nil)
+}
+
+type WeappSupportVersion struct {
+	utils.WeixinError
+	NowVersion string `json:"now_version"` // 当前版本
+	UvInfo     struct {
+		Items []struct {
+			Percentage float64 `json:"percentage"` // 百分比
+			Version    string  `json:"version"`    // 基础库版本号
+		} `json:"items"`
+	} `json:"uv_info"` // 受影响用户占比
+}
+
+/*
+查询当前设置的最低基础库版本及各版本用户占比
+https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/api/code/getweappsupportversion.html
+POST https://api.weixin.qq.com/cgi-bin/wxopen/getweappsupportversion?access_token=ACCESS_TOKEN
+*/
+func (api *AuthorizerApi) GetWeappSupportVersion(ctx context.Context) (*WeappSupportVersion, error) {
+	result := &WeappSupportVersion{}
+	if err := api.Client.HTTPPostJson(ctx, apiGetWeappSupportVersion, struct{}{}, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+/*
+设置最低基础库版本
+https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/api/code/setweappsupportversion.html
+POST https://api.weixin.qq.com/cgi-bin/wxopen/setweappsupportversion?access_token=ACCESS_TOKEN
+*/
+func (api *AuthorizerApi) SetWeappSupportVersion(ctx context.Context, version string) error {
+	return api.Client.HTTPPostJson(ctx, apiSetWeappSupportVersion, map[string]string{
+		"version": version,
+	}, nil)
+}
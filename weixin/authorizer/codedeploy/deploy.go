@@ -0,0 +1,379 @@
+package codedeploy
+
+// Package codedeploy 第三方平台为授权小程序批量上传代码、提交审核并在审核通过后发布
+// 每个小程序的进度保存在 Store 中, 重启之后再次调用 Run 即可从断点继续
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/lixinio/weixin/utils"
+	"github.com/lixinio/weixin/weixin/authorizer"
+	"github.com/lixinio/weixin/wxopen"
+)
+
+const (
+	defaultConcurrency = 4
+	templateTypeNormal = 0     // 普通模板
+	errCodeReleased    = 85052 // 小程序已经发布
+)
+
+var ErrNoTemplate = errors.New("no code template available")
+
+// Api 发布流程用到的授权小程序接口, *authorizer.AuthorizerApi 实现了该接口
+type Api interface {
+	Commit(ctx context.Context, templateID int32, extJson, userVersion, userDesc string) error
+	SubmitAudit(ctx context.Context, auditParams *authorizer.AuditParams) (int32, error)
+	GetAuditStatus(ctx context.Context, auditID int32) (*authorizer.AuditStatus, error)
+	Release(ctx context.Context) error
+}
+
+// TemplateLister 代码模板列表, *wxopen.WxOpen 实现了该接口
+type TemplateLister interface {
+	GetTemplateList(ctx context.Context) ([]wxopen.Template, error)
+}
+
+type Config struct {
+	// 根据 appid 创建授权小程序接口, 必填
+	Api func(appid string) Api
+	// 发布状态存储, 必填
+	Store Store
+	// 代码模板 id, 为 0 时通过 Templates 选择最新的普通模板
+	TemplateID int32
+	Templates  TemplateLister
+	// 代码版本号和描述, 为空时使用模板的版本号和描述
+	UserVersion string
+	UserDesc    string
+	// 每个小程序的 ext_json, 可选
+	ExtJson func(appid string) string
+	// 每个小程序的提审参数, 可选
+	AuditParams func(appid string) *authorizer.AuditParams
+	// 同时处理的小程序数量, 默认4
+	Concurrency int
+	// 状态变化的通知, 可选
+	OnChange func(state *State)
+}
+
+// Orchestrator 批量发布
+// 同一进程内对同一个小程序的操作是串行的, 多进程部署时应保证同一次发布只有一个进程调用 Run
+type Orchestrator struct {
+	config *Config
+	mutex  sync.Mutex
+	locks  map[string]*sync.Mutex
+}
+
+func New(config *Config) *Orchestrator {
+	if config.Api == nil || config.Store == nil {
+		panic("codedeploy: Api and Store are required")
+	}
+	if config.TemplateID == 0 && config.Templates == nil {
+		panic("codedeploy: TemplateID or Templates is required")
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = defaultConcurrency
+	}
+	return &Orchestrator{config: config, locks: map[string]*sync.Mutex{}}
+}
+
+func (o *Orchestrator) lock(appid string) func() {
+	o.mutex.Lock()
+	locker, ok := o.locks[appid]
+	if !ok {
+		locker = &sync.Mutex{}
+		o.locks[appid] = locker
+	}
+	o.mutex.Unlock()
+
+	locker.Lock()
+	return locker.Unlock
+}
+
+// 选择最新的普通模板
+func (o *Orchestrator) resolveTemplate(ctx context.Context) error {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	if o.config.TemplateID != 0 {
+		return nil
+	}
+
+	templates, err := o.config.Templates.GetTemplateList(ctx)
+	if err != nil {
+		return err
+	}
+	var latest *wxopen.Template
+	for i := range templates {
+		template := &templates[i]
+		if template.TemplateType != templateTypeNormal {
+			continue
+		}
+		if latest == nil || template.CreateTime > latest.CreateTime {
+			latest = template
+		}
+	}
+	if latest == nil {
+		return ErrNoTemplate
+	}
+
+	o.config.TemplateID = latest.TemplateID
+	if o.config.UserVersion == "" {
+		o.config.UserVersion = latest.UserVersion
+	}
+	if o.config.UserDesc == "" {
+		o.config.UserDesc = latest.UserDesc
+	}
+	return nil
+}
+
+// Run 并发推进所有小程序的发布流程, 直到全部进入审核或者出错
+// 上次中断的小程序从保存的状态继续, 审核中的小程序会主动查询一次审核状态, 避免遗漏推送
+// 单个小程序的接口错误记录在 State.Error 中, 不会中断其他小程序; 返回的错误仅来自模板和存储
+func (o *Orchestrator) Run(ctx context.Context, appids []string) (*Progress, error) {
+	if err := o.resolveTemplate(ctx); err != nil {
+		return nil, err
+	}
+
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+	tasks := make(chan string)
+	for i := 0; i < o.config.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for appid := range tasks {
+				if err := o.advance(ctx, appid); err != nil {
+					once.Do(func() { firstErr = err })
+				}
+			}
+		}()
+	}
+
+dispatch:
+	for _, appid := range appids {
+		select {
+		case tasks <- appid:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(tasks)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return o.Progress(ctx)
+}
+
+func (o *Orchestrator) newState(appid string) *State {
+	return &State{
+		Appid:       appid,
+		TemplateID:  o.config.TemplateID,
+		UserVersion: o.config.UserVersion,
+		Status:      StatusPending,
+	}
+}
+
+func (o *Orchestrator) save(ctx context.Context, state *State, err error) error {
+	state.Error = ""
+	if err != nil {
+		state.Error = err.Error()
+	}
+	state.UpdatedAt = time.Now().Unix()
+	if err := o.config.Store.Save(ctx, state); err != nil {
+		return err
+	}
+	if o.config.OnChange != nil {
+		o.config.OnChange(state)
+	}
+	return nil
+}
+
+func (o *Orchestrator) advance(ctx context.Context, appid string) error {
+	unlock := o.lock(appid)
+	defer unlock()
+
+	state, err := o.config.Store.Load(ctx, appid)
+	if err == ErrStateNotFound {
+		state = o.newState(appid)
+	} else if err != nil {
+		return err
+	} else if state.TemplateID != o.config.TemplateID || state.UserVersion != o.config.UserVersion {
+		// 新的模板或版本, 重新开始
+		state = o.newState(appid)
+	}
+
+	api := o.config.Api(appid)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		switch state.Status {
+		case StatusPending:
+			extJson := ""
+			if o.config.ExtJson != nil {
+				extJson = o.config.ExtJson(appid)
+			}
+			if err := api.Commit(
+				ctx, state.TemplateID, extJson, state.UserVersion, o.config.UserDesc,
+			); err != nil {
+				return o.save(ctx, state, err)
+			}
+			state.Status = StatusCommitted
+		case StatusCommitted:
+			auditParams := &authorizer.AuditParams{}
+			if o.config.AuditParams != nil {
+				auditParams = o.config.AuditParams(appid)
+			}
+			auditID, err := api.SubmitAudit(ctx, auditParams)
+			if err != nil {
+				return o.save(ctx, state, err)
+			}
+			state.Status = StatusAuditing
+			state.AuditID = auditID
+			state.Reason, state.ScreenShot = "", ""
+		case StatusAuditing, StatusDelayed:
+			status, err := api.GetAuditStatus(ctx, state.AuditID)
+			if err != nil {
+				return o.save(ctx, state, err)
+			}
+			if !applyAuditStatus(state, status) {
+				return o.save(ctx, state, nil)
+			}
+		case StatusApproved:
+			if err := release(ctx, api); err != nil {
+				return o.save(ctx, state, err)
+			}
+			state.Status = StatusReleased
+		default:
+			// 已发布或审核被拒绝, 被拒绝的小程序需要调用 Reset 之后重新发布
+			return nil
+		}
+
+		if err := o.save(ctx, state, nil); err != nil {
+			return err
+		}
+	}
+}
+
+func release(ctx context.Context, api Api) error {
+	err := api.Release(ctx)
+	var weixinErr *utils.WeixinError
+	if errors.As(err, &weixinErr) && weixinErr.ErrCode == errCodeReleased {
+		return nil
+	}
+	return err
+}
+
+// 根据审核状态更新 State, 审核中或者状态没有变化返回 false
+func applyAuditStatus(state *State, status *authorizer.AuditStatus) bool {
+	switch status.Status {
+	case authorizer.AuditStatusSuccess:
+		state.Status = StatusApproved
+		state.Reason, state.ScreenShot = "", ""
+	case authorizer.AuditStatusFail:
+		state.Status = StatusRejected
+		state.Reason, state.ScreenShot = status.Reason, status.ScreenShot
+	case authorizer.AuditStatusDelay:
+		if state.Status == StatusDelayed && state.Reason == status.Reason {
+			return false
+		}
+		state.Status = StatusDelayed
+		state.Reason = status.Reason
+	case authorizer.AuditStatusUndo:
+		// 审核被撤回, 重新提交审核
+		state.Status = StatusCommitted
+		state.Reason, state.ScreenShot = "", ""
+	default:
+		return false
+	}
+	return true
+}
+
+// HandleEvent 处理授权小程序的审核结果推送, 审核通过之后立即发布
+// 非审核结果推送返回 false; 不属于本次发布或者不在审核中的小程序忽略
+func (o *Orchestrator) HandleEvent(ctx context.Context, appid string, event interface{}) (bool, error) {
+	status, ok := authorizer.AuditEventResult(event)
+	if !ok {
+		return false, nil
+	}
+
+	unlock := o.lock(appid)
+	defer unlock()
+
+	state, err := o.config.Store.Load(ctx, appid)
+	if err == ErrStateNotFound {
+		return true, nil
+	} else if err != nil {
+		return true, err
+	}
+	if state.Status != StatusAuditing && state.Status != StatusDelayed {
+		return true, nil
+	}
+
+	if !applyAuditStatus(state, status) {
+		return true, nil
+	}
+	if state.Status == StatusApproved {
+		if err := release(ctx, o.config.Api(appid)); err != nil {
+			return true, o.save(ctx, state, err)
+		}
+		state.Status = StatusReleased
+	}
+	return true, o.save(ctx, state, nil)
+}
+
+// Reset 将小程序重置为未开始(例如审核被拒绝并修改之后), 下次 Run 时重新上传代码并提交审核
+func (o *Orchestrator) Reset(ctx context.Context, appid string) error {
+	unlock := o.lock(appid)
+	defer unlock()
+
+	state, err := o.config.Store.Load(ctx, appid)
+	if err == ErrStateNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	state.Status = StatusPending
+	state.AuditID = 0
+	state.Reason, state.ScreenShot = "", ""
+	return o.save(ctx, state, nil)
+}
+
+// Progress 发布进度
+type Progress struct {
+	Total    int
+	Counts   map[string]int // 各状态的小程序数量
+	Rejected []*State       // 审核被拒绝的小程序
+	Failed   []*State       // 最近一次操作出错的小程序
+}
+
+// Done 所有小程序都已发布或者被拒绝
+func (p *Progress) Done() bool {
+	return p.Counts[StatusReleased]+p.Counts[StatusRejected] == p.Total
+}
+
+func (o *Orchestrator) Progress(ctx context.Context) (*Progress, error) {
+	states, err := o.config.Store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := &Progress{Total: len(states), Counts: map[string]int{}}
+	for _, state := range states {
+		progress.Counts[state.Status]++
+		if state.Status == StatusRejected {
+			progress.Rejected = append(progress.Rejected, state)
+		}
+		if state.Error != "" {
+			progress.Failed = append(progress.Failed, state)
+		}
+	}
+	return progress, nil
+}
@@ -0,0 +1,183 @@
+package codedeploy
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/lixinio/weixin/utils"
+	"github.com/lixinio/weixin/utils/memcache"
+	"github.com/lixinio/weixin/weixin/authorizer"
+	"github.com/lixinio/weixin/weixin/server_api"
+	"github.com/lixinio/weixin/wxopen"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	_ Api            = (*authorizer.AuthorizerApi)(nil)
+	_ TemplateLister = (*wxopen.WxOpen)(nil)
+)
+
+type fakeTemplates []wxopen.Template
+
+func (t fakeTemplates) GetTemplateList(ctx context.Context) ([]wxopen.Template, error) {
+	return t, nil
+}
+
+// 模拟授权小程序, 审核状态默认审核中
+type fakeApi struct {
+	mutex       sync.Mutex
+	commitErr   map[string]error
+	auditStatus map[string]int
+	commits     map[string]int
+	releases    map[string]int
+}
+
+func newFakeApi() *fakeApi {
+	return &fakeApi{
+		commitErr:   map[string]error{},
+		auditStatus: map[string]int{},
+		commits:     map[string]int{},
+		releases:    map[string]int{},
+	}
+}
+
+type fakeAppApi struct {
+	*fakeApi
+	appid string
+}
+
+func (api *fakeAppApi) Commit(
+	ctx context.Context, templateID int32, extJson, userVersion, userDesc string,
+) error {
+	api.mutex.Lock()
+	defer api.mutex.Unlock()
+	if err := api.commitErr[api.appid]; err != nil {
+		return err
+	}
+	api.commits[api.appid]++
+	return nil
+}
+
+func (api *fakeAppApi) SubmitAudit(
+	ctx context.Context, auditParams *authorizer.AuditParams,
+) (int32, error) {
+	return int32(len(api.appid)), nil
+}
+
+func (api *fakeAppApi) GetAuditStatus(ctx context.Context, auditID int32) (*authorizer.AuditStatus, error) {
+	api.mutex.Lock()
+	defer api.mutex.Unlock()
+	status, ok := api.auditStatus[api.appid]
+	if !ok {
+		status = authorizer.AuditStatusAuditing
+	}
+	return &authorizer.AuditStatus{AuditID: auditID, Status: status}, nil
+}
+
+func (api *fakeAppApi) Release(ctx context.Context) error {
+	api.mutex.Lock()
+	defer api.mutex.Unlock()
+	api.releases[api.appid]++
+	if api.releases[api.appid] > 1 {
+		return &utils.WeixinError{ErrCode: errCodeReleased, ErrMsg: "app is already released"}
+	}
+	return nil
+}
+
+func TestOrchestrator(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeApi()
+	fake.commitErr["wx_c"] = errors.New("commit fail")
+	store := NewCacheStore(memcache.NewMemcache(), nil, "v1.0.1", 0)
+
+	newOrchestrator := func() *Orchestrator {
+		return New(&Config{
+			Api: func(appid string) Api {
+				return &fakeAppApi{fakeApi: fake, appid: appid}
+			},
+			Store: store,
+			Templates: fakeTemplates{
+				{TemplateID: 1, UserVersion: "v1.0.0", CreateTime: 100},
+				{TemplateID: 2, UserVersion: "v1.0.1", CreateTime: 200},
+				{TemplateID: 3, UserVersion: "v1.0.2", CreateTime: 300, TemplateType: 1},
+			},
+			Concurrency: 2,
+		})
+	}
+
+	o := newOrchestrator()
+	appids := []string{"wx_a", "wx_b", "wx_c", "wx_d"}
+	progress, err := o.Run(ctx, appids)
+	require.Equal(t, nil, err)
+	require.Equal(t, 4, progress.Total)
+	require.Equal(t, 3, progress.Counts[StatusAuditing])
+	require.Equal(t, 1, progress.Counts[StatusPending])
+	require.Equal(t, 1, len(progress.Failed))
+	require.Equal(t, "commit fail", progress.Failed[0].Error)
+
+	state, err := store.Load(ctx, "wx_a")
+	require.Equal(t, nil, err)
+	require.Equal(t, int32(2), state.TemplateID)
+	require.Equal(t, "v1.0.1", state.UserVersion)
+	require.Equal(t, int32(4), state.AuditID)
+
+	// 审核结果推送
+	handled, err := o.HandleEvent(ctx, "wx_a", &server_api.EventWeappAuditSuccess{})
+	require.Equal(t, nil, err)
+	require.True(t, handled)
+	handled, err = o.HandleEvent(ctx, "wx_b", &server_api.EventWeappAuditFail{Reason: "类目不符"})
+	require.Equal(t, nil, err)
+	require.True(t, handled)
+	handled, err = o.HandleEvent(ctx, "wx_b", &server_api.EventSubscribe{})
+	require.Equal(t, nil, err)
+	require.False(t, handled)
+
+	// 重启之后继续, 遗漏推送的小程序主动查询审核状态
+	fake.commitErr["wx_c"] = nil
+	fake.auditStatus["wx_d"] = authorizer.AuditStatusSuccess
+	o = newOrchestrator()
+	progress, err = o.Run(ctx, appids)
+	require.Equal(t, nil, err)
+	require.Equal(t, 2, progress.Counts[StatusReleased])
+	require.Equal(t, 1, progress.Counts[StatusRejected])
+	require.Equal(t, 1, progress.Counts[StatusAuditing])
+	require.Equal(t, 0, len(progress.Failed))
+	require.Equal(t, "类目不符", progress.Rejected[0].Reason)
+	require.False(t, progress.Done())
+	require.Equal(t, 1, fake.commits["wx_a"])
+	require.Equal(t, 1, fake.releases["wx_a"])
+
+	// 已发布的重复推送不会再次发布
+	handled, err = o.HandleEvent(ctx, "wx_a", &server_api.EventWeappAuditSuccess{})
+	require.Equal(t, nil, err)
+	require.True(t, handled)
+	require.Equal(t, 1, fake.releases["wx_a"])
+
+	// 被拒绝的小程序重新发布
+	require.Equal(t, nil, o.Reset(ctx, "wx_b"))
+	fake.auditStatus["wx_b"] = authorizer.AuditStatusSuccess
+	fake.auditStatus["wx_c"] = authorizer.AuditStatusSuccess
+	progress, err = o.Run(ctx, appids)
+	require.Equal(t, nil, err)
+	require.Equal(t, 4, progress.Counts[StatusReleased])
+	require.True(t, progress.Done())
+	require.Equal(t, 2, fake.commits["wx_b"])
+}
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	_, err := store.Load(ctx, "wx_a")
+	require.Equal(t, ErrStateNotFound, err)
+
+	require.Equal(t, nil, store.Save(ctx, &State{Appid: "wx_b", Status: StatusAuditing}))
+	require.Equal(t, nil, store.Save(ctx, &State{Appid: "wx_a", Status: StatusPending}))
+	states, err := store.List(ctx)
+	require.Equal(t, nil, err)
+	require.Equal(t, 2, len(states))
+	require.Equal(t, "wx_a", states[0].Appid)
+	require.Equal(t, StatusAuditing, states[1].Status)
+}
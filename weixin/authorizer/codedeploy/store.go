@@ -0,0 +1,154 @@
+package codedeploy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lixinio/weixin/utils"
+	"github.com/lixinio/weixin/utils/memcache"
+)
+
+const (
+	defaultKeyPrefix = "weixin:codedeploy:"
+	defaultCacheTTL  = 30 * 24 * time.Hour
+)
+
+var ErrStateNotFound = errors.New("deploy state not found")
+
+// 小程序发布状态
+const (
+	StatusPending   = "pending"   // 未开始
+	StatusCommitted = "committed" // 已上传代码, 待提交审核
+	StatusAuditing  = "auditing"  // 审核中
+	StatusDelayed   = "delayed"   // 审核延后
+	StatusRejected  = "rejected"  // 审核被拒绝
+	StatusApproved  = "approved"  // 审核通过, 待发布
+	StatusReleased  = "released"  // 已发布
+)
+
+// State 单个小程序的发布状态
+type State struct {
+	Appid       string `json:"appid"`
+	TemplateID  int32  `json:"template_id"`
+	UserVersion string `json:"user_version"`
+	AuditID     int32  `json:"auditid,omitempty"`
+	Status      string `json:"status"`
+	Reason      string `json:"reason,omitempty"`     // 审核被拒绝或延后的原因
+	ScreenShot  string `json:"screenshot,omitempty"` // 审核被拒绝的截图 media_id, 用 | 分隔
+	Error       string `json:"error,omitempty"`      // 最近一次操作的错误, 成功之后清空
+	UpdatedAt   int64  `json:"updated_at"`
+}
+
+// Store 发布状态存储, 每个 Store 对应一次发布
+type Store interface {
+	Load(ctx context.Context, appid string) (*State, error) // 不存在返回 ErrStateNotFound
+	Save(ctx context.Context, state *State) error
+	List(ctx context.Context) ([]*State, error)
+}
+
+// NewMemoryStore 内存存储, 适用于测试或者单进程
+func NewMemoryStore() *CacheStore {
+	cache := memcache.NewMemcache()
+	return NewCacheStore(cache, cache, "memory", 0)
+}
+
+// CacheStore 基于 utils.Cache 的存储, 每个小程序保存一条状态, 另外维护一个 appid 列表
+type CacheStore struct {
+	cache     utils.Cache
+	mutex     sync.Mutex
+	locker    utils.Lock // 可选, 多进程写入时避免覆盖 appid 列表
+	keyPrefix string
+	ttl       time.Duration
+}
+
+// name 为发布名称(例如版本号), 不同的发布互不影响; ttl 为 0 时默认保存30天
+func NewCacheStore(cache utils.Cache, locker utils.Lock, name string, ttl time.Duration) *CacheStore {
+	if ttl <= 0 {
+		ttl = defaultCacheTTL
+	}
+	return &CacheStore{
+		cache:     cache,
+		locker:    locker,
+		keyPrefix: fmt.Sprintf("%s%s:", defaultKeyPrefix, name),
+		ttl:       ttl,
+	}
+}
+
+func (s *CacheStore) key(appid string) string {
+	return s.keyPrefix + appid
+}
+
+func (s *CacheStore) indexKey() string {
+	return s.keyPrefix + "appids"
+}
+
+func (s *CacheStore) Load(ctx context.Context, appid string) (*State, error) {
+	state := &State{}
+	exist, err := utils.CacheGetJSON(s.cache, s.key(appid), state)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, ErrStateNotFound
+	}
+	return state, nil
+}
+
+func (s *CacheStore) Save(ctx context.Context, state *State) error {
+	if err := utils.CacheSetJSON(s.cache, s.key(state.Appid), state, s.ttl); err != nil {
+		return err
+	}
+	return s.addIndex(state.Appid)
+}
+
+func (s *CacheStore) addIndex(appid string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	key := s.indexKey()
+	return utils.WithLock(s.locker, key+":lock", func() error {
+		appids, err := s.loadIndex()
+		if err != nil {
+			return err
+		}
+		for _, item := range appids {
+			if item == appid {
+				return nil
+			}
+		}
+		return utils.CacheSetJSON(s.cache, key, append(appids, appid), s.ttl)
+	})
+}
+
+func (s *CacheStore) loadIndex() ([]string, error) {
+	appids := []string{}
+	if _, err := utils.CacheGetJSON(s.cache, s.indexKey(), &appids); err != nil {
+		return nil, err
+	}
+	return appids, nil
+}
+
+func (s *CacheStore) List(ctx context.Context) ([]*State, error) {
+	appids, err := s.loadIndex()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(appids)
+
+	result := make([]*State, 0, len(appids))
+	for _, appid := range appids {
+		state, err := s.Load(ctx, appid)
+		if err == ErrStateNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, state)
+	}
+	return result, nil
+}
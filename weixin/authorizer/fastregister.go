@@ -0,0 +1,33 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/lixinio/weixin/utils"
+)
+
+const apiFastRegister = "/cgi-bin/account/fastregister"
+
+type FastRegisterResult struct {
+	utils.WeixinError
+	Appid             string `json:"appid"`              // 新创建小程序的 appid
+	AuthorizationCode string `json:"authorization_code"` // 新创建小程序的授权码, 用于 wxopen.QueryAuth
+	IsWxVerifySucc    bool   `json:"is_wx_verify_succ"`  // 复用公众号微信认证小程序是否成功
+	IsLinkSucc        bool   `json:"is_link_succ"`       // 小程序是否和公众号关联成功
+}
+
+/*
+复用公众号主体快速注册小程序
+使用公众号的授权, ticket 为公众号管理员在 wxopen.FastRegisterAuthUrl 页面确认之后回调地址中的参数
+https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/api/Official_Accounts/fast_registration_of_mini_program.html
+POST https://api.weixin.qq.com/cgi-bin/account/fastregister?access_token=ACCESS_TOKEN
+*/
+func (api *AuthorizerApi) FastRegister(ctx context.Context, ticket string) (*FastRegisterResult, error) {
+	result := &FastRegisterResult{}
+	if err := api.Client.HTTPPostJson(ctx, apiFastRegister, map[string]string{
+		"ticket": ticket,
+	}, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,82 @@
+package authorizer
+
+import (
+	"context"
+	"errors"
+
+	"github.com/lixinio/weixin/utils"
+)
+
+const (
+	apiCreateOpenAccount = "/cgi-bin/open/create"
+	apiBindOpenAccount   = "/cgi-bin/open/bind"
+	apiUnbindOpenAccount = "/cgi-bin/open/unbind"
+	apiGetOpenAccount    = "/cgi-bin/open/get"
+
+	ErrCodeOpenAccountNotBound = 89002 // 该公众号/小程序未绑定微信开放平台帐号
+)
+
+/*
+创建开放平台帐号并绑定公众号/小程序, 返回开放平台帐号 appid
+appid 为授权公众号或小程序的 appid, 该帐号的主体需要与第三方平台的主体一致
+https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/api/account/create.html
+POST https://api.weixin.qq.com/cgi-bin/open/create?access_token=ACCESS_TOKEN
+*/
+func (api *AuthorizerApi) CreateOpenAccount(ctx context.Context, appid string) (string, error) {
+	result := &struct {
+		utils.WeixinError
+		OpenAppid string `json:"open_appid"`
+	}{}
+	if err := api.Client.HTTPPostJson(ctx, apiCreateOpenAccount, map[string]string{
+		"appid": appid,
+	}, result); err != nil {
+		return "", err
+	}
+	return result.OpenAppid, nil
+}
+
+/*
+将公众号/小程序绑定到开放平台帐号下
+https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/api/account/bind.html
+POST https://api.weixin.qq.com/cgi-bin/open/bind?access_token=ACCESS_TOKEN
+*/
+func (api *AuthorizerApi) BindOpenAccount(ctx context.Context, appid, openAppid string) error {
+	return api.Client.HTTPPostJson(ctx, apiBindOpenAccount, map[string]string{
+		"appid":      appid,
+		"open_appid": openAppid,
+	}, nil)
+}
+
+/*
+将公众号/小程序从开放平台帐号下解绑
+https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/api/account/unbind.html
+POST https://api.weixin.qq.com/cgi-bin/open/unbind?access_token=ACCESS_TOKEN
+*/
+func (api *AuthorizerApi) UnbindOpenAccount(ctx context.Context, appid, openAppid string) error {
+	return api.Client.HTTPPostJson(ctx, apiUnbindOpenAccount, map[string]string{
+		"appid":      appid,
+		"open_appid": openAppid,
+	}, nil)
+}
+
+/*
+获取公众号/小程序所绑定的开放平台帐号, 未绑定时返回空字符串
+https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/api/account/get.html
+POST https://api.weixin.qq.com/cgi-bin/open/get?access_token=ACCESS_TOKEN
+*/
+func (api *AuthorizerApi) GetOpenAccount(ctx context.Context, appid string) (string, error) {
+	result := &struct {
+		utils.WeixinError
+		OpenAppid string `json:"open_appid"`
+	}{}
+	if err := api.Client.HTTPPostJson(ctx, apiGetOpenAccount, map[string]string{
+		"appid": appid,
+	}, result); err != nil {
+		weixinErr := &utils.WeixinError{}
+		if errors.As(err, &weixinErr) && weixinErr.ErrCode == ErrCodeOpenAccountNotBound {
+			return "", nil
+		}
+		return "", err
+	}
+	return result.OpenAppid, nil
+}
@@ -0,0 +1,110 @@
+package authorizer
+
+import (
+	"context"
+	"io"
+
+	"github.com/lixinio/weixin/utils"
+)
+
+const (
+	apiSetPrivacySetting    = "/cgi-bin/component/setprivacysetting"
+	apiGetPrivacySetting    = "/cgi-bin/component/getprivacysetting"
+	apiUploadPrivacyExtFile = "/cgi-bin/component/uploadprivacyextfile"
+)
+
+// 隐私协议版本
+const (
+	PrivacyVerCurrent = 1 // 现网版本
+	PrivacyVerDev     = 2 // 开发版
+)
+
+type PrivacyOwnerSetting struct {
+	ContactEmail         string `json:"contact_email,omitempty"`          // 信息收集方（开发者）的邮箱地址, 4 种联系方式至少要填一种
+	ContactPhone         string `json:"contact_phone,omitempty"`          // 信息收集方（开发者）的手机号
+	ContactQQ            string `json:"contact_qq,omitempty"`             // 信息收集方（开发者）的 qq 号
+	ContactWeixin        string `json:"contact_weixin,omitempty"`         // 信息收集方（开发者）的微信号
+	ExtFileMediaID       string `json:"ext_file_media_id,omitempty"`      // 自定义补充文件, UploadPrivacyExtFile 返回
+	NoticeMethod         string `json:"notice_method"`                    // 通知方式，指的是当开发者收集信息有变动时，通过该方式通知用户
+	StoreExpireTimestamp string `json:"store_expire_timestamp,omitempty"` // 存储期限，指的是开发者收集用户信息存储多久
+}
+
+type PrivacySetting struct {
+	PrivacyKey   string `json:"privacy_key"`             // 用户信息类型的英文名称
+	PrivacyText  string `json:"privacy_text"`            // 该用户信息类型的用途
+	PrivacyLabel string `json:"privacy_label,omitempty"` // 用户信息类型的中文名称, 仅查询返回
+}
+
+type SdkPrivacyInfo struct {
+	SdkName    string           `json:"sdk_name"`     // sdk 的名称
+	SdkBizName string           `json:"sdk_biz_name"` // sdk 提供方的主体名称
+	SdkList    []PrivacySetting `json:"sdk_list"`     // sdk 收集的信息描述
+}
+
+type SetPrivacySettingParams struct {
+	PrivacyVer         int                 `json:"privacy_ver,omitempty"` // 隐私协议版本 PrivacyVer*, 默认现网版本
+	OwnerSetting       PrivacyOwnerSetting `json:"owner_setting"`
+	SettingList        []PrivacySetting    `json:"setting_list"`
+	SdkPrivacyInfoList []SdkPrivacyInfo    `json:"sdk_privacy_info_list,omitempty"`
+}
+
+/*
+设置小程序用户隐私保护指引
+https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/api/privacy_config/set_privacy_setting.html
+POST https://api.weixin.qq.com/cgi-bin/component/setprivacysetting?access_token=ACCESS_TOKEN
+*/
+func (api *AuthorizerApi) SetPrivacySetting(ctx context.Context, params *SetPrivacySettingParams) error {
+	return api.Client.HTTPPostJson(ctx, apiSetPrivacySetting, params, nil)
+}
+
+type PrivacySettingResult struct {
+	utils.WeixinError
+	CodeExist    int                 `json:"code_exist"`   // 代码是否存在， 0 不存在， 1 存在
+	PrivacyList  []string            `json:"privacy_list"` // 代码检测出来的用户信息类型（privacy_key）
+	SettingList  []PrivacySetting    `json:"setting_list"` // 已设置的用户信息类型
+	UpdateTime   int64               `json:"update_time"`  // 更新时间
+	OwnerSetting PrivacyOwnerSetting `json:"owner_setting"`
+	PrivacyDesc  struct {
+		PrivacyDescList []struct {
+			PrivacyKey  string `json:"privacy_key"`
+			PrivacyDesc string `json:"privacy_desc"`
+		} `json:"privacy_desc_list"`
+	} `json:"privacy_desc"` // 用户信息类型对应的中英文描述
+	SdkPrivacyInfoList []SdkPrivacyInfo `json:"sdk_privacy_info_list"`
+}
+
+/*
+查询小程序用户隐私保护指引
+https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/api/privacy_config/get_privacy_setting.html
+POST https://api.weixin.qq.com/cgi-bin/component/getprivacysetting?access_token=ACCESS_TOKEN
+*/
+func (api *AuthorizerApi) GetPrivacySetting(ctx context.Context, privacyVer int) (*PrivacySettingResult, error) {
+	result := &PrivacySettingResult{}
+	if err := api.Client.HTTPPostJson(ctx, apiGetPrivacySetting, map[string]int{
+		"privacy_ver": privacyVer,
+	}, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+/*
+上传小程序用户隐私保护指引文件
+只支持 txt 文件, 大小不超过 100kb
+https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/api/privacy_config/upload_privacy_exfile.html
+POST https://api.weixin.qq.com/cgi-bin/component/uploadprivacyextfile?access_token=ACCESS_TOKEN
+*/
+func (api *AuthorizerApi) UploadPrivacyExtFile(
+	ctx context.Context, filename string, content io.Reader,
+) (string, error) {
+	result := &struct {
+		utils.WeixinError
+		ExtFileMediaID string `json:"ext_file_media_id"`
+	}{}
+	if err := api.Client.HttpFile(
+		ctx, apiUploadPrivacyExtFile, "file", filename, content, nil, result,
+	); err != nil {
+		return "", err
+	}
+	return result.ExtFileMediaID, nil
+}
@@ -0,0 +1,319 @@
+package card_api
+
+// Package card 微信卡券
+
+import (
+	"context"
+
+	"github.com/lixinio/weixin/utils"
+)
+
+const (
+	apiCreate   = "/card/create"
+	apiUpdate   = "/card/update"
+	apiGet      = "/card/get"
+	apiBatchGet = "/card/batchget"
+	apiDelete   = "/card/delete"
+)
+
+// 卡券类型
+const (
+	CardTypeGroupon       = "GROUPON"        // 团购券
+	CardTypeCash          = "CASH"           // 代金券
+	CardTypeDiscount      = "DISCOUNT"       // 折扣券
+	CardTypeGift          = "GIFT"           // 兑换券
+	CardTypeGeneralCoupon = "GENERAL_COUPON" // 优惠券
+	CardTypeMemberCard    = "MEMBER_CARD"    // 会员卡
+)
+
+// 码型
+const (
+	CodeTypeText        = "CODE_TYPE_TEXT"         // 文本
+	CodeTypeBarcode     = "CODE_TYPE_BARCODE"      // 一维码
+	CodeTypeQrcode      = "CODE_TYPE_QRCODE"       // 二维码
+	CodeTypeOnlyQrcode  = "CODE_TYPE_ONLY_QRCODE"  // 二维码无code显示
+	CodeTypeOnlyBarcode = "CODE_TYPE_ONLY_BARCODE" // 一维码无code显示
+	CodeTypeNone        = "CODE_TYPE_NONE"         // 不显示code和条形码类型
+)
+
+// 卡券状态
+const (
+	CardStatusNotVerify  = "CARD_STATUS_NOT_VERIFY"  // 待审核
+	CardStatusVerifyFail = "CARD_STATUS_VERIFY_FAIL" // 审核失败
+	CardStatusVerifyOk   = "CARD_STATUS_VERIFY_OK"   // 通过审核
+	CardStatusDelete     = "CARD_STATUS_DELETE"      // 卡券被商户删除
+	CardStatusDispatch   = "CARD_STATUS_DISPATCH"    // 在公众平台投放过的卡券
+)
+
+type CardApi struct {
+	*utils.Client
+}
+
+func NewApi(client *utils.Client) *CardApi {
+	return &CardApi{Client: client}
+}
+
+type DateInfo struct {
+	Type           string `json:"type"`                       // DATE_TYPE_FIX_TIME_RANGE 表示固定日期区间，DATE_TYPE_FIX_TERM 表示固定时长 （自领取后按天算）
+	BeginTimestamp int64  `json:"begin_timestamp,omitempty"`  // type为DATE_TYPE_FIX_TIME_RANGE时专用，表示起用时间
+	EndTimestamp   int64  `json:"end_timestamp,omitempty"`    // 表示结束时间
+	FixedTerm      int    `json:"fixed_term,omitempty"`       // type为DATE_TYPE_FIX_TERM时专用，表示自领取后多少天内有效
+	FixedBeginTerm int    `json:"fixed_begin_term,omitempty"` // type为DATE_TYPE_FIX_TERM时专用，表示自领取后多少天开始生效
+}
+
+type BaseInfo struct {
+	ID           string    `json:"id,omitempty"` // 获取卡券详情时返回
+	Status       string    `json:"status,omitempty"`
+	LogoUrl      string    `json:"logo_url,omitempty"`
+	BrandName    string    `json:"brand_name,omitempty"`
+	CodeType     string    `json:"code_type,omitempty"`
+	Title        string    `json:"title,omitempty"`
+	Color        string    `json:"color,omitempty"`
+	Notice       string    `json:"notice,omitempty"`
+	ServicePhone string    `json:"service_phone,omitempty"`
+	Description  string    `json:"description,omitempty"`
+	DateInfo     *DateInfo `json:"date_info,omitempty"`
+	Sku          *struct {
+		Quantity int `json:"quantity"`
+	} `json:"sku,omitempty"`
+	UseLimit                  int     `json:"use_limit,omitempty"`
+	GetLimit                  int     `json:"get_limit,omitempty"`
+	UseCustomCode             bool    `json:"use_custom_code,omitempty"`
+	BindOpenid                bool    `json:"bind_openid,omitempty"`
+	CanShare                  *bool   `json:"can_share,omitempty"`
+	CanGiveFriend             *bool   `json:"can_give_friend,omitempty"`
+	LocationIDList            []int64 `json:"location_id_list,omitempty"`
+	UseAllLocations           bool    `json:"use_all_locations,omitempty"`
+	CenterTitle               string  `json:"center_title,omitempty"`
+	CenterSubTitle            string  `json:"center_sub_title,omitempty"`
+	CenterUrl                 string  `json:"center_url,omitempty"`
+	CenterAppBrandUserName    string  `json:"center_app_brand_user_name,omitempty"`
+	CenterAppBrandPass        string  `json:"center_app_brand_pass,omitempty"`
+	CustomUrlName             string  `json:"custom_url_name,omitempty"`
+	CustomUrl                 string  `json:"custom_url,omitempty"`
+	CustomUrlSubTitle         string  `json:"custom_url_sub_title,omitempty"`
+	CustomAppBrandUserName    string  `json:"custom_app_brand_user_name,omitempty"`
+	CustomAppBrandPass        string  `json:"custom_app_brand_pass,omitempty"`
+	PromotionUrlName          string  `json:"promotion_url_name,omitempty"`
+	PromotionUrl              string  `json:"promotion_url,omitempty"`
+	PromotionUrlSubTitle      string  `json:"promotion_url_sub_title,omitempty"`
+	PromotionAppBrandUserName string  `json:"promotion_app_brand_user_name,omitempty"`
+	PromotionAppBrandPass     string  `json:"promotion_app_brand_pass,omitempty"`
+	Source                    string  `json:"source,omitempty"`
+}
+
+type AdvancedInfo struct {
+	UseCondition *struct {
+		AcceptCategory          string `json:"accept_category,omitempty"`
+		RejectCategory          string `json:"reject_category,omitempty"`
+		LeastCost               int    `json:"least_cost,omitempty"`
+		ObjectUseFor            string `json:"object_use_for,omitempty"`
+		CanUseWithOtherDiscount bool   `json:"can_use_with_other_discount"`
+	} `json:"use_condition,omitempty"`
+	Abstract *struct {
+		Abstract    string   `json:"abstract"`
+		IconUrlList []string `json:"icon_url_list"`
+	} `json:"abstract,omitempty"`
+	TextImageList []struct {
+		ImageUrl string `json:"image_url"`
+		Text     string `json:"text"`
+	} `json:"text_image_list,omitempty"`
+	TimeLimit []struct {
+		Type        string `json:"type"` // MONDAY ... SUNDAY / HOLIDAY
+		BeginHour   int    `json:"begin_hour,omitempty"`
+		BeginMinute int    `json:"begin_minute,omitempty"`
+		EndHour     int    `json:"end_hour,omitempty"`
+		EndMinute   int    `json:"end_minute,omitempty"`
+	} `json:"time_limit,omitempty"`
+	BusinessService []string `json:"business_service,omitempty"`
+}
+
+// CommonCard 各类卡券共有的字段
+type CommonCard struct {
+	BaseInfo     *BaseInfo     `json:"base_info"`
+	AdvancedInfo *AdvancedInfo `json:"advanced_info,omitempty"`
+}
+
+type Groupon struct {
+	CommonCard
+	DealDetail string `json:"deal_detail,omitempty"` // 团购券专用，团购详情
+}
+
+type Cash struct {
+	CommonCard
+	LeastCost  int `json:"least_cost,omitempty"`  // 代金券专用，表示起用金额（单位为分）,如果无起用门槛则填0
+	ReduceCost int `json:"reduce_cost,omitempty"` // 代金券专用，表示减免金额。（单位为分）
+}
+
+type Discount struct {
+	CommonCard
+	Discount int `json:"discount,omitempty"` // 折扣券专用，表示打折额度（百分比）。填30就是七折
+}
+
+type Gift struct {
+	CommonCard
+	Gift string `json:"gift,omitempty"` // 兑换券专用，填写兑换内容的名称
+}
+
+type GeneralCoupon struct {
+	CommonCard
+	DefaultDetail string `json:"default_detail,omitempty"` // 优惠券专用，填写优惠详情
+}
+
+type CustomField struct {
+	NameType string `json:"name_type,omitempty"` // FIELD_NAME_TYPE_LEVEL 等级 ...
+	Name     string `json:"name,omitempty"`
+	Url      string `json:"url,omitempty"`
+}
+
+type CustomCell struct {
+	Name             string `json:"name"`
+	Tips             string `json:"tips,omitempty"`
+	Url              string `json:"url,omitempty"`
+	AppBrandUserName string `json:"app_brand_user_name,omitempty"`
+	AppBrandPass     string `json:"app_brand_pass,omitempty"`
+}
+
+type MemberCard struct {
+	CommonCard
+	BackgroundPicUrl string       `json:"background_pic_url,omitempty"`
+	Prerogative      string       `json:"prerogative,omitempty"`   // 会员卡特权说明
+	AutoActivate     bool         `json:"auto_activate,omitempty"` // 设置为true时用户领取会员卡后系统自动将其激活，无需调用激活接口
+	WxActivate       bool         `json:"wx_activate,omitempty"`   // 设置为true时会员卡支持一键开卡
+	SupplyBonus      bool         `json:"supply_bonus"`            // 显示积分
+	BonusUrl         string       `json:"bonus_url,omitempty"`
+	SupplyBalance    bool         `json:"supply_balance"` // 是否支持储值
+	BalanceUrl       string       `json:"balance_url,omitempty"`
+	CustomField1     *CustomField `json:"custom_field1,omitempty"`
+	CustomField2     *CustomField `json:"custom_field2,omitempty"`
+	CustomField3     *CustomField `json:"custom_field3,omitempty"`
+	BonusCleared     string       `json:"bonus_cleared,omitempty"`
+	BonusRules       string       `json:"bonus_rules,omitempty"`
+	BalanceRules     string       `json:"balance_rules,omitempty"`
+	ActivateUrl      string       `json:"activate_url,omitempty"`
+	CustomCell1      *CustomCell  `json:"custom_cell1,omitempty"`
+	BonusRule        *struct {
+		CostMoneyUnit        int `json:"cost_money_unit,omitempty"`
+		IncreaseBonus        int `json:"increase_bonus,omitempty"`
+		MaxIncreaseBonus     int `json:"max_increase_bonus,omitempty"`
+		InitIncreaseBonus    int `json:"init_increase_bonus,omitempty"`
+		CostBonusUnit        int `json:"cost_bonus_unit,omitempty"`
+		ReduceMoney          int `json:"reduce_money,omitempty"`
+		LeastMoneyToUseBonus int `json:"least_money_to_use_bonus,omitempty"`
+		MaxReduceBonus       int `json:"max_reduce_bonus,omitempty"`
+	} `json:"bonus_rule,omitempty"`
+	Discount int `json:"discount,omitempty"` // 折扣，该会员卡享受的折扣优惠,填10就是九折
+}
+
+// Card 卡券, 根据 CardType 填充对应类型的字段
+type Card struct {
+	CardType      string         `json:"card_type,omitempty"`
+	Groupon       *Groupon       `json:"groupon,omitempty"`
+	Cash          *Cash          `json:"cash,omitempty"`
+	Discount      *Discount      `json:"discount,omitempty"`
+	Gift          *Gift          `json:"gift,omitempty"`
+	GeneralCoupon *GeneralCoupon `json:"general_coupon,omitempty"`
+	MemberCard    *MemberCard    `json:"member_card,omitempty"`
+}
+
+/*
+创建卡券
+See: https://developers.weixin.qq.com/doc/offiaccount/Cards_and_Offer/Create_a_Coupon_Voucher_or_Card.html
+POST https://api.weixin.qq.com/card/create?access_token=ACCESS_TOKEN
+*/
+func (api *CardApi) Create(ctx context.Context, card *Card) (string, error) {
+	param := &struct {
+		Card *Card `json:"card"`
+	}{card}
+
+	result := &struct {
+		utils.WeixinError
+		CardID string `json:"card_id"`
+	}{}
+	if err := api.Client.HTTPPostJson(ctx, apiCreate, param, result); err != nil {
+		return "", err
+	}
+	return result.CardID, nil
+}
+
+/*
+更改卡券信息
+card 只需要填充需要修改的类型和字段, 无需 CardType
+返回 是否提交审核，false为修改后不会重新提审，true为修改字段后重新提审，该卡券的状态变为审核中
+See: https://developers.weixin.qq.com/doc/offiaccount/Cards_and_Offer/Managing_Coupons_Vouchers_and_Cards.html#4
+POST https://api.weixin.qq.com/card/update?access_token=TOKEN
+*/
+func (api *CardApi) Update(ctx context.Context, cardID string, card *Card) (bool, error) {
+	param := &struct {
+		CardID string `json:"card_id"`
+		*Card
+	}{cardID, card}
+
+	result := &struct {
+		utils.WeixinError
+		SendCheck bool `json:"send_check"`
+	}{}
+	if err := api.Client.HTTPPostJson(ctx, apiUpdate, param, result); err != nil {
+		return false, err
+	}
+	return result.SendCheck, nil
+}
+
+type cardIDParam struct {
+	CardID string `json:"card_id"`
+}
+
+/*
+查看卡券详情
+See: https://developers.weixin.qq.com/doc/offiaccount/Cards_and_Offer/Managing_Coupons_Vouchers_and_Cards.html#1
+POST https://api.weixin.qq.com/card/get?access_token=TOKEN
+*/
+func (api *CardApi) Get(ctx context.Context, cardID string) (*Card, error) {
+	result := &struct {
+		utils.WeixinError
+		Card *Card `json:"card"`
+	}{}
+	if err := api.Client.HTTPPostJson(ctx, apiGet, &cardIDParam{cardID}, result); err != nil {
+		return nil, err
+	}
+	return result.Card, nil
+}
+
+type CardIDList struct {
+	utils.WeixinError
+	CardIDList []string `json:"card_id_list"`
+	TotalNum   int      `json:"total_num"`
+}
+
+/*
+批量查询卡券列表
+count 需要查询的卡片的数量（数量最大50）
+statusList 支持开发者拉出指定状态的卡券列表, 为空表示全部
+See: https://developers.weixin.qq.com/doc/offiaccount/Cards_and_Offer/Managing_Coupons_Vouchers_and_Cards.html#3
+POST https://api.weixin.qq.com/card/batchget?access_token=TOKEN
+*/
+func (api *CardApi) BatchGet(
+	ctx context.Context, offset, count int, statusList []string,
+) (*CardIDList, error) {
+	param := &struct {
+		Offset     int      `json:"offset"`
+		Count      int      `json:"count"`
+		StatusList []string `json:"status_list,omitempty"`
+	}{offset, count, statusList}
+
+	result := &CardIDList{}
+	if err := api.Client.HTTPPostJson(ctx, apiBatchGet, param, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+/*
+删除卡券
+删除卡券接口允许商户删除任意一类卡券。删除卡券后，该卡券对应已生成的领取用二维码、添加到卡包JS API均会失效
+See: https://developers.weixin.qq.com/doc/offiaccount/Cards_and_Offer/Managing_Coupons_Vouchers_and_Cards.html#7
+POST https://api.weixin.qq.com/card/delete?access_token=TOKEN
+*/
+func (api *CardApi) Delete(ctx context.Context, cardID string) error {
+	return api.Client.HTTPPostJson(ctx, apiDelete, &cardIDParam{cardID}, nil)
+}
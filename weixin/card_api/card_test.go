@@ -0,0 +1,73 @@
+package card_api
+
+import (
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/lixinio/weixin/test"
+	"github.com/lixinio/weixin/utils/redis"
+	"github.com/lixinio/weixin/weixin/authorizer"
+	"github.com/lixinio/weixin/weixin/official_account"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	_ WxCardTicketGetter = (*official_account.OfficialAccount)(nil)
+	_ WxCardTicketGetter = (*authorizer.Authorizer)(nil)
+)
+
+func sha1Sorted(datas ...string) string {
+	sort.Strings(datas)
+	return fmt.Sprintf("%x", sha1.Sum([]byte(strings.Join(datas, ""))))
+}
+
+func TestSign(t *testing.T) {
+	ext := signCardExt("ticket", "card_id", "code", "openid", "1404896688", "nonce")
+	require.Equal(t, "1404896688", ext.Timestamp)
+	require.Equal(t, "nonce", ext.NonceStr)
+	require.Equal(t, "code", ext.Code)
+	require.Equal(t, "openid", ext.OpenID)
+	require.Equal(
+		t, sha1Sorted("ticket", "1404896688", "card_id", "code", "openid", "nonce"),
+		ext.Signature,
+	)
+
+	// 未指定 code/openid 时以空串参与签名
+	ext = signCardExt("ticket", "card_id", "", "", "1404896688", "nonce")
+	require.Equal(t, sha1Sorted("ticket", "1404896688", "card_id", "nonce"), ext.Signature)
+
+	config := signChooseCard(
+		"ticket", "appid", "shop", CardTypeCash, "card_id", "1404896688", "nonce",
+	)
+	require.Equal(t, "SHA1", config.SignType)
+	require.Equal(t, "shop", config.ShopID)
+	require.Equal(t, CardTypeCash, config.CardType)
+	require.Equal(
+		t,
+		sha1Sorted("ticket", "appid", "shop", "1404896688", "nonce", "card_id", CardTypeCash),
+		config.CardSign,
+	)
+}
+
+func TestCard(t *testing.T) {
+	redis := redis.NewRedis(&redis.Config{RedisUrl: test.CacheUrl})
+	officialAccount := official_account.New(redis, redis, &official_account.Config{
+		Appid:  test.OfficialAccountAppid,
+		Secret: test.OfficialAccountSecret,
+	})
+	ctx := context.Background()
+	cardApi := NewApi(officialAccount.Client)
+
+	list, err := cardApi.BatchGet(ctx, 0, 10, nil)
+	require.Equal(t, nil, err)
+
+	for _, cardID := range list.CardIDList {
+		card, err := cardApi.Get(ctx, cardID)
+		require.Equal(t, nil, err)
+		require.NotEmpty(t, card.CardType)
+	}
+}
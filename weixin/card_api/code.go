@@ -0,0 +1,124 @@
+package card_api
+
+import (
+	"context"
+
+	"github.com/lixinio/weixin/utils"
+)
+
+const (
+	apiCodeDecrypt     = "/card/code/decrypt"
+	apiCodeGet         = "/card/code/get"
+	apiCodeConsume     = "/card/code/consume"
+	apiCodeUnavailable = "/card/code/unavailable"
+)
+
+/*
+Code解码接口
+用户从卡包跳转到商户网页时, 会带上加密的 encrypt_code, 需要调用本接口解码获取真实 code
+See: https://developers.weixin.qq.com/doc/offiaccount/Cards_and_Offer/Redeeming_a_coupon_voucher_or_card.html#4
+POST https://api.weixin.qq.com/card/code/decrypt?access_token=TOKEN
+*/
+func (api *CardApi) DecryptCode(ctx context.Context, encryptCode string) (string, error) {
+	param := &struct {
+		EncryptCode string `json:"encrypt_code"`
+	}{encryptCode}
+
+	result := &struct {
+		utils.WeixinError
+		Code string `json:"code"`
+	}{}
+	if err := api.Client.HTTPPostJson(ctx, apiCodeDecrypt, param, result); err != nil {
+		return "", err
+	}
+	return result.Code, nil
+}
+
+// 用户卡券状态
+const (
+	UserCardStatusNormal      = "NORMAL"       // 正常
+	UserCardStatusConsumed    = "CONSUMED"     // 已核销
+	UserCardStatusExpire      = "EXPIRE"       // 已过期
+	UserCardStatusGifting     = "GIFTING"      // 转赠中
+	UserCardStatusGiftTimeout = "GIFT_TIMEOUT" // 转赠超时
+	UserCardStatusDelete      = "DELETE"       // 已删除
+	UserCardStatusUnavailable = "UNAVAILABLE"  // 已失效
+)
+
+type CodeInfo struct {
+	utils.WeixinError
+	Card struct {
+		CardID    string `json:"card_id"`
+		BeginTime int64  `json:"begin_time"`
+		EndTime   int64  `json:"end_time"`
+	} `json:"card"`
+	OpenID         string `json:"openid"`
+	CanConsume     bool   `json:"can_consume"`
+	UserCardStatus string `json:"user_card_status"`
+}
+
+/*
+查询Code接口
+See: https://developers.weixin.qq.com/doc/offiaccount/Cards_and_Offer/Redeeming_a_coupon_voucher_or_card.html#1
+POST https://api.weixin.qq.com/card/code/get?access_token=TOKEN
+*/
+func (api *CardApi) GetCode(
+	ctx context.Context, cardID, code string, checkConsume bool,
+) (*CodeInfo, error) {
+	param := &struct {
+		CardID       string `json:"card_id,omitempty"`
+		Code         string `json:"code"`
+		CheckConsume bool   `json:"check_consume"`
+	}{cardID, code, checkConsume}
+
+	result := &CodeInfo{}
+	if err := api.Client.HTTPPostJson(ctx, apiCodeGet, param, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type ConsumeResult struct {
+	utils.WeixinError
+	Card struct {
+		CardID string `json:"card_id"`
+	} `json:"card"`
+	OpenID string `json:"openid"`
+}
+
+/*
+核销Code接口
+cardID 卡券ID。创建卡券时use_custom_code填写true时必填。非自定义Code不必填写
+See: https://developers.weixin.qq.com/doc/offiaccount/Cards_and_Offer/Redeeming_a_coupon_voucher_or_card.html#2
+POST https://api.weixin.qq.com/card/code/consume?access_token=TOKEN
+*/
+func (api *CardApi) ConsumeCode(
+	ctx context.Context, cardID, code string,
+) (*ConsumeResult, error) {
+	param := &struct {
+		Code   string `json:"code"`
+		CardID string `json:"card_id,omitempty"`
+	}{code, cardID}
+
+	result := &ConsumeResult{}
+	if err := api.Client.HTTPPostJson(ctx, apiCodeConsume, param, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+/*
+设置卡券失效接口
+See: https://developers.weixin.qq.com/doc/offiaccount/Cards_and_Offer/Managing_Coupons_Vouchers_and_Cards.html#8
+POST https://api.weixin.qq.com/card/code/unavailable?access_token=TOKEN
+*/
+func (api *CardApi) UnavailableCode(
+	ctx context.Context, cardID, code, reason string,
+) error {
+	param := &struct {
+		Code   string `json:"code"`
+		CardID string `json:"card_id,omitempty"`
+		Reason string `json:"reason,omitempty"`
+	}{code, cardID, reason}
+	return api.Client.HTTPPostJson(ctx, apiCodeUnavailable, param, nil)
+}
@@ -0,0 +1,124 @@
+package card_api
+
+import (
+	"context"
+
+	"github.com/lixinio/weixin/utils"
+)
+
+const (
+	apiMemberCardActivate    = "/card/membercard/activate"
+	apiMemberCardUpdateUser  = "/card/membercard/updateuser"
+	apiMemberCardUserInfoGet = "/card/membercard/userinfo/get"
+)
+
+type MemberCardActivateParam struct {
+	MembershipNumber      string `json:"membership_number"`                  // 会员卡编号
+	Code                  string `json:"code"`                               // 领取会员卡用户获得的code
+	CardID                string `json:"card_id,omitempty"`                  // 卡券ID,自定义code卡券必填
+	BackgroundPicUrl      string `json:"background_pic_url,omitempty"`       // 商家自定义会员卡背景图
+	ActivateBeginTime     int64  `json:"activate_begin_time,omitempty"`      // 激活后的有效起始时间
+	ActivateEndTime       int64  `json:"activate_end_time,omitempty"`        // 激活后的有效截至时间
+	InitBonus             int    `json:"init_bonus,omitempty"`               // 初始积分
+	InitBonusRecord       string `json:"init_bonus_record,omitempty"`        // 积分同步说明
+	InitBalance           int    `json:"init_balance,omitempty"`             // 初始余额
+	InitCustomFieldValue1 string `json:"init_custom_field_value1,omitempty"` // 创建时字段custom_field1定义类型的初始值
+	InitCustomFieldValue2 string `json:"init_custom_field_value2,omitempty"` // 创建时字段custom_field2定义类型的初始值
+	InitCustomFieldValue3 string `json:"init_custom_field_value3,omitempty"` // 创建时字段custom_field3定义类型的初始值
+}
+
+/*
+激活会员卡
+See: https://developers.weixin.qq.com/doc/offiaccount/Cards_and_Offer/Membership_Cards/Create_a_membership_card.html#6
+POST https://api.weixin.qq.com/card/membercard/activate?access_token=TOKEN
+*/
+func (api *CardApi) ActivateMemberCard(ctx context.Context, param *MemberCardActivateParam) error {
+	return api.Client.HTTPPostJson(ctx, apiMemberCardActivate, param, nil)
+}
+
+type MemberCardUpdateUserParam struct {
+	Code              string `json:"code"`
+	CardID            string `json:"card_id"`
+	BackgroundPicUrl  string `json:"background_pic_url,omitempty"`
+	Bonus             *int   `json:"bonus,omitempty"`               // 需要设置的积分全量值，传入的数值会直接显示
+	AddBonus          int    `json:"add_bonus,omitempty"`           // 本次积分变动值，传负数代表减少
+	RecordBonus       string `json:"record_bonus,omitempty"`        // 商家自定义积分消耗记录
+	Balance           *int   `json:"balance,omitempty"`             // 需要设置的余额全量值
+	AddBalance        int    `json:"add_balance,omitempty"`         // 本次余额变动值，传负数代表减少
+	RecordBalance     string `json:"record_balance,omitempty"`      // 商家自定义金额消耗记录
+	CustomFieldValue1 string `json:"custom_field_value1,omitempty"` // 创建时字段custom_field1定义类型的最新数值
+	CustomFieldValue2 string `json:"custom_field_value2,omitempty"`
+	CustomFieldValue3 string `json:"custom_field_value3,omitempty"`
+	NotifyOptional    *struct {
+		IsNotifyBonus        bool `json:"is_notify_bonus"`
+		IsNotifyBalance      bool `json:"is_notify_balance"`
+		IsNotifyCustomField1 bool `json:"is_notify_custom_field1"`
+		IsNotifyCustomField2 bool `json:"is_notify_custom_field2"`
+		IsNotifyCustomField3 bool `json:"is_notify_custom_field3"`
+	} `json:"notify_optional,omitempty"`
+}
+
+type MemberCardUpdateUserResult struct {
+	utils.WeixinError
+	ResultBonus   int    `json:"result_bonus"`   // 当前用户积分总额
+	ResultBalance int    `json:"result_balance"` // 当前用户预存总金额
+	OpenID        string `json:"openid"`
+}
+
+/*
+更新会员信息
+See: https://developers.weixin.qq.com/doc/offiaccount/Cards_and_Offer/Membership_Cards/Manage_Member_Card.html#2
+POST https://api.weixin.qq.com/card/membercard/updateuser?access_token=TOKEN
+*/
+func (api *CardApi) UpdateMemberCardUser(
+	ctx context.Context, param *MemberCardUpdateUserParam,
+) (*MemberCardUpdateUserResult, error) {
+	result := &MemberCardUpdateUserResult{}
+	if err := api.Client.HTTPPostJson(ctx, apiMemberCardUpdateUser, param, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type MemberCardUserInfo struct {
+	utils.WeixinError
+	OpenID           string `json:"openid"`
+	Nickname         string `json:"nickname"`
+	MembershipNumber string `json:"membership_number"`
+	Bonus            int    `json:"bonus"`
+	Balance          int    `json:"balance"`
+	Sex              string `json:"sex"`
+	UserInfo         struct {
+		CommonFieldList []struct {
+			Name  string `json:"name"`
+			Value string `json:"value"`
+		} `json:"common_field_list"`
+		CustomFieldList []struct {
+			Name      string   `json:"name"`
+			Value     string   `json:"value"`
+			ValueList []string `json:"value_list"`
+		} `json:"custom_field_list"`
+	} `json:"user_info"`
+	UserCardStatus string `json:"user_card_status"`
+	HasActive      bool   `json:"has_active"`
+}
+
+/*
+拉取会员信息
+See: https://developers.weixin.qq.com/doc/offiaccount/Cards_and_Offer/Membership_Cards/Manage_Member_Card.html#1
+POST https://api.weixin.qq.com/card/membercard/userinfo/get?access_token=TOKEN
+*/
+func (api *CardApi) GetMemberCardUserInfo(
+	ctx context.Context, cardID, code string,
+) (*MemberCardUserInfo, error) {
+	param := &struct {
+		CardID string `json:"card_id"`
+		Code   string `json:"code"`
+	}{cardID, code}
+
+	result := &MemberCardUserInfo{}
+	if err := api.Client.HTTPPostJson(ctx, apiMemberCardUserInfoGet, param, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
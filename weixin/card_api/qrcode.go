@@ -0,0 +1,122 @@
+package card_api
+
+import (
+	"context"
+	"errors"
+
+	"github.com/lixinio/weixin/utils"
+)
+
+const (
+	apiQrcodeCreate      = "/card/qrcode/create"
+	apiLandingPageCreate = "/card/landingpage/create"
+)
+
+const (
+	QrActionCard         = "QR_CARD"          // 单张卡券
+	QrActionMultipleCard = "QR_MULTIPLE_CARD" // 多张卡券
+)
+
+var ErrNoCard = errors.New("no card")
+
+type QrcodeCard struct {
+	CardID       string `json:"card_id"`
+	Code         string `json:"code,omitempty"`           // 卡券Code码,use_custom_code字段为true的卡券必须填写
+	OpenID       string `json:"openid,omitempty"`         // 指定领取者的openid，只有该用户能领取
+	IsUniqueCode bool   `json:"is_unique_code,omitempty"` // 指定下发二维码，生成的二维码随机分配一个code，领取后不可再次扫描
+	OuterStr     string `json:"outer_str,omitempty"`      // 领取场景值，用于领取渠道的数据统计
+}
+
+type QrcodeResult struct {
+	utils.WeixinError
+	Ticket        string `json:"ticket"`
+	ExpireSeconds int    `json:"expire_seconds"`
+	Url           string `json:"url"`
+	ShowQrcodeUrl string `json:"show_qrcode_url"`
+}
+
+/*
+创建卡券二维码
+expireSeconds 指定二维码的有效时间，范围是60 ~ 1800秒。不填默认为365天有效
+cards 为一张时使用 QR_CARD, 多张(最多5张)时使用 QR_MULTIPLE_CARD
+See: https://developers.weixin.qq.com/doc/offiaccount/Cards_and_Offer/Distributing_Coupons_Vouchers_and_Cards.html#0
+POST https://api.weixin.qq.com/card/qrcode/create?access_token=TOKEN
+*/
+func (api *CardApi) CreateQrcode(
+	ctx context.Context, expireSeconds int, cards ...*QrcodeCard,
+) (*QrcodeResult, error) {
+	type actionInfo struct {
+		Card         *QrcodeCard `json:"card,omitempty"`
+		MultipleCard *struct {
+			CardList []*QrcodeCard `json:"card_list"`
+		} `json:"multiple_card,omitempty"`
+	}
+	param := &struct {
+		ActionName    string     `json:"action_name"`
+		ExpireSeconds int        `json:"expire_seconds,omitempty"`
+		ActionInfo    actionInfo `json:"action_info"`
+	}{ExpireSeconds: expireSeconds}
+
+	if len(cards) == 0 {
+		return nil, ErrNoCard
+	} else if len(cards) == 1 {
+		param.ActionName = QrActionCard
+		param.ActionInfo.Card = cards[0]
+	} else {
+		param.ActionName = QrActionMultipleCard
+		param.ActionInfo.MultipleCard = &struct {
+			CardList []*QrcodeCard `json:"card_list"`
+		}{cards}
+	}
+
+	result := &QrcodeResult{}
+	if err := api.Client.HTTPPostJson(ctx, apiQrcodeCreate, param, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// 投放页面的场景值
+const (
+	LandingPageSceneIvr     = "SCENE_IVR"     // 自动回复
+	LandingPageSceneMenu    = "SCENE_MENU"    // 自定义菜单
+	LandingPageSceneQrcode  = "SCENE_QRCODE"  // 二维码
+	LandingPageSceneArticle = "SCENE_ARTICLE" // 公众号文章
+	LandingPageSceneH5      = "SCENE_H5"      // h5页面
+	LandingPageSceneMoments = "SCENE_MOMENTS" // 朋友圈
+	LandingPageSceneOthers  = "SCENE_OTHERS"  // 其他
+)
+
+type LandingPage struct {
+	Banner   string             `json:"banner"`     // 页面的banner图片链接
+	Title    string             `json:"page_title"` // 页面的title
+	CanShare bool               `json:"can_share"`  // 页面是否可以分享
+	Scene    string             `json:"scene"`      // 投放页面的场景值
+	CardList []*LandingPageCard `json:"card_list"`
+}
+
+type LandingPageCard struct {
+	CardID   string `json:"card_id"`
+	ThumbUrl string `json:"thumb_url"` // 缩略图url
+}
+
+type LandingPageResult struct {
+	utils.WeixinError
+	Url    string `json:"url"`
+	PageID int64  `json:"page_id"`
+}
+
+/*
+创建货架接口
+See: https://developers.weixin.qq.com/doc/offiaccount/Cards_and_Offer/Distributing_Coupons_Vouchers_and_Cards.html#3
+POST https://api.weixin.qq.com/card/landingpage/create?access_token=$TOKEN
+*/
+func (api *CardApi) CreateLandingPage(
+	ctx context.Context, page *LandingPage,
+) (*LandingPageResult, error) {
+	result := &LandingPageResult{}
+	if err := api.Client.HTTPPostJson(ctx, apiLandingPageCreate, page, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
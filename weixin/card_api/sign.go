@@ -0,0 +1,100 @@
+package card_api
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/lixinio/weixin/utils"
+)
+
+// WxCardTicketGetter 获取 wxcard_ticket (api_ticket)
+// official_account.OfficialAccount 和 authorizer.Authorizer 均已实现
+type WxCardTicketGetter interface {
+	GetWxCardApiTicket(ctx context.Context) (string, error)
+}
+
+// CardExt wx.addCard 中 cardExt 字段, 需 json 序列化后传入
+// https://developers.weixin.qq.com/doc/offiaccount/OA_Web_Apps/JS-SDK.html#53
+type CardExt struct {
+	Code                string `json:"code,omitempty"`
+	OpenID              string `json:"openid,omitempty"`
+	Timestamp           string `json:"timestamp"`
+	NonceStr            string `json:"nonce_str"`
+	FixedBeginTimestamp int64  `json:"fixed_begin_timestamp,omitempty"`
+	OuterStr            string `json:"outer_str,omitempty"`
+	Signature           string `json:"signature"`
+}
+
+// 生成 wx.addCard 需要的 cardExt, code/openid 非必填
+// 签名: 将 api_ticket、timestamp、card_id、code、openid、nonce_str 字典序排序后 sha1
+// https://developers.weixin.qq.com/doc/offiaccount/OA_Web_Apps/JS-SDK.html#65
+func GetCardExt(
+	ctx context.Context, getter WxCardTicketGetter, cardID, code, openID string,
+) (*CardExt, error) {
+	ticket, err := getter.GetWxCardApiTicket(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return signCardExt(
+		ticket, cardID, code, openID,
+		strconv.FormatInt(time.Now().Unix(), 10), utils.GetRandString(16),
+	), nil
+}
+
+func signCardExt(ticket, cardID, code, openID, timestamp, nonceStr string) *CardExt {
+	return &CardExt{
+		Code:      code,
+		OpenID:    openID,
+		Timestamp: timestamp,
+		NonceStr:  nonceStr,
+		Signature: utils.CalcSignature(ticket, timestamp, cardID, code, openID, nonceStr),
+	}
+}
+
+// ChooseCardConfig wx.chooseCard 参数
+// https://developers.weixin.qq.com/doc/offiaccount/OA_Web_Apps/JS-SDK.html#54
+type ChooseCardConfig struct {
+	ShopID    string `json:"shopId"`
+	CardType  string `json:"cardType"`
+	CardID    string `json:"cardId"`
+	Timestamp string `json:"timestamp"`
+	NonceStr  string `json:"nonceStr"`
+	SignType  string `json:"signType"`
+	CardSign  string `json:"cardSign"`
+}
+
+// 生成 wx.chooseCard 需要的参数, shopID/cardType/cardID 非必填
+// 签名: 将 api_ticket、app_id、location_id、timestamp、nonce_str、card_id、card_type 字典序排序后 sha1
+// https://developers.weixin.qq.com/doc/offiaccount/OA_Web_Apps/JS-SDK.html#54
+func GetChooseCardConfig(
+	ctx context.Context, getter WxCardTicketGetter,
+	appID, shopID, cardType, cardID string,
+) (*ChooseCardConfig, error) {
+	ticket, err := getter.GetWxCardApiTicket(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return signChooseCard(
+		ticket, appID, shopID, cardType, cardID,
+		strconv.FormatInt(time.Now().Unix(), 10), utils.GetRandString(16),
+	), nil
+}
+
+func signChooseCard(
+	ticket, appID, shopID, cardType, cardID, timestamp, nonceStr string,
+) *ChooseCardConfig {
+	return &ChooseCardConfig{
+		ShopID:    shopID,
+		CardType:  cardType,
+		CardID:    cardID,
+		Timestamp: timestamp,
+		NonceStr:  nonceStr,
+		SignType:  "SHA1",
+		CardSign: utils.CalcSignature(
+			ticket, appID, shopID, timestamp, nonceStr, cardID, cardType,
+		),
+	}
+}
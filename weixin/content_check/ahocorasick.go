@@ -0,0 +1,98 @@
+package content_check
+
+import (
+	"unicode"
+)
+
+// keywordMatcher Aho-Corasick 自动机, 一次扫描匹配全部关键词, 忽略大小写
+type keywordMatcher struct {
+	nodes []acNode
+	words []string
+}
+
+type acNode struct {
+	next   map[rune]int
+	fail   int
+	output []int // 以当前节点结尾的关键词下标
+}
+
+func newKeywordMatcher(keywords []string) *keywordMatcher {
+	m := &keywordMatcher{nodes: []acNode{{next: map[rune]int{}}}}
+	for _, keyword := range keywords {
+		if keyword == "" {
+			continue
+		}
+		state := 0
+		for _, r := range keyword {
+			r = unicode.ToLower(r)
+			next, ok := m.nodes[state].next[r]
+			if !ok {
+				next = len(m.nodes)
+				m.nodes = append(m.nodes, acNode{next: map[rune]int{}})
+				m.nodes[state].next[r] = next
+			}
+			state = next
+		}
+		m.nodes[state].output = append(m.nodes[state].output, len(m.words))
+		m.words = append(m.words, keyword)
+	}
+
+	// 广度优先构建失败指针, 同时合并失败节点的输出
+	queue := []int{}
+	for _, child := range m.nodes[0].next {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		for r, child := range m.nodes[state].next {
+			queue = append(queue, child)
+			m.nodes[child].fail = m.transition(m.nodes[state].fail, r, state == 0)
+			m.nodes[child].output = append(
+				m.nodes[child].output, m.nodes[m.nodes[child].fail].output...,
+			)
+		}
+	}
+	return m
+}
+
+// transition 从 state 沿失败指针查找 r 的转移, root 表示 state 的父节点为根节点
+func (m *keywordMatcher) transition(state int, r rune, root bool) int {
+	if root {
+		return 0
+	}
+	for {
+		if next, ok := m.nodes[state].next[r]; ok {
+			return next
+		}
+		if state == 0 {
+			return 0
+		}
+		state = m.nodes[state].fail
+	}
+}
+
+func (m *keywordMatcher) empty() bool {
+	return len(m.words) == 0
+}
+
+// find 返回文本中出现的关键词(去重, 按首次出现的顺序)
+func (m *keywordMatcher) find(text string) []string {
+	if m.empty() {
+		return nil
+	}
+
+	var result []string
+	seen := map[int]bool{}
+	state := 0
+	for _, r := range text {
+		state = m.transition(state, unicode.ToLower(r), false)
+		for _, idx := range m.nodes[state].output {
+			if !seen[idx] {
+				seen[idx] = true
+				result = append(result, m.words[idx])
+			}
+		}
+	}
+	return result
+}
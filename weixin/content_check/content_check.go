@@ -34,14 +34,17 @@ type MsgCheckResult struct {
 		Suggest string // 建议, 有risky、pass、review三种值
 		Label   int64  // 命中标签枚举值, 如100 正常; 10001 广告 ...
 	} // 综合结果
-	Detail []struct {
-		Strategy string
-		ErrCode  int64
-		Suggest  string
-		Label    int64
-		Prob     int    // 0-100，代表置信度，越高代表越有可能属于当前返回的标签（label）
-		Keyword  string // 命中的自定义关键词
-	} // 详细检测结果
+	Detail []MsgCheckDetail // 详细检测结果
+}
+
+// MsgCheckDetail 单个策略的检测结果
+type MsgCheckDetail struct {
+	Strategy string
+	ErrCode  int64
+	Suggest  string
+	Label    int64
+	Prob     int    // 0-100，代表置信度，越高代表越有可能属于当前返回的标签（label）
+	Keyword  string // 命中的自定义关键词
 }
 
 func NewApi(client *utils.Client) *ContentCheckApi {
@@ -85,7 +88,7 @@ func (api *ContentCheckApi) CheckMsg(
 	return result, nil
 }
 
-// CheckImg 过滤敏感图片
+// CheckImg 过滤敏感图片(同步, 1.0 版本接口), 新业务建议使用 MediaCheckAsync
 // https://developers.weixin.qq.com/miniprogram/dev/api-backend/open-api/sec-check/security.imgSecCheck.html
 func (api *ContentCheckApi) CheckImg(
 	ctx context.Context,
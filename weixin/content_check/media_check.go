@@ -0,0 +1,112 @@
+package content_check
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/lixinio/weixin/utils"
+)
+
+const (
+	apiMediaCheckAsync = "/wxa/media_check_async"
+
+	MaxMediaSize = 10 * 1024 * 1024 // 单个文件大小上限 10M
+)
+
+// 媒体类型
+const (
+	MediaTypeAudio = 1 // 音频
+	MediaTypeImage = 2 // 图片
+)
+
+// 场景枚举值
+const (
+	SceneProfile = 1 // 资料
+	SceneComment = 2 // 评论
+	SceneForum   = 3 // 论坛
+	SceneSocial  = 4 // 社交日志
+)
+
+// 检测建议
+const (
+	SuggestPass   = "pass"
+	SuggestReview = "review"
+	SuggestRisky  = "risky"
+)
+
+var (
+	ErrMediaTooLarge     = errors.New("media size exceeds 10M")
+	ErrMediaTypeMismatch = errors.New("media content type mismatch")
+)
+
+// MediaCheckParams 异步校验参数
+type MediaCheckParams struct {
+	MediaUrl  string `json:"media_url"`  // 要检测的图片或音频的url，支持图片格式包括jpg, jepg, png, bmp, gif（取首帧），支持的音频格式包括mp3, aac, ac3, wma, flac, vorbis, opus, wav
+	MediaType int    `json:"media_type"` // MediaType*
+	Version   int    `json:"version"`    // 接口版本号，2.0版本为固定值2
+	Scene     int    `json:"scene"`      // Scene*
+	OpenID    string `json:"openid"`     // 用户的openid（用户需在近两小时访问过小程序）
+}
+
+// MediaCheckAsync 异步校验图片/音频是否含有违法违规内容
+// 检测结果在 30 分钟内通过 server_api.EventWxaMediaCheck 推送, 使用 trace_id 关联
+// https://developers.weixin.qq.com/miniprogram/dev/OpenApiDoc/sec-center/sec-check/mediaCheckAsync.html
+// POST https://api.weixin.qq.com/wxa/media_check_async?access_token=ACCESS_TOKEN
+func (api *ContentCheckApi) MediaCheckAsync(
+	ctx context.Context,
+	params *MediaCheckParams,
+) (traceID string, err error) {
+	payload := *params // 不修改调用方的参数
+	if payload.Version == 0 {
+		payload.Version = msgCheckVersion
+	}
+	result := &struct {
+		utils.WeixinError
+		TraceID string `json:"trace_id"`
+	}{}
+	if err = api.Client.HTTPPostJson(ctx, apiMediaCheckAsync, &payload, result); err != nil {
+		return "", err
+	}
+	return result.TraceID, nil
+}
+
+// ValidateMedia 提交之前通过 HEAD 请求校验文件大小和类型, 不支持 HEAD 的地址跳过校验
+func ValidateMedia(ctx context.Context, mediaUrl string, mediaType int) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, mediaUrl, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented:
+		return nil
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return fmt.Errorf("head media %s fail, http status %d", mediaUrl, resp.StatusCode)
+	}
+
+	if resp.ContentLength > MaxMediaSize {
+		return ErrMediaTooLarge
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" || strings.HasPrefix(contentType, "application/octet-stream") {
+		return nil
+	}
+	prefix := "image/"
+	if mediaType == MediaTypeAudio {
+		prefix = "audio/"
+	}
+	if !strings.HasPrefix(contentType, prefix) {
+		return fmt.Errorf("%w: %s", ErrMediaTypeMismatch, contentType)
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package content_check
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/lixinio/weixin/utils"
+	"github.com/lixinio/weixin/utils/memcache"
+	"github.com/lixinio/weixin/weixin/server_api"
+)
+
+const (
+	mediaCheckKeyPrefix = "weixin:mediacheck:"
+	mediaCheckCacheTTL  = 24 * time.Hour
+)
+
+var ErrMediaCheckTaskNotFound = errors.New("media check task not found")
+
+// 检测任务状态, 除了 pending 和 failed 以外与检测建议 Suggest* 一致
+const (
+	MediaCheckStatusPending = "pending" // 已提交, 等待推送
+	MediaCheckStatusFailed  = "failed"  // 检测失败(推送的 errcode 非0)
+)
+
+// MediaCheckTask 一次异步检测, 通过 TraceID 关联业务对象
+type MediaCheckTask struct {
+	TraceID   string `json:"trace_id"`
+	BizID     string `json:"biz_id"` // 业务对象标识, 例如 "avatar:10086"
+	MediaUrl  string `json:"media_url"`
+	MediaType int    `json:"media_type"`
+	OpenID    string `json:"openid"`
+	Status    string `json:"status"`
+	Label     int    `json:"label,omitempty"` // 命中标签
+	ErrMsg    string `json:"errmsg,omitempty"`
+	CreatedAt int64  `json:"created_at"`
+	UpdatedAt int64  `json:"updated_at"`
+}
+
+// MediaCheckStore 检测任务存储
+type MediaCheckStore interface {
+	Load(ctx context.Context, traceID string) (*MediaCheckTask, error) // 不存在返回 ErrMediaCheckTaskNotFound
+	Save(ctx context.Context, task *MediaCheckTask) error
+}
+
+// NewMemoryMediaCheckStore 内存存储, 适用于测试或者单进程
+func NewMemoryMediaCheckStore() *CacheMediaCheckStore {
+	return NewCacheMediaCheckStore(memcache.NewMemcache(), 0)
+}
+
+// CacheMediaCheckStore 基于 utils.Cache 的存储
+type CacheMediaCheckStore struct {
+	cache utils.Cache
+	ttl   time.Duration
+}
+
+// ttl 为 0 时默认保存1天, 推送一般在30分钟内到达
+func NewCacheMediaCheckStore(cache utils.Cache, ttl time.Duration) *CacheMediaCheckStore {
+	if ttl <= 0 {
+		ttl = mediaCheckCacheTTL
+	}
+	return &CacheMediaCheckStore{cache: cache, ttl: ttl}
+}
+
+func (s *CacheMediaCheckStore) Load(ctx context.Context, traceID string) (*MediaCheckTask, error) {
+	task := &MediaCheckTask{}
+	exist, err := utils.CacheGetJSON(s.cache, mediaCheckKeyPrefix+traceID, task)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, ErrMediaCheckTaskNotFound
+	}
+	return task, nil
+}
+
+func (s *CacheMediaCheckStore) Save(ctx context.Context, task *MediaCheckTask) error {
+	return utils.CacheSetJSON(s.cache, mediaCheckKeyPrefix+task.TraceID, task, s.ttl)
+}
+
+// MediaChecker 提交异步检测并根据推送更新任务状态
+// 推送可能早于 Submit 保存任务到达, 此时 HandleEvent 暂存检测结果(BizID 为空)并返回 ErrMediaCheckTaskNotFound,
+// 之后 Submit 合并暂存的结果, 返回的任务状态不再是 pending, 调用方需要直接处理
+type MediaChecker struct {
+	api   *ContentCheckApi
+	store MediaCheckStore
+	mutex sync.Mutex
+	// 提交之前是否跳过 ValidateMedia
+	SkipValidate bool
+	// 可选, 多进程处理推送时避免 Submit 和 HandleEvent 互相覆盖
+	Locker utils.Lock
+}
+
+func NewMediaChecker(api *ContentCheckApi, store MediaCheckStore) *MediaChecker {
+	return &MediaChecker{api: api, store: store}
+}
+
+// 串行处理同一个 trace_id 的任务
+func (c *MediaChecker) withTask(traceID string, fn func() error) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return utils.WithLock(c.Locker, mediaCheckKeyPrefix+traceID+":lock", fn)
+}
+
+// Submit 校验并提交检测, 返回的任务已保存
+// 如果检测结果已经推送, 返回的任务包含检测结果
+func (c *MediaChecker) Submit(
+	ctx context.Context,
+	bizID string,
+	params *MediaCheckParams,
+) (*MediaCheckTask, error) {
+	if !c.SkipValidate {
+		if err := ValidateMedia(ctx, params.MediaUrl, params.MediaType); err != nil {
+			return nil, err
+		}
+	}
+
+	traceID, err := c.api.MediaCheckAsync(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var task *MediaCheckTask
+	err = c.withTask(traceID, func() error {
+		now := time.Now().Unix()
+		task, err = c.store.Load(ctx, traceID)
+		if errors.Is(err, ErrMediaCheckTaskNotFound) {
+			task = &MediaCheckTask{
+				TraceID:   traceID,
+				Status:    MediaCheckStatusPending,
+				CreatedAt: now,
+			}
+		} else if err != nil {
+			return err
+		}
+
+		task.BizID = bizID
+		task.MediaUrl = params.MediaUrl
+		task.MediaType = params.MediaType
+		task.OpenID = params.OpenID
+		task.UpdatedAt = now
+		return c.store.Save(ctx, task)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// HandleEvent 处理检测结果推送, 返回更新之后的任务
+// 非 EventWxaMediaCheck 事件返回 nil, nil; 找不到任务时暂存结果并返回 ErrMediaCheckTaskNotFound
+func (c *MediaChecker) HandleEvent(ctx context.Context, event interface{}) (*MediaCheckTask, error) {
+	e, ok := event.(*server_api.EventWxaMediaCheck)
+	if !ok {
+		return nil, nil
+	}
+
+	var task *MediaCheckTask
+	found := true
+	err := c.withTask(e.TraceID, func() error {
+		now := time.Now().Unix()
+		var err error
+		task, err = c.store.Load(ctx, e.TraceID)
+		if errors.Is(err, ErrMediaCheckTaskNotFound) {
+			// Submit 还没有保存任务
+			found = false
+			task = &MediaCheckTask{TraceID: e.TraceID, CreatedAt: now}
+		} else if err != nil {
+			return err
+		}
+
+		if e.ErrCode != 0 {
+			task.Status = MediaCheckStatusFailed
+			task.ErrMsg = e.ErrMsg
+		} else {
+			task.Status = e.Result.Suggest
+			task.Label = e.Result.Label
+			task.ErrMsg = ""
+		}
+		task.UpdatedAt = now
+		return c.store.Save(ctx, task)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrMediaCheckTaskNotFound
+	}
+	return task, nil
+}
@@ -0,0 +1,144 @@
+package content_check
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lixinio/weixin/utils"
+	"github.com/lixinio/weixin/weixin/server_api"
+	"github.com/stretchr/testify/require"
+)
+
+const mediaCheckEvent = `<xml>
+	<ToUserName><![CDATA[gh_38cc49f9733b]]></ToUserName>
+	<FromUserName><![CDATA[oH1fu0FdHqpToe2T6gBj0WyB8iS1]]></FromUserName>
+	<CreateTime>1626959646</CreateTime>
+	<MsgType><![CDATA[event]]></MsgType>
+	<Event><![CDATA[wxa_media_check]]></Event>
+	<appid><![CDATA[wx8f16a5e5b8a4da04]]></appid>
+	<trace_id><![CDATA[60f96f1d-3845297a-1976a3ae]]></trace_id>
+	<version>2</version>
+	<detail><strategy><![CDATA[content_model]]></strategy><errcode>0</errcode><suggest><![CDATA[risky]]></suggest><label>20002</label><prob>90</prob></detail>
+	<detail><strategy><![CDATA[keyword]]></strategy><errcode>0</errcode><suggest><![CDATA[pass]]></suggest><label>100</label><prob>100</prob></detail>
+	<errcode>0</errcode>
+	<errmsg><![CDATA[ok]]></errmsg>
+	<result><suggest><![CDATA[risky]]></suggest><label>20002</label></result>
+</xml>`
+
+func newMediaServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case apiMediaCheckAsync:
+			params := &MediaCheckParams{}
+			require.Equal(t, nil, json.NewDecoder(r.Body).Decode(params))
+			require.Equal(t, 2, params.Version)
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"errcode":0,"errmsg":"ok","trace_id":"60f96f1d-3845297a-1976a3ae"}`))
+		case "/avatar.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Header().Set("Content-Length", "1024")
+		case "/large.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Header().Set("Content-Length", "20971520")
+		case "/voice.mp3":
+			w.Header().Set("Content-Type", "audio/mpeg")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestValidateMedia(t *testing.T) {
+	ctx := context.Background()
+	server := newMediaServer(t)
+	defer server.Close()
+
+	require.Equal(t, nil, ValidateMedia(ctx, server.URL+"/avatar.png", MediaTypeImage))
+	require.Equal(t, nil, ValidateMedia(ctx, server.URL+"/voice.mp3", MediaTypeAudio))
+	require.Equal(t, ErrMediaTooLarge, ValidateMedia(ctx, server.URL+"/large.png", MediaTypeImage))
+	err := ValidateMedia(ctx, server.URL+"/voice.mp3", MediaTypeImage)
+	require.True(t, errors.Is(err, ErrMediaTypeMismatch))
+	require.NotEqual(t, nil, ValidateMedia(ctx, server.URL+"/none.png", MediaTypeImage))
+}
+
+func TestMediaChecker(t *testing.T) {
+	ctx := context.Background()
+	server := newMediaServer(t)
+	defer server.Close()
+
+	api := NewApi(utils.NewClient(server.URL, utils.StaticClientAccessTokenGetter("token")))
+	checker := NewMediaChecker(api, NewMemoryMediaCheckStore())
+
+	_, err := checker.Submit(ctx, "avatar:10086", &MediaCheckParams{
+		MediaUrl:  server.URL + "/large.png",
+		MediaType: MediaTypeImage,
+		Scene:     SceneProfile,
+		OpenID:    "oH1fu0FdHqpToe2T6gBj0WyB8iS1",
+	})
+	require.Equal(t, ErrMediaTooLarge, err)
+
+	params := &MediaCheckParams{
+		MediaUrl:  server.URL + "/avatar.png",
+		MediaType: MediaTypeImage,
+		Scene:     SceneProfile,
+		OpenID:    "oH1fu0FdHqpToe2T6gBj0WyB8iS1",
+	}
+	task, err := checker.Submit(ctx, "avatar:10086", params)
+	require.Equal(t, nil, err)
+	require.Equal(t, "60f96f1d-3845297a-1976a3ae", task.TraceID)
+	require.Equal(t, MediaCheckStatusPending, task.Status)
+	require.Equal(t, 0, params.Version) // 不修改调用方的参数
+
+	content, err := server_api.NewApi("appid", "token", "", nil).ParseXML([]byte(mediaCheckEvent))
+	require.Equal(t, nil, err)
+	event, ok := content.(*server_api.EventWxaMediaCheck)
+	require.True(t, ok)
+	require.Equal(t, 2, len(event.Detail))
+	require.Equal(t, "keyword", event.Detail[1].Strategy)
+
+	task, err = checker.HandleEvent(ctx, event)
+	require.Equal(t, nil, err)
+	require.Equal(t, "avatar:10086", task.BizID)
+	require.Equal(t, SuggestRisky, task.Status)
+	require.Equal(t, 20002, task.Label)
+
+	task, err = checker.HandleEvent(ctx, &server_api.EventSubscribe{})
+	require.Equal(t, nil, err)
+	require.Nil(t, task)
+
+	event.TraceID = "unknown"
+	_, err = checker.HandleEvent(ctx, event)
+	require.Equal(t, ErrMediaCheckTaskNotFound, err)
+}
+
+func TestMediaCheckerEarlyEvent(t *testing.T) {
+	ctx := context.Background()
+	server := newMediaServer(t)
+	defer server.Close()
+
+	api := NewApi(utils.NewClient(server.URL, utils.StaticClientAccessTokenGetter("token")))
+	checker := NewMediaChecker(api, NewMemoryMediaCheckStore())
+	checker.SkipValidate = true
+
+	// 推送早于 Submit 保存任务
+	content, err := server_api.NewApi("appid", "token", "", nil).ParseXML([]byte(mediaCheckEvent))
+	require.Equal(t, nil, err)
+	task, err := checker.HandleEvent(ctx, content)
+	require.Equal(t, ErrMediaCheckTaskNotFound, err)
+	require.Nil(t, task)
+
+	task, err = checker.Submit(ctx, "avatar:10086", &MediaCheckParams{
+		MediaUrl:  server.URL + "/avatar.png",
+		MediaType: MediaTypeImage,
+		Scene:     SceneProfile,
+		OpenID:    "oH1fu0FdHqpToe2T6gBj0WyB8iS1",
+	})
+	require.Equal(t, nil, err)
+	require.Equal(t, "avatar:10086", task.BizID)
+	require.Equal(t, SuggestRisky, task.Status)
+	require.Equal(t, 20002, task.Label)
+}
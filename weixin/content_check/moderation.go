@@ -0,0 +1,216 @@
+package content_check
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/lixinio/weixin/utils"
+)
+
+const (
+	moderationKeyPrefix = "weixin:moderation:"
+	moderationCacheTTL  = 7 * 24 * time.Hour
+
+	LabelNormal = 100   // 正常
+	LabelOther  = 21000 // 其他
+
+	StrategyLocalKeyword = "local_keyword" // 本地关键词
+	StrategyLocalRegexp  = "local_regexp"  // 本地正则
+)
+
+// 结论来源
+const (
+	VerdictSourceLocal  = "local"
+	VerdictSourceCache  = "cache"
+	VerdictSourceWeixin = "weixin"
+)
+
+// MsgCheckParams 文本检测参数, 与 CheckMsg 的参数一致
+type MsgCheckParams struct {
+	OpenID    string
+	Scene     int // Scene*
+	Content   string
+	Nickname  string
+	Title     string
+	Signature string
+}
+
+func (p *MsgCheckParams) text() string {
+	return strings.Join([]string{p.Content, p.Nickname, p.Title, p.Signature}, "\n")
+}
+
+// Verdict 统一的检测结论, 与 MsgCheckResult 的结构保持一致
+type Verdict struct {
+	Result struct {
+		Suggest string `json:"suggest"` // Suggest*
+		Label   int64  `json:"label"`
+	} `json:"result"`
+	Detail   []MsgCheckDetail `json:"detail,omitempty"`
+	Keywords []string         `json:"keywords,omitempty"` // 命中的关键词(本地和微信自定义关键词)
+	Source   string           `json:"-"`                  // VerdictSource*
+}
+
+func (v *Verdict) Pass() bool {
+	return v.Result.Suggest == SuggestPass
+}
+
+// ModerationConfig 审核流水线配置
+type ModerationConfig struct {
+	Api            *ContentCheckApi // 为空时只做本地过滤, 未命中的文本视为通过
+	BlockKeywords  []string         // 命中直接判定为 risky
+	BlockPatterns  []*regexp.Regexp // 命中直接判定为 risky
+	ReviewKeywords []string         // 命中判定为 review, 不再调用微信
+	PassPatterns   []*regexp.Regexp // 全文匹配其中之一直接判定为 pass, 例如纯数字或者表情
+	BlockLabel     int64            // 本地命中使用的标签, 默认 LabelOther
+	Cache          utils.Cache      // 可选, 按场景/openid/各字段 hash 缓存微信的结论
+	CacheTTL       time.Duration    // 默认7天
+	// 缓存读写失败时回调, 可选; 缓存失败不影响检测结果
+	OnCacheError func(err error)
+}
+
+// Moderation 文本审核流水线: 本地过滤 → 缓存 → CheckMsg
+type Moderation struct {
+	config *ModerationConfig
+	block  *keywordMatcher
+	review *keywordMatcher
+	ttl    time.Duration
+	label  int64
+}
+
+func NewModeration(config *ModerationConfig) *Moderation {
+	m := &Moderation{
+		config: config,
+		block:  newKeywordMatcher(config.BlockKeywords),
+		review: newKeywordMatcher(config.ReviewKeywords),
+		ttl:    config.CacheTTL,
+		label:  config.BlockLabel,
+	}
+	if m.ttl <= 0 {
+		m.ttl = moderationCacheTTL
+	}
+	if m.label == 0 {
+		m.label = LabelOther
+	}
+	return m
+}
+
+// Check 检测文本
+func (m *Moderation) Check(ctx context.Context, params *MsgCheckParams) (*Verdict, error) {
+	text := params.text()
+	if verdict := m.checkLocal(text); verdict != nil {
+		return verdict, nil
+	}
+	if m.config.Api == nil {
+		return newVerdict(SuggestPass, LabelNormal, VerdictSourceLocal), nil
+	}
+
+	// 微信的结论与 openid 相关(用户近期的违规记录), 因此 openid 也是缓存 key 的一部分
+	key := cacheKey(params)
+	if m.config.Cache != nil {
+		verdict := &Verdict{}
+		exist, err := utils.CacheGetJSON(m.config.Cache, key, verdict)
+		if err != nil {
+			m.cacheError(err)
+		} else if exist {
+			verdict.Source = VerdictSourceCache
+			return verdict, nil
+		}
+	}
+
+	result, err := m.config.Api.CheckMsg(
+		ctx, params.OpenID, params.Scene, params.Content,
+		params.Nickname, params.Title, params.Signature,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	verdict := newVerdict(result.Result.Suggest, result.Result.Label, VerdictSourceWeixin)
+	verdict.Detail = result.Detail
+	for _, detail := range result.Detail {
+		if detail.Keyword != "" {
+			verdict.Keywords = append(verdict.Keywords, detail.Keyword)
+		}
+	}
+	if m.config.Cache != nil {
+		if err = utils.CacheSetJSON(m.config.Cache, key, verdict, m.ttl); err != nil {
+			m.cacheError(err)
+		}
+	}
+	return verdict, nil
+}
+
+// checkLocal 本地过滤, 无法判定返回 nil
+func (m *Moderation) checkLocal(text string) *Verdict {
+	if strings.TrimSpace(text) == "" {
+		return newVerdict(SuggestPass, LabelNormal, VerdictSourceLocal)
+	}
+
+	if keywords := m.block.find(text); len(keywords) > 0 {
+		return m.localHit(SuggestRisky, StrategyLocalKeyword, keywords)
+	}
+	for _, pattern := range m.config.BlockPatterns {
+		if match := pattern.FindString(text); match != "" {
+			return m.localHit(SuggestRisky, StrategyLocalRegexp, []string{match})
+		}
+	}
+	if keywords := m.review.find(text); len(keywords) > 0 {
+		return m.localHit(SuggestReview, StrategyLocalKeyword, keywords)
+	}
+
+	for _, pattern := range m.config.PassPatterns {
+		if loc := pattern.FindStringIndex(text); loc != nil && loc[0] == 0 && loc[1] == len(text) {
+			return newVerdict(SuggestPass, LabelNormal, VerdictSourceLocal)
+		}
+	}
+	return nil
+}
+
+func (m *Moderation) localHit(suggest, strategy string, keywords []string) *Verdict {
+	verdict := newVerdict(suggest, m.label, VerdictSourceLocal)
+	verdict.Keywords = keywords
+	for _, keyword := range keywords {
+		verdict.Detail = append(verdict.Detail, MsgCheckDetail{
+			Strategy: strategy,
+			Suggest:  suggest,
+			Label:    m.label,
+			Prob:     100,
+			Keyword:  keyword,
+		})
+	}
+	return verdict
+}
+
+func (m *Moderation) cacheError(err error) {
+	if m.config.OnCacheError != nil {
+		m.config.OnCacheError(err)
+	}
+}
+
+// cacheKey 每个字段带长度前缀后再 hash, 避免不同字段组合拼接后相同
+func cacheKey(params *MsgCheckParams) string {
+	hash := sha256.New()
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, uint64(params.Scene))
+	hash.Write(buf)
+	for _, field := range []string{
+		params.OpenID, params.Content, params.Nickname, params.Title, params.Signature,
+	} {
+		binary.BigEndian.PutUint64(buf, uint64(len(field)))
+		hash.Write(buf)
+		hash.Write([]byte(field))
+	}
+	return moderationKeyPrefix + hex.EncodeToString(hash.Sum(nil))
+}
+
+func newVerdict(suggest string, label int64, source string) *Verdict {
+	verdict := &Verdict{Source: source}
+	verdict.Result.Suggest = suggest
+	verdict.Result.Label = label
+	return verdict
+}
@@ -0,0 +1,142 @@
+package content_check
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/lixinio/weixin/utils"
+	"github.com/lixinio/weixin/utils/memcache"
+	"github.com/stretchr/testify/require"
+)
+
+// failingCache 读写都失败的缓存
+type failingCache struct {
+	memcache.Memcache
+}
+
+func (c *failingCache) Get(key string, value interface{}) (bool, error) {
+	return false, errors.New("cache unavailable")
+}
+
+func (c *failingCache) Set(key string, value interface{}, timeout time.Duration) error {
+	return errors.New("cache unavailable")
+}
+
+func TestKeywordMatcher(t *testing.T) {
+	m := newKeywordMatcher([]string{"he", "she", "his", "hers", "加微信", "微信号", ""})
+	require.Equal(t, []string{"she", "he", "hers"}, m.find("uSHErs"))
+	require.Equal(t, []string{"加微信", "微信号"}, m.find("加微信号abc"))
+	require.Empty(t, m.find("nothing"))
+	require.Empty(t, newKeywordMatcher(nil).find("anything"))
+}
+
+func TestModeration(t *testing.T) {
+	ctx := context.Background()
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, apiMsgSecCheck, r.URL.Path)
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"errcode":0,"errmsg":"ok","result":{"suggest":"risky","label":20006},` +
+			`"detail":[{"strategy":"keyword","errcode":0,"suggest":"risky","label":20006,"prob":90,"keyword":"赌博"}]}`))
+	}))
+	defer server.Close()
+
+	m := NewModeration(&ModerationConfig{
+		Api:            NewApi(utils.NewClient(server.URL, utils.StaticClientAccessTokenGetter("token"))),
+		BlockKeywords:  []string{"代开发票"},
+		BlockPatterns:  []*regexp.Regexp{regexp.MustCompile(`1[3-9]\d{9}`)},
+		ReviewKeywords: []string{"加微信"},
+		PassPatterns:   []*regexp.Regexp{regexp.MustCompile(`[0-9\s]+`)},
+		Cache:          memcache.NewMemcache(),
+	})
+
+	verdict, err := m.Check(ctx, &MsgCheckParams{Scene: SceneComment, Content: "低价代开发票"})
+	require.Equal(t, nil, err)
+	require.Equal(t, SuggestRisky, verdict.Result.Suggest)
+	require.Equal(t, int64(LabelOther), verdict.Result.Label)
+	require.Equal(t, []string{"代开发票"}, verdict.Keywords)
+	require.Equal(t, VerdictSourceLocal, verdict.Source)
+
+	verdict, err = m.Check(ctx, &MsgCheckParams{Scene: SceneComment, Content: "电话13800138000"})
+	require.Equal(t, nil, err)
+	require.Equal(t, SuggestRisky, verdict.Result.Suggest)
+	require.Equal(t, StrategyLocalRegexp, verdict.Detail[0].Strategy)
+
+	verdict, err = m.Check(ctx, &MsgCheckParams{Scene: SceneComment, Content: "有事加微信"})
+	require.Equal(t, nil, err)
+	require.Equal(t, SuggestReview, verdict.Result.Suggest)
+
+	verdict, err = m.Check(ctx, &MsgCheckParams{Scene: SceneComment, Content: " 123 456"})
+	require.Equal(t, nil, err)
+	require.True(t, verdict.Pass())
+	require.Equal(t, 0, calls)
+
+	verdict, err = m.Check(ctx, &MsgCheckParams{Scene: SceneComment, Content: "一起来赌博"})
+	require.Equal(t, nil, err)
+	require.Equal(t, SuggestRisky, verdict.Result.Suggest)
+	require.Equal(t, []string{"赌博"}, verdict.Keywords)
+	require.Equal(t, VerdictSourceWeixin, verdict.Source)
+	require.Equal(t, 1, calls)
+
+	// 相同内容命中缓存
+	verdict, err = m.Check(ctx, &MsgCheckParams{Scene: SceneComment, Content: "一起来赌博"})
+	require.Equal(t, nil, err)
+	require.Equal(t, VerdictSourceCache, verdict.Source)
+	require.Equal(t, int64(20006), verdict.Result.Label)
+	require.Equal(t, 1, calls)
+
+	// 不同场景分别缓存
+	_, err = m.Check(ctx, &MsgCheckParams{Scene: SceneForum, Content: "一起来赌博"})
+	require.Equal(t, nil, err)
+	require.Equal(t, 2, calls)
+
+	// 不同用户分别缓存
+	_, err = m.Check(ctx, &MsgCheckParams{OpenID: "openid", Scene: SceneComment, Content: "一起来赌博"})
+	require.Equal(t, nil, err)
+	require.Equal(t, 3, calls)
+
+	// 字段拼接后相同也不能共用缓存
+	_, err = m.Check(ctx, &MsgCheckParams{Scene: SceneProfile, Content: "a\nb"})
+	require.Equal(t, nil, err)
+	require.Equal(t, 4, calls)
+	_, err = m.Check(ctx, &MsgCheckParams{Scene: SceneProfile, Content: "a", Nickname: "b"})
+	require.Equal(t, nil, err)
+	require.Equal(t, 5, calls)
+	verdict, err = m.Check(ctx, &MsgCheckParams{Scene: SceneProfile, Content: "a", Nickname: "b"})
+	require.Equal(t, nil, err)
+	require.Equal(t, VerdictSourceCache, verdict.Source)
+	require.Equal(t, 5, calls)
+}
+
+func TestModerationCacheError(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"errcode":0,"errmsg":"ok","result":{"suggest":"pass","label":100}}`))
+	}))
+	defer server.Close()
+
+	cacheErrors := 0
+	m := NewModeration(&ModerationConfig{
+		Api:          NewApi(utils.NewClient(server.URL, utils.StaticClientAccessTokenGetter("token"))),
+		Cache:        &failingCache{},
+		OnCacheError: func(err error) { cacheErrors++ },
+	})
+
+	// 缓存读写失败时仍然调用微信并返回结论
+	for i := 1; i <= 2; i++ {
+		verdict, err := m.Check(context.Background(), &MsgCheckParams{Scene: SceneComment, Content: "你好"})
+		require.Equal(t, nil, err)
+		require.True(t, verdict.Pass())
+		require.Equal(t, VerdictSourceWeixin, verdict.Source)
+		require.Equal(t, i, calls)
+		require.Equal(t, i*2, cacheErrors)
+	}
+}
@@ -0,0 +1,127 @@
+package datacube_api
+
+// Package datacube 数据统计
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lixinio/weixin/utils"
+)
+
+const (
+	dateFormatOfficialAccount = "2006-01-02" // 公众号 日期格式
+	dateFormatWxa             = "20060102"   // 小程序 日期格式
+)
+
+type DatacubeApi struct {
+	*utils.Client
+}
+
+func NewApi(client *utils.Client) *DatacubeApi {
+	return &DatacubeApi{Client: client}
+}
+
+type dateRange struct {
+	Begin time.Time
+	End   time.Time
+}
+
+// splitDateRange 按照接口允许的最大时间跨度(天)拆分日期区间, 首尾均包含
+func splitDateRange(begin, end time.Time, maxDays int) ([]dateRange, error) {
+	begin, end = truncateDay(begin), truncateDay(end)
+	if end.Before(begin) {
+		return nil, fmt.Errorf(
+			"invalid date range %s - %s",
+			begin.Format(dateFormatOfficialAccount), end.Format(dateFormatOfficialAccount),
+		)
+	}
+	if maxDays <= 0 {
+		maxDays = 1
+	}
+
+	ranges := []dateRange{}
+	for !begin.After(end) {
+		chunkEnd := begin.AddDate(0, 0, maxDays-1)
+		if chunkEnd.After(end) {
+			chunkEnd = end
+		}
+		ranges = append(ranges, dateRange{Begin: begin, End: chunkEnd})
+		begin = chunkEnd.AddDate(0, 0, 1)
+	}
+	return ranges, nil
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// splitDays 按天拆分
+func splitDays(begin, end time.Time) ([]dateRange, error) {
+	return splitDateRange(begin, end, 1)
+}
+
+// splitWeeks 按自然周拆分, begin 必须是周一, end 必须是周日
+func splitWeeks(begin, end time.Time) ([]dateRange, error) {
+	begin, end = truncateDay(begin), truncateDay(end)
+	if begin.Weekday() != time.Monday || end.Weekday() != time.Sunday {
+		return nil, fmt.Errorf(
+			"invalid week range %s - %s, must be monday to sunday",
+			begin.Format(dateFormatOfficialAccount), end.Format(dateFormatOfficialAccount),
+		)
+	}
+	return splitDateRange(begin, end, 7)
+}
+
+// splitMonths 按自然月拆分, begin 必须是月初, end 必须是月末
+func splitMonths(begin, end time.Time) ([]dateRange, error) {
+	begin, end = truncateDay(begin), truncateDay(end)
+	if begin.Day() != 1 || end.AddDate(0, 0, 1).Day() != 1 || end.Before(begin) {
+		return nil, fmt.Errorf(
+			"invalid month range %s - %s, must be first day to last day of month",
+			begin.Format(dateFormatOfficialAccount), end.Format(dateFormatOfficialAccount),
+		)
+	}
+
+	ranges := []dateRange{}
+	for begin.Before(end) {
+		next := begin.AddDate(0, 1, 0)
+		ranges = append(ranges, dateRange{Begin: begin, End: next.AddDate(0, 0, -1)})
+		begin = next
+	}
+	return ranges, nil
+}
+
+// 按照最大跨度拆分日期区间, 依次请求
+func (api *DatacubeApi) fetchRange(
+	ctx context.Context, uri, layout string,
+	begin, end time.Time, maxDays int,
+	newResult func() interface{}, collect func(interface{}),
+) error {
+	ranges, err := splitDateRange(begin, end, maxDays)
+	if err != nil {
+		return err
+	}
+	return api.fetchRanges(ctx, uri, layout, ranges, newResult, collect)
+}
+
+// 按照拆分好的日期区间, 依次请求
+func (api *DatacubeApi) fetchRanges(
+	ctx context.Context, uri, layout string, ranges []dateRange,
+	newResult func() interface{}, collect func(interface{}),
+) error {
+	for _, r := range ranges {
+		param := &struct {
+			BeginDate string `json:"begin_date"`
+			EndDate   string `json:"end_date"`
+		}{r.Begin.Format(layout), r.End.Format(layout)}
+
+		result := newResult()
+		if err := api.Client.HTTPPostJson(ctx, uri, param, result); err != nil {
+			return err
+		}
+		collect(result)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package datacube_api
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lixinio/weixin/test"
+	"github.com/lixinio/weixin/utils/redis"
+	"github.com/lixinio/weixin/weixin/official_account"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitDateRange(t *testing.T) {
+	day := func(s string) time.Time {
+		d, err := time.Parse(dateFormatOfficialAccount, s)
+		require.Equal(t, nil, err)
+		return d
+	}
+	format := func(ranges []dateRange) []string {
+		result := []string{}
+		for _, r := range ranges {
+			result = append(result, fmt.Sprintf(
+				"%s~%s",
+				r.Begin.Format(dateFormatWxa), r.End.Format(dateFormatWxa),
+			))
+		}
+		return result
+	}
+
+	ranges, err := splitDateRange(day("2021-01-01"), day("2021-01-01"), 7)
+	require.Equal(t, nil, err)
+	require.Equal(t, []string{"20210101~20210101"}, format(ranges))
+
+	ranges, err = splitDateRange(day("2021-01-01"), day("2021-01-16"), 7)
+	require.Equal(t, nil, err)
+	require.Equal(t, []string{
+		"20210101~20210107", "20210108~20210114", "20210115~20210116",
+	}, format(ranges))
+
+	ranges, err = splitDateRange(day("2021-02-27"), day("2021-03-01"), 1)
+	require.Equal(t, nil, err)
+	require.Equal(t, []string{
+		"20210227~20210227", "20210228~20210228", "20210301~20210301",
+	}, format(ranges))
+
+	_, err = splitDateRange(day("2021-01-02"), day("2021-01-01"), 7)
+	require.NotEqual(t, nil, err)
+
+	// 2021-01-04 是周一
+	ranges, err = splitWeeks(day("2021-01-04"), day("2021-01-24"))
+	require.Equal(t, nil, err)
+	require.Equal(t, []string{
+		"20210104~20210110", "20210111~20210117", "20210118~20210124",
+	}, format(ranges))
+
+	_, err = splitWeeks(day("2021-01-01"), day("2021-01-24"))
+	require.NotEqual(t, nil, err)
+	_, err = splitWeeks(day("2021-01-04"), day("2021-01-23"))
+	require.NotEqual(t, nil, err)
+
+	ranges, err = splitMonths(day("2021-01-01"), day("2021-04-30"))
+	require.Equal(t, nil, err)
+	require.Equal(t, []string{
+		"20210101~20210131", "20210201~20210228", "20210301~20210331", "20210401~20210430",
+	}, format(ranges))
+
+	ranges, err = splitMonths(day("2020-02-01"), day("2020-02-29"))
+	require.Equal(t, nil, err)
+	require.Equal(t, []string{"20200201~20200229"}, format(ranges))
+
+	_, err = splitMonths(day("2021-01-02"), day("2021-04-30"))
+	require.NotEqual(t, nil, err)
+	_, err = splitMonths(day("2021-01-01"), day("2021-04-29"))
+	require.NotEqual(t, nil, err)
+	_, err = splitMonths(day("2021-04-01"), day("2021-01-31"))
+	require.NotEqual(t, nil, err)
+}
+
+func TestUserSummary(t *testing.T) {
+	redis := redis.NewRedis(&redis.Config{RedisUrl: test.CacheUrl})
+	officialAccount := official_account.New(redis, redis, &official_account.Config{
+		Appid:  test.OfficialAccountAppid,
+		Secret: test.OfficialAccountSecret,
+	})
+	ctx := context.Background()
+	datacubeApi := NewApi(officialAccount.Client)
+
+	end := time.Now().AddDate(0, 0, -1)
+	begin := end.AddDate(0, 0, -20)
+
+	summary, err := datacubeApi.GetUserSummary(ctx, begin, end)
+	require.Equal(t, nil, err)
+	fmt.Println(summary)
+
+	cumulate, err := datacubeApi.GetUserCumulate(ctx, begin, end)
+	require.Equal(t, nil, err)
+	require.Equal(t, 21, len(cumulate))
+}
@@ -0,0 +1,434 @@
+package datacube_api
+
+import (
+	"context"
+	"time"
+
+	"github.com/lixinio/weixin/utils"
+)
+
+// 公众号 数据统计
+// https://developers.weixin.qq.com/doc/offiaccount/Analytics/User_Analysis_Data_Interface.html
+// 所有接口 POST {"begin_date": "2014-12-02", "end_date": "2014-12-07"}, 最大时间跨度见各接口说明
+
+const (
+	apiGetUserSummary          = "/datacube/getusersummary"
+	apiGetUserCumulate         = "/datacube/getusercumulate"
+	apiGetArticleSummary       = "/datacube/getarticlesummary"
+	apiGetArticleTotal         = "/datacube/getarticletotal"
+	apiGetUserRead             = "/datacube/getuserread"
+	apiGetUserReadHour         = "/datacube/getuserreadhour"
+	apiGetUserShare            = "/datacube/getusershare"
+	apiGetUserShareHour        = "/datacube/getusersharehour"
+	apiGetUpstreamMsg          = "/datacube/getupstreammsg"
+	apiGetUpstreamMsgHour      = "/datacube/getupstreammsghour"
+	apiGetUpstreamMsgWeek      = "/datacube/getupstreammsgweek"
+	apiGetUpstreamMsgMonth     = "/datacube/getupstreammsgmonth"
+	apiGetUpstreamMsgDist      = "/datacube/getupstreammsgdist"
+	apiGetUpstreamMsgDistWeek  = "/datacube/getupstreammsgdistweek"
+	apiGetUpstreamMsgDistMonth = "/datacube/getupstreammsgdistmonth"
+	apiGetInterfaceSummary     = "/datacube/getinterfacesummary"
+	apiGetInterfaceSummaryHour = "/datacube/getinterfacesummaryhour"
+)
+
+/*
+获取用户增减数据, 最大时间跨度 7 天
+See: https://developers.weixin.qq.com/doc/offiaccount/Analytics/User_Analysis_Data_Interface.html
+*/
+type UserSummary struct {
+	RefDate    string `json:"ref_date"`
+	UserSource int    `json:"user_source"` // 用户的渠道
+	NewUser    int    `json:"new_user"`
+	CancelUser int    `json:"cancel_user"`
+}
+
+func (api *DatacubeApi) GetUserSummary(
+	ctx context.Context, begin, end time.Time,
+) ([]UserSummary, error) {
+	type resultList struct {
+		utils.WeixinError
+		List []UserSummary `json:"list"`
+	}
+
+	list := []UserSummary{}
+	if err := api.fetchRange(
+		ctx, apiGetUserSummary, dateFormatOfficialAccount, begin, end, 7,
+		func() interface{} { return &resultList{} },
+		func(r interface{}) { list = append(list, r.(*resultList).List...) },
+	); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+/*
+获取累计用户数据, 最大时间跨度 7 天
+See: https://developers.weixin.qq.com/doc/offiaccount/Analytics/User_Analysis_Data_Interface.html
+*/
+type UserCumulate struct {
+	RefDate      string `json:"ref_date"`
+	CumulateUser int    `json:"cumulate_user"`
+}
+
+func (api *DatacubeApi) GetUserCumulate(
+	ctx context.Context, begin, end time.Time,
+) ([]UserCumulate, error) {
+	type resultList struct {
+		utils.WeixinError
+		List []UserCumulate `json:"list"`
+	}
+
+	list := []UserCumulate{}
+	if err := api.fetchRange(
+		ctx, apiGetUserCumulate, dateFormatOfficialAccount, begin, end, 7,
+		func() interface{} { return &resultList{} },
+		func(r interface{}) { list = append(list, r.(*resultList).List...) },
+	); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// 图文阅读/分享/收藏 通用统计字段
+type ArticleStat struct {
+	IntPageReadUser  int `json:"int_page_read_user"`  // 图文页的阅读人数
+	IntPageReadCount int `json:"int_page_read_count"` // 图文页的阅读次数
+	OriPageReadUser  int `json:"ori_page_read_user"`  // 原文页的阅读人数
+	OriPageReadCount int `json:"ori_page_read_count"` // 原文页的阅读次数
+	ShareUser        int `json:"share_user"`
+	ShareCount       int `json:"share_count"`
+	AddToFavUser     int `json:"add_to_fav_user"`
+	AddToFavCount    int `json:"add_to_fav_count"`
+}
+
+/*
+获取图文群发每日数据, 最大时间跨度 1 天
+See: https://developers.weixin.qq.com/doc/offiaccount/Analytics/Graphic_Analysis_Data_Interface.html
+*/
+type ArticleSummary struct {
+	RefDate string `json:"ref_date"`
+	MsgID   string `json:"msgid"`
+	Title   string `json:"title"`
+	ArticleStat
+}
+
+func (api *DatacubeApi) GetArticleSummary(
+	ctx context.Context, begin, end time.Time,
+) ([]ArticleSummary, error) {
+	type resultList struct {
+		utils.WeixinError
+		List []ArticleSummary `json:"list"`
+	}
+
+	list := []ArticleSummary{}
+	if err := api.fetchRange(
+		ctx, apiGetArticleSummary, dateFormatOfficialAccount, begin, end, 1,
+		func() interface{} { return &resultList{} },
+		func(r interface{}) { list = append(list, r.(*resultList).List...) },
+	); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+/*
+获取图文群发总数据, 最大时间跨度 1 天
+See: https://developers.weixin.qq.com/doc/offiaccount/Analytics/Graphic_Analysis_Data_Interface.html
+*/
+type ArticleTotal struct {
+	RefDate string `json:"ref_date"`
+	MsgID   string `json:"msgid"`
+	Title   string `json:"title"`
+	Details []struct {
+		StatDate   string `json:"stat_date"`
+		TargetUser int    `json:"target_user"` // 送达人数
+		ArticleStat
+		IntPageFromSessionReadUser   int `json:"int_page_from_session_read_user"`
+		IntPageFromSessionReadCount  int `json:"int_page_from_session_read_count"`
+		IntPageFromHistMsgReadUser   int `json:"int_page_from_hist_msg_read_user"`
+		IntPageFromHistMsgReadCount  int `json:"int_page_from_hist_msg_read_count"`
+		IntPageFromFeedReadUser      int `json:"int_page_from_feed_read_user"`
+		IntPageFromFeedReadCount     int `json:"int_page_from_feed_read_count"`
+		IntPageFromFriendsReadUser   int `json:"int_page_from_friends_read_user"`
+		IntPageFromFriendsReadCount  int `json:"int_page_from_friends_read_count"`
+		IntPageFromOtherReadUser     int `json:"int_page_from_other_read_user"`
+		IntPageFromOtherReadCount    int `json:"int_page_from_other_read_count"`
+		FeedShareFromSessionUser     int `json:"feed_share_from_session_user"`
+		FeedShareFromSessionCnt      int `json:"feed_share_from_session_cnt"`
+		FeedShareFromFeedUser        int `json:"feed_share_from_feed_user"`
+		FeedShareFromFeedCnt         int `json:"feed_share_from_feed_cnt"`
+		FeedShareFromOtherUser       int `json:"feed_share_from_other_user"`
+		FeedShareFromOtherCnt        int `json:"feed_share_from_other_cnt"`
+		IntPageFromKanyikanReadUser  int `json:"int_page_from_kanyikan_read_user"`
+		IntPageFromKanyikanReadCount int `json:"int_page_from_kanyikan_read_count"`
+		IntPageFromSouyisouReadUser  int `json:"int_page_from_souyisou_read_user"`
+		IntPageFromSouyisouReadCount int `json:"int_page_from_souyisou_read_count"`
+	} `json:"details"`
+}
+
+func (api *DatacubeApi) GetArticleTotal(
+	ctx context.Context, begin, end time.Time,
+) ([]ArticleTotal, error) {
+	type resultList struct {
+		utils.WeixinError
+		List []ArticleTotal `json:"list"`
+	}
+
+	list := []ArticleTotal{}
+	if err := api.fetchRange(
+		ctx, apiGetArticleTotal, dateFormatOfficialAccount, begin, end, 1,
+		func() interface{} { return &resultList{} },
+		func(r interface{}) { list = append(list, r.(*resultList).List...) },
+	); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+/*
+获取图文统计数据, 最大时间跨度 3 天
+See: https://developers.weixin.qq.com/doc/offiaccount/Analytics/Graphic_Analysis_Data_Interface.html
+*/
+type UserRead struct {
+	RefDate    string `json:"ref_date"`
+	RefHour    int    `json:"ref_hour"`    // 分时统计才有, 1200代表12点
+	UserSource int    `json:"user_source"` // 用户从哪里进入来阅读该图文
+	ArticleStat
+}
+
+func (api *DatacubeApi) GetUserRead(
+	ctx context.Context, begin, end time.Time,
+) ([]UserRead, error) {
+	return api.getUserRead(ctx, apiGetUserRead, begin, end, 3)
+}
+
+/*
+获取图文统计分时数据, 最大时间跨度 1 天
+See: https://developers.weixin.qq.com/doc/offiaccount/Analytics/Graphic_Analysis_Data_Interface.html
+*/
+func (api *DatacubeApi) GetUserReadHour(
+	ctx context.Context, begin, end time.Time,
+) ([]UserRead, error) {
+	return api.getUserRead(ctx, apiGetUserReadHour, begin, end, 1)
+}
+
+func (api *DatacubeApi) getUserRead(
+	ctx context.Context, uri string, begin, end time.Time, maxDays int,
+) ([]UserRead, error) {
+	type resultList struct {
+		utils.WeixinError
+		List []UserRead `json:"list"`
+	}
+
+	list := []UserRead{}
+	if err := api.fetchRange(
+		ctx, uri, dateFormatOfficialAccount, begin, end, maxDays,
+		func() interface{} { return &resultList{} },
+		func(r interface{}) { list = append(list, r.(*resultList).List...) },
+	); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+/*
+获取图文分享转发数据, 最大时间跨度 7 天
+See: https://developers.weixin.qq.com/doc/offiaccount/Analytics/Graphic_Analysis_Data_Interface.html
+*/
+type UserShare struct {
+	RefDate    string `json:"ref_date"`
+	RefHour    int    `json:"ref_hour"`    // 分时统计才有
+	ShareScene int    `json:"share_scene"` // 分享的场景 1代表好友转发 2代表朋友圈 3代表腾讯微博 255代表其他
+	ShareCount int    `json:"share_count"`
+	ShareUser  int    `json:"share_user"`
+}
+
+func (api *DatacubeApi) GetUserShare(
+	ctx context.Context, begin, end time.Time,
+) ([]UserShare, error) {
+	return api.getUserShare(ctx, apiGetUserShare, begin, end, 7)
+}
+
+/*
+获取图文分享转发分时数据, 最大时间跨度 1 天
+See: https://developers.weixin.qq.com/doc/offiaccount/Analytics/Graphic_Analysis_Data_Interface.html
+*/
+func (api *DatacubeApi) GetUserShareHour(
+	ctx context.Context, begin, end time.Time,
+) ([]UserShare, error) {
+	return api.getUserShare(ctx, apiGetUserShareHour, begin, end, 1)
+}
+
+func (api *DatacubeApi) getUserShare(
+	ctx context.Context, uri string, begin, end time.Time, maxDays int,
+) ([]UserShare, error) {
+	type resultList struct {
+		utils.WeixinError
+		List []UserShare `json:"list"`
+	}
+
+	list := []UserShare{}
+	if err := api.fetchRange(
+		ctx, uri, dateFormatOfficialAccount, begin, end, maxDays,
+		func() interface{} { return &resultList{} },
+		func(r interface{}) { list = append(list, r.(*resultList).List...) },
+	); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+/*
+获取消息发送概况数据
+See: https://developers.weixin.qq.com/doc/offiaccount/Analytics/Message_analysis_data_interface.html
+*/
+type UpstreamMsg struct {
+	RefDate  string `json:"ref_date"`
+	RefHour  int    `json:"ref_hour"` // 分时统计才有
+	MsgType  int    `json:"msg_type"` // 消息类型，代表含义如下： 1代表文字 2代表图片 3代表语音 4代表视频 6代表第三方应用消息（链接消息）
+	MsgUser  int    `json:"msg_user"`
+	MsgCount int    `json:"msg_count"`
+}
+
+// GetUpstreamMsg 获取消息发送概况数据, 最大时间跨度 7 天
+func (api *DatacubeApi) GetUpstreamMsg(
+	ctx context.Context, begin, end time.Time,
+) ([]UpstreamMsg, error) {
+	return api.getUpstreamMsg(ctx, apiGetUpstreamMsg, begin, end, 7)
+}
+
+// GetUpstreamMsgHour 获取消息发送分时数据, 最大时间跨度 1 天
+func (api *DatacubeApi) GetUpstreamMsgHour(
+	ctx context.Context, begin, end time.Time,
+) ([]UpstreamMsg, error) {
+	return api.getUpstreamMsg(ctx, apiGetUpstreamMsgHour, begin, end, 1)
+}
+
+// GetUpstreamMsgWeek 获取消息发送周数据, 最大时间跨度 30 天
+func (api *DatacubeApi) GetUpstreamMsgWeek(
+	ctx context.Context, begin, end time.Time,
+) ([]UpstreamMsg, error) {
+	return api.getUpstreamMsg(ctx, apiGetUpstreamMsgWeek, begin, end, 30)
+}
+
+// GetUpstreamMsgMonth 获取消息发送月数据, 最大时间跨度 30 天
+func (api *DatacubeApi) GetUpstreamMsgMonth(
+	ctx context.Context, begin, end time.Time,
+) ([]UpstreamMsg, error) {
+	return api.getUpstreamMsg(ctx, apiGetUpstreamMsgMonth, begin, end, 30)
+}
+
+func (api *DatacubeApi) getUpstreamMsg(
+	ctx context.Context, uri string, begin, end time.Time, maxDays int,
+) ([]UpstreamMsg, error) {
+	type resultList struct {
+		utils.WeixinError
+		List []UpstreamMsg `json:"list"`
+	}
+
+	list := []UpstreamMsg{}
+	if err := api.fetchRange(
+		ctx, uri, dateFormatOfficialAccount, begin, end, maxDays,
+		func() interface{} { return &resultList{} },
+		func(r interface{}) { list = append(list, r.(*resultList).List...) },
+	); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+/*
+获取消息发送分布数据
+See: https://developers.weixin.qq.com/doc/offiaccount/Analytics/Message_analysis_data_interface.html
+*/
+type UpstreamMsgDist struct {
+	RefDate       string `json:"ref_date"`
+	CountInterval int    `json:"count_interval"` // 当日发送消息量分布的区间，0代表 “0”，1代表“1-5”，2代表“6-10”，3代表“10次以上”
+	MsgUser       int    `json:"msg_user"`
+}
+
+// GetUpstreamMsgDist 获取消息发送分布数据, 最大时间跨度 15 天
+func (api *DatacubeApi) GetUpstreamMsgDist(
+	ctx context.Context, begin, end time.Time,
+) ([]UpstreamMsgDist, error) {
+	return api.getUpstreamMsgDist(ctx, apiGetUpstreamMsgDist, begin, end, 15)
+}
+
+// GetUpstreamMsgDistWeek 获取消息发送分布周数据, 最大时间跨度 30 天
+func (api *DatacubeApi) GetUpstreamMsgDistWeek(
+	ctx context.Context, begin, end time.Time,
+) ([]UpstreamMsgDist, error) {
+	return api.getUpstreamMsgDist(ctx, apiGetUpstreamMsgDistWeek, begin, end, 30)
+}
+
+// GetUpstreamMsgDistMonth 获取消息发送分布月数据, 最大时间跨度 30 天
+func (api *DatacubeApi) GetUpstreamMsgDistMonth(
+	ctx context.Context, begin, end time.Time,
+) ([]UpstreamMsgDist, error) {
+	return api.getUpstreamMsgDist(ctx, apiGetUpstreamMsgDistMonth, begin, end, 30)
+}
+
+func (api *DatacubeApi) getUpstreamMsgDist(
+	ctx context.Context, uri string, begin, end time.Time, maxDays int,
+) ([]UpstreamMsgDist, error) {
+	type resultList struct {
+		utils.WeixinError
+		List []UpstreamMsgDist `json:"list"`
+	}
+
+	list := []UpstreamMsgDist{}
+	if err := api.fetchRange(
+		ctx, uri, dateFormatOfficialAccount, begin, end, maxDays,
+		func() interface{} { return &resultList{} },
+		func(r interface{}) { list = append(list, r.(*resultList).List...) },
+	); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+/*
+获取接口分析数据
+See: https://developers.weixin.qq.com/doc/offiaccount/Analytics/Analytics_API.html
+*/
+type InterfaceSummary struct {
+	RefDate       string `json:"ref_date"`
+	RefHour       int    `json:"ref_hour"`        // 分时统计才有
+	CallbackCount int    `json:"callback_count"`  // 通过服务器配置地址获得消息后，被动回复用户消息的次数
+	FailCount     int    `json:"fail_count"`      // 上述动作的失败次数
+	TotalTimeCost int    `json:"total_time_cost"` // 总耗时，除以callback_count即为平均耗时
+	MaxTimeCost   int    `json:"max_time_cost"`   // 最大耗时
+}
+
+// GetInterfaceSummary 获取接口分析数据, 最大时间跨度 30 天
+func (api *DatacubeApi) GetInterfaceSummary(
+	ctx context.Context, begin, end time.Time,
+) ([]InterfaceSummary, error) {
+	return api.getInterfaceSummary(ctx, apiGetInterfaceSummary, begin, end, 30)
+}
+
+// GetInterfaceSummaryHour 获取接口分析分时数据, 最大时间跨度 1 天
+func (api *DatacubeApi) GetInterfaceSummaryHour(
+	ctx context.Context, begin, end time.Time,
+) ([]InterfaceSummary, error) {
+	return api.getInterfaceSummary(ctx, apiGetInterfaceSummaryHour, begin, end, 1)
+}
+
+func (api *DatacubeApi) getInterfaceSummary(
+	ctx context.Context, uri string, begin, end time.Time, maxDays int,
+) ([]InterfaceSummary, error) {
+	type resultList struct {
+		utils.WeixinError
+		List []InterfaceSummary `json:"list"`
+	}
+
+	list := []InterfaceSummary{}
+	if err := api.fetchRange(
+		ctx, uri, dateFormatOfficialAccount, begin, end, maxDays,
+		func() interface{} { return &resultList{} },
+		func(r interface{}) { list = append(list, r.(*resultList).List...) },
+	); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
@@ -0,0 +1,322 @@
+package datacube_api
+
+import (
+	"context"
+	"time"
+
+	"github.com/lixinio/weixin/utils"
+)
+
+// 小程序 数据分析
+// https://developers.weixin.qq.com/miniprogram/dev/api-backend/open-api/data-analysis/visit-trend/analysis.getDailyVisitTrend.html
+// 所有接口 POST {"begin_date": "20170313", "end_date": "20170313"}
+// 日数据 每次只能查询一天, 周数据 必须是自然周(周一到周日), 月数据 必须是自然月
+
+const (
+	apiGetDailySummary      = "/datacube/getweanalysisappiddailysummarytrend"
+	apiGetDailyVisitTrend   = "/datacube/getweanalysisappiddailyvisittrend"
+	apiGetWeeklyVisitTrend  = "/datacube/getweanalysisappidweeklyvisittrend"
+	apiGetMonthlyVisitTrend = "/datacube/getweanalysisappidmonthlyvisittrend"
+	apiGetDailyRetain       = "/datacube/getweanalysisappiddailyretaininfo"
+	apiGetWeeklyRetain      = "/datacube/getweanalysisappidweeklyretaininfo"
+	apiGetMonthlyRetain     = "/datacube/getweanalysisappidmonthlyretaininfo"
+	apiGetVisitDistribution = "/datacube/getweanalysisappidvisitdistribution"
+	apiGetVisitPage         = "/datacube/getweanalysisappidvisitpage"
+	apiGetUserPortrait      = "/datacube/getweanalysisappiduserportrait"
+	apiGetPerformanceData   = "/wxa/business/performance"
+)
+
+/*
+获取用户访问小程序数据概况, 按天拆分查询
+See: https://developers.weixin.qq.com/miniprogram/dev/api-backend/open-api/data-analysis/analysis.getDailySummary.html
+*/
+type DailySummary struct {
+	RefDate    string `json:"ref_date"`
+	VisitTotal int    `json:"visit_total"` // 累计用户数
+	SharePV    int    `json:"share_pv"`    // 转发次数
+	ShareUV    int    `json:"share_uv"`    // 转发人数
+}
+
+func (api *DatacubeApi) GetWxaDailySummary(
+	ctx context.Context, begin, end time.Time,
+) ([]DailySummary, error) {
+	type resultList struct {
+		utils.WeixinError
+		List []DailySummary `json:"list"`
+	}
+
+	list := []DailySummary{}
+	if err := api.fetchRange(
+		ctx, apiGetDailySummary, dateFormatWxa, begin, end, 1,
+		func() interface{} { return &resultList{} },
+		func(r interface{}) { list = append(list, r.(*resultList).List...) },
+	); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+/*
+获取用户访问小程序数据日趋势, 按天拆分查询
+See: https://developers.weixin.qq.com/miniprogram/dev/api-backend/open-api/data-analysis/visit-trend/analysis.getDailyVisitTrend.html
+*/
+type VisitTrend struct {
+	RefDate         string  `json:"ref_date"`
+	SessionCnt      int     `json:"session_cnt"`       // 打开次数
+	VisitPV         int     `json:"visit_pv"`          // 访问次数
+	VisitUV         int     `json:"visit_uv"`          // 访问人数
+	VisitUVNew      int     `json:"visit_uv_new"`      // 新用户数
+	StayTimeUV      float64 `json:"stay_time_uv"`      // 人均停留时长 (浮点型，单位：秒)
+	StayTimeSession float64 `json:"stay_time_session"` // 次均停留时长 (浮点型，单位：秒)
+	VisitDepth      float64 `json:"visit_depth"`       // 平均访问深度 (浮点型)
+}
+
+func (api *DatacubeApi) GetWxaDailyVisitTrend(
+	ctx context.Context, begin, end time.Time,
+) ([]VisitTrend, error) {
+	return api.getVisitTrend(ctx, apiGetDailyVisitTrend, begin, end, splitDays)
+}
+
+// GetWxaWeeklyVisitTrend 周趋势, begin 为周一, end 为周日, 按自然周拆分查询
+func (api *DatacubeApi) GetWxaWeeklyVisitTrend(
+	ctx context.Context, begin, end time.Time,
+) ([]VisitTrend, error) {
+	return api.getVisitTrend(ctx, apiGetWeeklyVisitTrend, begin, end, splitWeeks)
+}
+
+// GetWxaMonthlyVisitTrend 月趋势, begin 为自然月第一天, end 为自然月最后一天, 按自然月拆分查询
+func (api *DatacubeApi) GetWxaMonthlyVisitTrend(
+	ctx context.Context, begin, end time.Time,
+) ([]VisitTrend, error) {
+	return api.getVisitTrend(ctx, apiGetMonthlyVisitTrend, begin, end, splitMonths)
+}
+
+func (api *DatacubeApi) getVisitTrend(
+	ctx context.Context, uri string, begin, end time.Time,
+	split func(begin, end time.Time) ([]dateRange, error),
+) ([]VisitTrend, error) {
+	type resultList struct {
+		utils.WeixinError
+		List []VisitTrend `json:"list"`
+	}
+
+	ranges, err := split(begin, end)
+	if err != nil {
+		return nil, err
+	}
+
+	list := []VisitTrend{}
+	if err := api.fetchRanges(
+		ctx, uri, dateFormatWxa, ranges,
+		func() interface{} { return &resultList{} },
+		func(r interface{}) { list = append(list, r.(*resultList).List...) },
+	); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+type KeyValue struct {
+	Key   int `json:"key"`
+	Value int `json:"value"`
+}
+
+/*
+获取用户访问小程序留存
+See: https://developers.weixin.qq.com/miniprogram/dev/api-backend/open-api/data-analysis/visit-retain/analysis.getDailyRetain.html
+*/
+type RetainInfo struct {
+	utils.WeixinError
+	RefDate    string     `json:"ref_date"`
+	VisitUVNew []KeyValue `json:"visit_uv_new"` // 新增用户留存, key 标识第几天/周/月, value 为人数
+	VisitUV    []KeyValue `json:"visit_uv"`     // 活跃用户留存
+}
+
+// GetWxaDailyRetain 日留存, 按天拆分查询
+func (api *DatacubeApi) GetWxaDailyRetain(
+	ctx context.Context, begin, end time.Time,
+) ([]RetainInfo, error) {
+	return api.getRetain(ctx, apiGetDailyRetain, begin, end, splitDays)
+}
+
+// GetWxaWeeklyRetain 周留存, begin 为周一, end 为周日, 按自然周拆分查询
+func (api *DatacubeApi) GetWxaWeeklyRetain(
+	ctx context.Context, begin, end time.Time,
+) ([]RetainInfo, error) {
+	return api.getRetain(ctx, apiGetWeeklyRetain, begin, end, splitWeeks)
+}
+
+// GetWxaMonthlyRetain 月留存, begin 为自然月第一天, end 为自然月最后一天, 按自然月拆分查询
+func (api *DatacubeApi) GetWxaMonthlyRetain(
+	ctx context.Context, begin, end time.Time,
+) ([]RetainInfo, error) {
+	return api.getRetain(ctx, apiGetMonthlyRetain, begin, end, splitMonths)
+}
+
+func (api *DatacubeApi) getRetain(
+	ctx context.Context, uri string, begin, end time.Time,
+	split func(begin, end time.Time) ([]dateRange, error),
+) ([]RetainInfo, error) {
+	ranges, err := split(begin, end)
+	if err != nil {
+		return nil, err
+	}
+
+	list := []RetainInfo{}
+	if err := api.fetchRanges(
+		ctx, uri, dateFormatWxa, ranges,
+		func() interface{} { return &RetainInfo{} },
+		func(r interface{}) { list = append(list, *r.(*RetainInfo)) },
+	); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+/*
+获取用户小程序访问分布数据, 按天拆分查询
+See: https://developers.weixin.qq.com/miniprogram/dev/api-backend/open-api/data-analysis/analysis.getVisitDistribution.html
+*/
+type VisitDistribution struct {
+	utils.WeixinError
+	RefDate string `json:"ref_date"`
+	List    []struct {
+		Index    string     `json:"index"` // 分布类型 access_source_session_cnt / access_staytime_info / access_depth_info
+		ItemList []KeyValue `json:"item_list"`
+	} `json:"list"`
+}
+
+func (api *DatacubeApi) GetWxaVisitDistribution(
+	ctx context.Context, begin, end time.Time,
+) ([]VisitDistribution, error) {
+	list := []VisitDistribution{}
+	if err := api.fetchRange(
+		ctx, apiGetVisitDistribution, dateFormatWxa, begin, end, 1,
+		func() interface{} { return &VisitDistribution{} },
+		func(r interface{}) { list = append(list, *r.(*VisitDistribution)) },
+	); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+/*
+访问页面, 按天拆分查询
+See: https://developers.weixin.qq.com/miniprogram/dev/api-backend/open-api/data-analysis/analysis.getVisitPage.html
+*/
+type VisitPage struct {
+	utils.WeixinError
+	RefDate string `json:"ref_date"`
+	List    []struct {
+		PagePath       string  `json:"page_path"`
+		PageVisitPV    int     `json:"page_visit_pv"`
+		PageVisitUV    int     `json:"page_visit_uv"`
+		PageStaytimePV float64 `json:"page_staytime_pv"`
+		EntrypagePV    int     `json:"entrypage_pv"`
+		ExitpagePV     int     `json:"exitpage_pv"`
+		PageSharePV    int     `json:"page_share_pv"`
+		PageShareUV    int     `json:"page_share_uv"`
+	} `json:"list"`
+}
+
+func (api *DatacubeApi) GetWxaVisitPage(
+	ctx context.Context, begin, end time.Time,
+) ([]VisitPage, error) {
+	list := []VisitPage{}
+	if err := api.fetchRange(
+		ctx, apiGetVisitPage, dateFormatWxa, begin, end, 1,
+		func() interface{} { return &VisitPage{} },
+		func(r interface{}) { list = append(list, *r.(*VisitPage)) },
+	); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+type PortraitItem struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Value int    `json:"value"`
+}
+
+type Portrait struct {
+	Province  []PortraitItem `json:"province"`
+	City      []PortraitItem `json:"city"`
+	Genders   []PortraitItem `json:"genders"`
+	Platforms []PortraitItem `json:"platforms"`
+	Devices   []PortraitItem `json:"devices"`
+	Ages      []PortraitItem `json:"ages"`
+}
+
+type UserPortrait struct {
+	utils.WeixinError
+	RefDate    string   `json:"ref_date"`
+	VisitUVNew Portrait `json:"visit_uv_new"`
+	VisitUV    Portrait `json:"visit_uv"`
+}
+
+/*
+获取小程序用户画像分布数据
+时间范围支持昨天、最近7天、最近30天, 不做拆分
+See: https://developers.weixin.qq.com/miniprogram/dev/api-backend/open-api/data-analysis/analysis.getUserPortrait.html
+*/
+func (api *DatacubeApi) GetWxaUserPortrait(
+	ctx context.Context, begin, end time.Time,
+) (*UserPortrait, error) {
+	param := &struct {
+		BeginDate string `json:"begin_date"`
+		EndDate   string `json:"end_date"`
+	}{begin.Format(dateFormatWxa), end.Format(dateFormatWxa)}
+
+	result := &UserPortrait{}
+	if err := api.Client.HTTPPostJson(ctx, apiGetUserPortrait, param, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type PerformanceField struct {
+	Field string `json:"field"` // 查询条件, 例如 networktype / device_level / device
+	Value string `json:"value"`
+}
+
+type PerformanceParams struct {
+	Module string `json:"module"` // 查询数据的类型, 例如 10021: 打开率, 10022: 启动各阶段耗时
+	Time   struct {
+		BeginTimestamp int64 `json:"begin_timestamp"`
+		EndTimestamp   int64 `json:"end_timestamp"`
+	} `json:"time"`
+	Params []PerformanceField `json:"params"`
+}
+
+type PerformanceData struct {
+	utils.WeixinError
+	Body struct {
+		Tables []struct {
+			ID    string `json:"id"`
+			Zh    string `json:"zh"`
+			Lines []struct {
+				Fields []struct {
+					Refdate string `json:"refdate"`
+					Value   string `json:"value"`
+				} `json:"fields"`
+			} `json:"lines"`
+		} `json:"tables"`
+		Count int `json:"count"`
+	} `json:"body"`
+}
+
+/*
+获取小程序启动性能，运行性能等数据
+See: https://developers.weixin.qq.com/miniprogram/dev/api-backend/open-api/data-analysis/analysis.getPerformanceData.html
+POST https://api.weixin.qq.com/wxa/business/performance?access_token=ACCESS_TOKEN
+*/
+func (api *DatacubeApi) GetWxaPerformanceData(
+	ctx context.Context, params *PerformanceParams,
+) (*PerformanceData, error) {
+	result := &PerformanceData{}
+	if err := api.Client.HTTPPostJson(ctx, apiGetPerformanceData, params, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,167 @@
+package draft_api
+
+// Package draft 草稿箱 & 发布能力
+
+import (
+	"context"
+
+	"github.com/lixinio/weixin/utils"
+)
+
+const (
+	apiDraftAdd      = "/cgi-bin/draft/add"
+	apiDraftGet      = "/cgi-bin/draft/get"
+	apiDraftDelete   = "/cgi-bin/draft/delete"
+	apiDraftUpdate   = "/cgi-bin/draft/update"
+	apiDraftCount    = "/cgi-bin/draft/count"
+	apiDraftBatchGet = "/cgi-bin/draft/batchget"
+)
+
+type DraftApi struct {
+	*utils.Client
+}
+
+func NewApi(client *utils.Client) *DraftApi {
+	return &DraftApi{Client: client}
+}
+
+type Article struct {
+	Title              string `json:"title"`
+	Author             string `json:"author,omitempty"`
+	Digest             string `json:"digest,omitempty"` // 图文消息的摘要，仅有单图文消息才有摘要，多图文此处为空
+	Content            string `json:"content"`          // 图文消息的具体内容，支持HTML标签，必须少于2万字符，小于1M
+	ContentSourceURL   string `json:"content_source_url,omitempty"`
+	ThumbMediaID       string `json:"thumb_media_id"` // 图文消息的封面图片素材id（必须是永久MediaID）
+	NeedOpenComment    int    `json:"need_open_comment"`
+	OnlyFansCanComment int    `json:"only_fans_can_comment"`
+}
+
+type NewsItem struct {
+	Article
+	URL       string `json:"url"`
+	ThumbURL  string `json:"thumb_url"`
+	IsDeleted bool   `json:"is_deleted"` // 该图文是否被删除, 只有获取已发布图文时才有
+}
+
+type mediaIDParam struct {
+	MediaID string `json:"media_id"`
+}
+
+/*
+新建草稿
+See: https://developers.weixin.qq.com/doc/offiaccount/Draft_Box/Add_draft.html
+POST https://api.weixin.qq.com/cgi-bin/draft/add?access_token=ACCESS_TOKEN
+*/
+func (api *DraftApi) AddDraft(ctx context.Context, articles []Article) (string, error) {
+	param := &struct {
+		Articles []Article `json:"articles"`
+	}{articles}
+
+	result := &struct {
+		utils.WeixinError
+		MediaID string `json:"media_id"`
+	}{}
+	if err := api.Client.HTTPPostJson(ctx, apiDraftAdd, param, result); err != nil {
+		return "", err
+	}
+	return result.MediaID, nil
+}
+
+/*
+获取草稿
+See: https://developers.weixin.qq.com/doc/offiaccount/Draft_Box/Get_draft.html
+POST https://api.weixin.qq.com/cgi-bin/draft/get?access_token=ACCESS_TOKEN
+*/
+func (api *DraftApi) GetDraft(ctx context.Context, mediaID string) ([]NewsItem, error) {
+	result := &struct {
+		utils.WeixinError
+		NewsItem []NewsItem `json:"news_item"`
+	}{}
+	if err := api.Client.HTTPPostJson(ctx, apiDraftGet, &mediaIDParam{mediaID}, result); err != nil {
+		return nil, err
+	}
+	return result.NewsItem, nil
+}
+
+/*
+删除草稿
+See: https://developers.weixin.qq.com/doc/offiaccount/Draft_Box/Delete_draft.html
+POST https://api.weixin.qq.com/cgi-bin/draft/delete?access_token=ACCESS_TOKEN
+*/
+func (api *DraftApi) DeleteDraft(ctx context.Context, mediaID string) error {
+	return api.Client.HTTPPostJson(ctx, apiDraftDelete, &mediaIDParam{mediaID}, nil)
+}
+
+/*
+修改草稿
+index 要更新的文章在图文消息中的位置（多图文消息时，此字段才有意义），第一篇为0
+See: https://developers.weixin.qq.com/doc/offiaccount/Draft_Box/Update_draft.html
+POST https://api.weixin.qq.com/cgi-bin/draft/update?access_token=ACCESS_TOKEN
+*/
+func (api *DraftApi) UpdateDraft(
+	ctx context.Context, mediaID string, index int, article *Article,
+) error {
+	param := &struct {
+		MediaID  string   `json:"media_id"`
+		Index    int      `json:"index"`
+		Articles *Article `json:"articles"`
+	}{mediaID, index, article}
+	return api.Client.HTTPPostJson(ctx, apiDraftUpdate, param, nil)
+}
+
+/*
+获取草稿总数
+See: https://developers.weixin.qq.com/doc/offiaccount/Draft_Box/Count_drafts.html
+GET https://api.weixin.qq.com/cgi-bin/draft/count?access_token=ACCESS_TOKEN
+*/
+func (api *DraftApi) CountDraft(ctx context.Context) (int, error) {
+	result := &struct {
+		utils.WeixinError
+		TotalCount int `json:"total_count"`
+	}{}
+	if err := api.Client.HTTPGet(ctx, apiDraftCount, result); err != nil {
+		return 0, err
+	}
+	return result.TotalCount, nil
+}
+
+type batchGetParam struct {
+	Offset    int `json:"offset"`
+	Count     int `json:"count"`      // 返回素材的数量，取值在1到20之间
+	NoContent int `json:"no_content"` // 1 表示不返回 content 字段，0 表示正常返回，默认为 0
+}
+
+type DraftItem struct {
+	MediaID string `json:"media_id"`
+	Content struct {
+		NewsItem []NewsItem `json:"news_item"`
+	} `json:"content"`
+	UpdateTime int64 `json:"update_time"`
+}
+
+type DraftList struct {
+	utils.WeixinError
+	TotalCount int         `json:"total_count"`
+	ItemCount  int         `json:"item_count"`
+	Item       []DraftItem `json:"item"`
+}
+
+/*
+获取草稿列表
+See: https://developers.weixin.qq.com/doc/offiaccount/Draft_Box/Get_draft_list.html
+POST https://api.weixin.qq.com/cgi-bin/draft/batchget?access_token=ACCESS_TOKEN
+*/
+func (api *DraftApi) BatchGetDraft(
+	ctx context.Context, offset, count int, noContent bool,
+) (*DraftList, error) {
+	param := &batchGetParam{Offset: offset, Count: count}
+	if noContent {
+		param.NoContent = 1
+	}
+
+	result := &DraftList{}
+	if err := api.Client.HTTPPostJson(ctx, apiDraftBatchGet, param, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,81 @@
+package draft_api
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/lixinio/weixin/test"
+	"github.com/lixinio/weixin/utils"
+	"github.com/lixinio/weixin/utils/redis"
+	"github.com/lixinio/weixin/weixin/material_api"
+	"github.com/lixinio/weixin/weixin/official_account"
+	"github.com/stretchr/testify/require"
+)
+
+func initOfficialAccount() *utils.Client {
+	redis := redis.NewRedis(&redis.Config{RedisUrl: test.CacheUrl})
+	officialAccount := official_account.New(redis, redis, &official_account.Config{
+		Appid:  test.OfficialAccountAppid,
+		Secret: test.OfficialAccountSecret,
+	})
+	return officialAccount.Client
+}
+
+func TestDraft(t *testing.T) {
+	ctx := context.Background()
+	client := initOfficialAccount()
+	draftApi := NewApi(client)
+
+	// 封面必须是永久素材
+	file, err := os.Open(test.ImagePath)
+	require.Equal(t, nil, err)
+	defer file.Close()
+	thumb, err := material_api.NewApi(client).AddMaterial(
+		ctx, test.ImagePath, file, material_api.MediaTypeImage,
+	)
+	require.Equal(t, nil, err)
+
+	mediaID, err := draftApi.AddDraft(ctx, []Article{
+		{
+			Title:        "标题",
+			Content:      "<p>内容</p>",
+			ThumbMediaID: thumb.MediaID,
+		},
+	})
+	require.Equal(t, nil, err)
+
+	{
+		err := draftApi.UpdateDraft(ctx, mediaID, 0, &Article{
+			Title:        "新标题",
+			Content:      "<p>内容</p>",
+			ThumbMediaID: thumb.MediaID,
+		})
+		require.Equal(t, nil, err)
+	}
+
+	{
+		items, err := draftApi.GetDraft(ctx, mediaID)
+		require.Equal(t, nil, err)
+		require.Equal(t, 1, len(items))
+		require.Equal(t, "新标题", items[0].Title)
+	}
+
+	{
+		count, err := draftApi.CountDraft(ctx)
+		require.Equal(t, nil, err)
+		require.Greater(t, count, 0)
+
+		list, err := draftApi.BatchGetDraft(ctx, 0, 20, true)
+		require.Equal(t, nil, err)
+		require.Equal(t, count, list.TotalCount)
+	}
+
+	{
+		_, err := draftApi.BatchGetPublish(ctx, 0, 20, true)
+		require.Equal(t, nil, err)
+	}
+
+	err = draftApi.DeleteDraft(ctx, mediaID)
+	require.Equal(t, nil, err)
+}
@@ -0,0 +1,152 @@
+package draft_api
+
+import (
+	"context"
+
+	"github.com/lixinio/weixin/utils"
+)
+
+const (
+	apiFreePublishSubmit     = "/cgi-bin/freepublish/submit"
+	apiFreePublishGet        = "/cgi-bin/freepublish/get"
+	apiFreePublishDelete     = "/cgi-bin/freepublish/delete"
+	apiFreePublishGetArticle = "/cgi-bin/freepublish/getarticle"
+	apiFreePublishBatchGet   = "/cgi-bin/freepublish/batchget"
+)
+
+// 发布状态
+const (
+	PublishStatusSuccess      = 0 // 成功
+	PublishStatusPublishing   = 1 // 发布中
+	PublishStatusOriginalFail = 2 // 原创失败
+	PublishStatusFail         = 3 // 常规失败
+	PublishStatusAuditFail    = 4 // 平台审核不通过
+	PublishStatusDeleted      = 5 // 成功后用户删除所有文章
+	PublishStatusBlocked      = 6 // 成功后系统封禁所有文章
+)
+
+type PublishResult struct {
+	utils.WeixinError
+	PublishID string `json:"publish_id"`
+	MsgDataID string `json:"msg_data_id"`
+}
+
+/*
+发布接口
+开发者需要先将图文素材以草稿的形式保存, 选择要发布的草稿 media_id 进行发布
+发布任务完成后, 会推送 PUBLISHJOBFINISH 事件, 参考 server_api.EventPublishJobFinish
+See: https://developers.weixin.qq.com/doc/offiaccount/Publish/Publish.html
+POST https://api.weixin.qq.com/cgi-bin/freepublish/submit?access_token=ACCESS_TOKEN
+*/
+func (api *DraftApi) Publish(ctx context.Context, mediaID string) (*PublishResult, error) {
+	result := &PublishResult{}
+	if err := api.Client.HTTPPostJson(
+		ctx, apiFreePublishSubmit, &mediaIDParam{mediaID}, result,
+	); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type PublishStatus struct {
+	utils.WeixinError
+	PublishID     string `json:"publish_id"`
+	PublishStatus int    `json:"publish_status"`
+	ArticleID     string `json:"article_id"`
+	ArticleDetail struct {
+		Count int `json:"count"`
+		Item  []struct {
+			Idx        int    `json:"idx"`
+			ArticleUrl string `json:"article_url"`
+		} `json:"item"`
+	} `json:"article_detail"`
+	FailIdx []int `json:"fail_idx"` // 当发布状态为2或4时，返回不通过的文章编号，第一篇为 1
+}
+
+/*
+发布状态轮询接口
+See: https://developers.weixin.qq.com/doc/offiaccount/Publish/Get_status.html
+POST https://api.weixin.qq.com/cgi-bin/freepublish/get?access_token=ACCESS_TOKEN
+*/
+func (api *DraftApi) GetPublishStatus(
+	ctx context.Context, publishID string,
+) (*PublishStatus, error) {
+	param := &struct {
+		PublishID string `json:"publish_id"`
+	}{publishID}
+
+	result := &PublishStatus{}
+	if err := api.Client.HTTPPostJson(ctx, apiFreePublishGet, param, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+/*
+删除发布
+index 要删除的文章在图文消息中的位置，第一篇编号为1，该字段不填或填0会删除全部文章
+See: https://developers.weixin.qq.com/doc/offiaccount/Publish/Delete_posts.html
+POST https://api.weixin.qq.com/cgi-bin/freepublish/delete?access_token=ACCESS_TOKEN
+*/
+func (api *DraftApi) DeletePublish(ctx context.Context, articleID string, index int) error {
+	param := &struct {
+		ArticleID string `json:"article_id"`
+		Index     int    `json:"index,omitempty"`
+	}{articleID, index}
+	return api.Client.HTTPPostJson(ctx, apiFreePublishDelete, param, nil)
+}
+
+/*
+通过 article_id 获取已发布文章
+See: https://developers.weixin.qq.com/doc/offiaccount/Publish/Get_article_from_id.html
+POST https://api.weixin.qq.com/cgi-bin/freepublish/getarticle?access_token=ACCESS_TOKEN
+*/
+func (api *DraftApi) GetArticle(ctx context.Context, articleID string) ([]NewsItem, error) {
+	param := &struct {
+		ArticleID string `json:"article_id"`
+	}{articleID}
+
+	result := &struct {
+		utils.WeixinError
+		NewsItem []NewsItem `json:"news_item"`
+	}{}
+	if err := api.Client.HTTPPostJson(ctx, apiFreePublishGetArticle, param, result); err != nil {
+		return nil, err
+	}
+	return result.NewsItem, nil
+}
+
+type PublishItem struct {
+	ArticleID string `json:"article_id"`
+	Content   struct {
+		NewsItem []NewsItem `json:"news_item"`
+	} `json:"content"`
+	UpdateTime int64 `json:"update_time"`
+}
+
+type PublishList struct {
+	utils.WeixinError
+	TotalCount int           `json:"total_count"`
+	ItemCount  int           `json:"item_count"`
+	Item       []PublishItem `json:"item"`
+}
+
+/*
+获取成功发布列表
+See: https://developers.weixin.qq.com/doc/offiaccount/Publish/Get_publication_records.html
+POST https://api.weixin.qq.com/cgi-bin/freepublish/batchget?access_token=ACCESS_TOKEN
+*/
+func (api *DraftApi) BatchGetPublish(
+	ctx context.Context, offset, count int, noContent bool,
+) (*PublishList, error) {
+	param := &batchGetParam{Offset: offset, Count: count}
+	if noContent {
+		param.NoContent = 1
+	}
+
+	result := &PublishList{}
+	if err := api.Client.HTTPPostJson(ctx, apiFreePublishBatchGet, param, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,134 @@
+package identity
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/lixinio/weixin/utils/oauthflow"
+	"github.com/lixinio/weixin/weixin/official_account"
+	"github.com/lixinio/weixin/weixin/snstoken"
+	"github.com/lixinio/weixin/weixin/user_api"
+	"github.com/lixinio/weixin/wxopen"
+	wxwork_user_api "github.com/lixinio/weixin/wxwork/user_api"
+)
+
+// RecordOAuth 记录网页授权得到的身份
+// 企业微信自建应用没有 open_userid, 只记录 corpid 下的 userid
+func (r *Resolver) RecordOAuth(ctx context.Context, identity *oauthflow.Identity) error {
+	if identity.CorpID != "" {
+		return r.Link(
+			ctx,
+			UserID(identity.CorpID, identity.UserID),
+			OpenUserID(identity.CorpID, identity.OpenUserID),
+		)
+	}
+	return r.Link(
+		ctx, OpenID(identity.AppID, identity.OpenID), UnionID(identity.UnionID),
+	)
+}
+
+// OAuthSuccess 包装 oauthflow.Config.OnSuccess, 授权成功时自动记录身份
+func (r *Resolver) OAuthSuccess(
+	next func(w http.ResponseWriter, req *http.Request, identity *oauthflow.Identity, returnTo string),
+) func(w http.ResponseWriter, req *http.Request, identity *oauthflow.Identity, returnTo string) {
+	return func(
+		w http.ResponseWriter, req *http.Request, identity *oauthflow.Identity, returnTo string,
+	) {
+		// 记录失败不影响登录
+		_ = r.RecordOAuth(req.Context(), identity)
+		next(w, req, identity, returnTo)
+	}
+}
+
+// RecordSnsToken 记录网页授权 access_token 中的身份
+func (r *Resolver) RecordSnsToken(ctx context.Context, appID string, token *snstoken.Token) error {
+	return r.Link(ctx, OpenID(appID, token.Openid), UnionID(token.Unionid))
+}
+
+// RecordUser 记录公众号用户信息中的身份
+func (r *Resolver) RecordUser(ctx context.Context, appID string, user *user_api.User) error {
+	return r.Link(ctx, OpenID(appID, user.OpenID), UnionID(user.UnionID))
+}
+
+// GetUserInfo 获取公众号用户信息并记录身份
+func (r *Resolver) GetUserInfo(
+	ctx context.Context, appID string, api *user_api.UserApi, openid, lang string,
+) (*user_api.UserInfo, error) {
+	userInfo, err := api.GetUserInfo(ctx, openid, lang)
+	if err != nil {
+		return nil, err
+	}
+	if err = r.RecordUser(ctx, appID, &userInfo.User); err != nil {
+		return nil, err
+	}
+	return userInfo, nil
+}
+
+// RecordMpSession 记录小程序登录得到的身份
+func (r *Resolver) RecordMpSession(ctx context.Context, appID, openid, unionid string) error {
+	return r.Link(ctx, OpenID(appID, openid), UnionID(unionid))
+}
+
+// Jscode2Session 小程序登录并记录身份
+func (r *Resolver) Jscode2Session(
+	ctx context.Context, wxa *official_account.OfficialAccount, jsCode string,
+) (*official_account.MpSession, error) {
+	session, err := wxa.Jscode2Session(ctx, jsCode)
+	if err != nil {
+		return nil, err
+	}
+	if err = r.RecordMpSession(ctx, wxa.Config.Appid, session.OpenID, session.UnionID); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// ComponentJscode2Session 第三方平台代小程序登录并记录身份
+func (r *Resolver) ComponentJscode2Session(
+	ctx context.Context, api *wxopen.WxOpen, authorizerAppID, jsCode string,
+) (*wxopen.MpSession, error) {
+	session, err := api.Jscode2Session(ctx, authorizerAppID, jsCode)
+	if err != nil {
+		return nil, err
+	}
+	if err = r.RecordMpSession(ctx, authorizerAppID, session.OpenID, session.UnionID); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// ConvertToOpenId 企业微信 userid 转 openid, 优先使用已记录的关联
+func (r *Resolver) ConvertToOpenId(
+	ctx context.Context, corpID string, api *wxwork_user_api.UserApi, userid string,
+) (string, error) {
+	if openid, err := r.GetOpenID(ctx, UserID(corpID, userid), corpID); err == nil {
+		return openid, nil
+	}
+
+	openid, err := api.ConvertToOpenId(ctx, userid)
+	if err != nil {
+		return "", err
+	}
+	if err = r.Link(ctx, UserID(corpID, userid), OpenID(corpID, openid)); err != nil {
+		return "", err
+	}
+	return openid, nil
+}
+
+// ConvertToUserId 企业微信 openid 转 userid, 优先使用已记录的关联
+func (r *Resolver) ConvertToUserId(
+	ctx context.Context, corpID string, api *wxwork_user_api.UserApi, openid string,
+) (string, error) {
+	if userid, err := r.GetUserID(ctx, OpenID(corpID, openid), corpID); err == nil {
+		return userid, nil
+	}
+
+	userid, err := api.ConvertToUserId(ctx, openid)
+	if err != nil {
+		return "", err
+	}
+	if err = r.Link(ctx, UserID(corpID, userid), OpenID(corpID, openid)); err != nil {
+		return "", err
+	}
+	return userid, nil
+}
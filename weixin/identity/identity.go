@@ -0,0 +1,145 @@
+package identity
+
+// Package identity 关联同一个用户在公众号/小程序/网站应用/企业微信下的不同身份
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Kind 身份类型
+type Kind string
+
+const (
+	KindOpenID         Kind = "openid"          // 公众号/小程序/网站应用 openid, 企业微信 userid 转换的 openid, Scope 为 appid 或 corpid
+	KindUnionID        Kind = "unionid"         // 开放平台 unionid, Scope 可为空
+	KindUserID         Kind = "userid"          // 企业微信成员 userid, Scope 为 corpid
+	KindOpenUserID     Kind = "open_userid"     // 企业微信第三方应用 open_userid, Scope 为 corpid
+	KindExternalUserID Kind = "external_userid" // 企业微信外部联系人, Scope 为 corpid
+)
+
+const maxDepth = 8 // 关联查找的最大层数
+
+var ErrNotFound = errors.New("identity not found")
+
+// ID 某个作用域(appid/corpid)下的用户身份
+type ID struct {
+	Kind  Kind   `json:"kind"`
+	Scope string `json:"scope,omitempty"`
+	Value string `json:"value"`
+}
+
+func (id ID) String() string {
+	return fmt.Sprintf("%s:%s:%s", id.Kind, id.Scope, id.Value)
+}
+
+func OpenID(appID, openid string) ID {
+	return ID{Kind: KindOpenID, Scope: appID, Value: openid}
+}
+
+func UnionID(unionid string) ID {
+	return ID{Kind: KindUnionID, Value: unionid}
+}
+
+func UserID(corpID, userid string) ID {
+	return ID{Kind: KindUserID, Scope: corpID, Value: userid}
+}
+
+func OpenUserID(corpID, openUserID string) ID {
+	return ID{Kind: KindOpenUserID, Scope: corpID, Value: openUserID}
+}
+
+func ExternalUserID(corpID, externalUserID string) ID {
+	return ID{Kind: KindExternalUserID, Scope: corpID, Value: externalUserID}
+}
+
+// Resolver 记录和查询身份关联
+type Resolver struct {
+	store Store
+}
+
+func New(store Store) *Resolver {
+	return &Resolver{store: store}
+}
+
+// Link 关联多个身份, 空值忽略; 只有一个身份时也会记录, 例如企业微信自建应用登录的成员
+func (r *Resolver) Link(ctx context.Context, ids ...ID) error {
+	valid := make([]ID, 0, len(ids))
+	for _, id := range ids {
+		if id.Value != "" {
+			valid = append(valid, id)
+		}
+	}
+	if len(valid) == 1 {
+		return r.store.Add(ctx, valid[0])
+	}
+	for i := 1; i < len(valid); i++ {
+		if err := r.store.Link(ctx, valid[0], valid[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Exists 身份是否已经记录
+func (r *Resolver) Exists(ctx context.Context, id ID) (bool, error) {
+	return r.store.Exists(ctx, id)
+}
+
+// Resolve 查找与 from 关联的指定类型和作用域的身份, scope 为空时不限制作用域
+func (r *Resolver) Resolve(ctx context.Context, from ID, kind Kind, scope string) (ID, error) {
+	match := func(id ID) bool {
+		return id.Kind == kind && (scope == "" || id.Scope == scope)
+	}
+
+	// 广度优先, 优先返回关系最近的身份
+	visited := map[ID]bool{from: true}
+	current := []ID{from}
+	for depth := 0; depth < maxDepth && len(current) > 0; depth++ {
+		next := []ID{}
+		for _, id := range current {
+			neighbors, err := r.store.Neighbors(ctx, id)
+			if err != nil {
+				return ID{}, err
+			}
+			for _, neighbor := range neighbors {
+				if visited[neighbor] {
+					continue
+				}
+				if match(neighbor) {
+					return neighbor, nil
+				}
+				visited[neighbor] = true
+				next = append(next, neighbor)
+			}
+		}
+		current = next
+	}
+	return ID{}, ErrNotFound
+}
+
+func (r *Resolver) resolveValue(
+	ctx context.Context, from ID, kind Kind, scope string,
+) (string, error) {
+	id, err := r.Resolve(ctx, from, kind, scope)
+	if err != nil {
+		return "", err
+	}
+	return id.Value, nil
+}
+
+// GetUnionID 根据 appid 下的 openid 获取 unionid
+func (r *Resolver) GetUnionID(ctx context.Context, appID, openid string) (string, error) {
+	return r.resolveValue(ctx, OpenID(appID, openid), KindUnionID, "")
+}
+
+// GetOpenID 获取 from 在 appid 下的 openid
+func (r *Resolver) GetOpenID(ctx context.Context, from ID, appID string) (string, error) {
+	return r.resolveValue(ctx, from, KindOpenID, appID)
+}
+
+// GetUserID 获取 from 在企业微信 corpid 下的 userid
+func (r *Resolver) GetUserID(ctx context.Context, from ID, corpID string) (string, error) {
+	return r.resolveValue(ctx, from, KindUserID, corpID)
+}
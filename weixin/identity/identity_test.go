@@ -0,0 +1,84 @@
+package identity
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lixinio/weixin/utils/memcache"
+	"github.com/lixinio/weixin/utils/oauthflow"
+	"github.com/lixinio/weixin/weixin/user_api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolver(t *testing.T) {
+	for _, store := range []Store{
+		NewMemoryStore(),
+		NewCacheStore(memcache.NewMemcache(), nil, 0),
+	} {
+		ctx := context.Background()
+		resolver := New(store)
+
+		// 公众号
+		require.Equal(t, nil, resolver.RecordUser(ctx, "wx_oa", &user_api.User{
+			OpenID: "oa_openid", UnionID: "unionid",
+		}))
+		// 小程序
+		require.Equal(t, nil, resolver.RecordMpSession(ctx, "wx_mp", "mp_openid", "unionid"))
+		// 网站应用扫码
+		require.Equal(t, nil, resolver.RecordOAuth(ctx, &oauthflow.Identity{
+			AppID: "wx_web", OpenID: "web_openid", UnionID: "unionid",
+		}))
+		// 企业微信成员绑定公众号 openid
+		require.Equal(t, nil, resolver.Link(
+			ctx, UserID("corp", "zhangsan"), OpenID("wx_oa", "oa_openid"),
+		))
+		// 没有 unionid 的小程序用户
+		require.Equal(t, nil, resolver.RecordMpSession(ctx, "wx_mp", "lonely", ""))
+
+		unionid, err := resolver.GetUnionID(ctx, "wx_mp", "mp_openid")
+		require.Equal(t, nil, err)
+		require.Equal(t, "unionid", unionid)
+
+		openid, err := resolver.GetOpenID(ctx, OpenID("wx_web", "web_openid"), "wx_mp")
+		require.Equal(t, nil, err)
+		require.Equal(t, "mp_openid", openid)
+
+		userid, err := resolver.GetUserID(ctx, OpenID("wx_mp", "mp_openid"), "corp")
+		require.Equal(t, nil, err)
+		require.Equal(t, "zhangsan", userid)
+
+		openid, err = resolver.GetOpenID(ctx, UserID("corp", "zhangsan"), "wx_web")
+		require.Equal(t, nil, err)
+		require.Equal(t, "web_openid", openid)
+
+		_, err = resolver.GetUnionID(ctx, "wx_mp", "lonely")
+		require.Equal(t, ErrNotFound, err)
+
+		// 重复关联不会产生重复记录
+		require.Equal(t, nil, resolver.RecordMpSession(ctx, "wx_mp", "mp_openid", "unionid"))
+		neighbors, err := store.Neighbors(ctx, OpenID("wx_mp", "mp_openid"))
+		require.Equal(t, nil, err)
+		require.Equal(t, []ID{UnionID("unionid")}, neighbors)
+
+		// 企业微信自建应用登录, 没有 open_userid
+		exists, err := resolver.Exists(ctx, UserID("corp", "lisi"))
+		require.Equal(t, nil, err)
+		require.False(t, exists)
+		require.Equal(t, nil, resolver.RecordOAuth(ctx, &oauthflow.Identity{
+			CorpID: "corp", UserID: "lisi",
+		}))
+		exists, err = resolver.Exists(ctx, UserID("corp", "lisi"))
+		require.Equal(t, nil, err)
+		require.True(t, exists)
+		exists, err = resolver.Exists(ctx, UserID("other", "lisi"))
+		require.Equal(t, nil, err)
+		require.False(t, exists)
+		// 已有的关联不受影响
+		require.Equal(t, nil, resolver.RecordOAuth(ctx, &oauthflow.Identity{
+			CorpID: "corp", UserID: "zhangsan",
+		}))
+		userid, err = resolver.GetUserID(ctx, OpenID("wx_mp", "mp_openid"), "corp")
+		require.Equal(t, nil, err)
+		require.Equal(t, "zhangsan", userid)
+	}
+}
@@ -0,0 +1,107 @@
+package identity
+
+import (
+	"context"
+	"time"
+
+	"github.com/lixinio/weixin/utils"
+	"github.com/lixinio/weixin/utils/memcache"
+)
+
+const (
+	defaultKeyPrefix = "weixin:identity:"
+	defaultCacheTTL  = 365 * 24 * time.Hour
+)
+
+// Store 身份关联存储, 关联是双向的
+type Store interface {
+	Link(ctx context.Context, a, b ID) error
+	// 记录身份, 尚未关联其他身份时也保存
+	Add(ctx context.Context, id ID) error
+	Exists(ctx context.Context, id ID) (bool, error)
+	// 直接关联的身份
+	Neighbors(ctx context.Context, id ID) ([]ID, error)
+}
+
+// NewMemoryStore 内存存储, 适用于测试或者单进程
+func NewMemoryStore() *CacheStore {
+	cache := memcache.NewMemcache()
+	return NewCacheStore(cache, cache, 0)
+}
+
+// CacheStore 基于 utils.Cache 的存储, 每个身份保存一个关联列表
+type CacheStore struct {
+	cache     utils.Cache
+	locker    utils.Lock // 可选, 多进程写入时避免覆盖
+	keyPrefix string
+	ttl       time.Duration
+}
+
+// ttl 为 0 时默认保存一年, 每次关联时续期
+func NewCacheStore(cache utils.Cache, locker utils.Lock, ttl time.Duration) *CacheStore {
+	if ttl <= 0 {
+		ttl = defaultCacheTTL
+	}
+	return &CacheStore{
+		cache:     cache,
+		locker:    locker,
+		keyPrefix: defaultKeyPrefix,
+		ttl:       ttl,
+	}
+}
+
+func (s *CacheStore) key(id ID) string {
+	return s.keyPrefix + id.String()
+}
+
+func (s *CacheStore) Link(ctx context.Context, a, b ID) error {
+	if err := s.add(a, b); err != nil {
+		return err
+	}
+	return s.add(b, a)
+}
+
+func (s *CacheStore) Add(ctx context.Context, id ID) error {
+	key := s.key(id)
+	return utils.WithLock(s.locker, key+":lock", func() error {
+		ids, err := s.load(key)
+		if err != nil {
+			return err
+		}
+		// 保留已有的关联, 同时续期
+		return utils.CacheSetJSON(s.cache, key, ids, s.ttl)
+	})
+}
+
+func (s *CacheStore) Exists(ctx context.Context, id ID) (bool, error) {
+	ids := []ID{}
+	return utils.CacheGetJSON(s.cache, s.key(id), &ids)
+}
+
+func (s *CacheStore) add(from, to ID) error {
+	key := s.key(from)
+	return utils.WithLock(s.locker, key+":lock", func() error {
+		ids, err := s.load(key)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			if id == to {
+				return nil
+			}
+		}
+		return utils.CacheSetJSON(s.cache, key, append(ids, to), s.ttl)
+	})
+}
+
+func (s *CacheStore) load(key string) ([]ID, error) {
+	ids := []ID{}
+	if _, err := utils.CacheGetJSON(s.cache, key, &ids); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (s *CacheStore) Neighbors(ctx context.Context, id ID) ([]ID, error) {
+	return s.load(s.key(id))
+}
@@ -410,23 +410,70 @@ func (api *InvoiceApi) Insert(
 	return &result, nil
 }
 
+// 开票明细
+type MakeOutInvoiceDetail struct {
+	Fphxz string `json:"fphxz"`          // 发票行性质 0 正常行 1 折扣行 2 被折扣行
+	Spbm  string `json:"spbm"`           // 19 位税收分类编码
+	Xmmc  string `json:"xmmc"`           // 项目名称
+	Dw    string `json:"dw,omitempty"`   // 计量单位
+	Ggxh  string `json:"ggxh,omitempty"` // 规格型号
+	Xmsl  string `json:"xmsl,omitempty"` // 项目数量
+	Xmdj  string `json:"xmdj,omitempty"` // 项目单价
+	Xmje  string `json:"xmje"`           // 项目金额 不含税，单位元 两位小数
+	Sl    string `json:"sl"`             // 税率 精确到两位小数 如0.01
+	Se    string `json:"se"`             // 税额 单位元 两位小数
+}
+
+type MakeOutInvoiceObj struct {
+	WxOpenID          string                 `json:"wxopenid"`            // 用户的openid 用户知道是谁在开票
+	Ddh               string                 `json:"ddh"`                 // 订单号，企业自己内部的订单号码
+	Fpqqlsh           string                 `json:"fpqqlsh"`             // 发票请求流水号，唯一识别开票请求的流水号
+	Nsrsbh            string                 `json:"nsrsbh"`              // 纳税人识别码
+	Nsrmc             string                 `json:"nsrmc"`               // 纳税人名称
+	Nsrdz             string                 `json:"nsrdz"`               // 纳税人地址
+	Nsrdh             string                 `json:"nsrdh"`               // 纳税人电话
+	Nsrbank           string                 `json:"nsrbank"`             // 纳税人开户行
+	Nsrbankid         string                 `json:"nsrbankid"`           // 纳税人银行账号
+	Ghfmc             string                 `json:"ghfmc"`               // 购货方名称
+	Ghfnsrsbh         string                 `json:"ghfnsrsbh,omitempty"` // 购货方识别号
+	Ghfdz             string                 `json:"ghfdz,omitempty"`     // 购货方地址
+	Ghfdh             string                 `json:"ghfdh,omitempty"`     // 购货方电话
+	Ghfbank           string                 `json:"ghfbank,omitempty"`   // 购货方开户行
+	Ghfbankid         string                 `json:"ghfbankid,omitempty"` // 购货方银行帐号
+	Kpr               string                 `json:"kpr"`                 // 开票人
+	Skr               string                 `json:"skr,omitempty"`       // 收款人
+	Fhr               string                 `json:"fhr,omitempty"`       // 复核人
+	Jshj              string                 `json:"jshj"`                // 价税合计
+	Hjje              string                 `json:"hjje"`                // 合计金额
+	Hjse              string                 `json:"hjse"`                // 合计税额
+	Bz                string                 `json:"bz,omitempty"`        // 备注
+	Hylx              string                 `json:"hylx,omitempty"`      // 行业类型 0 商业 1 其它
+	InvoiceDetailList []MakeOutInvoiceDetail `json:"invoicedetail_list"`  // 发票行项目数据
+}
+
 /*
 统一开票接口-开具蓝票
 对于使用微信电子发票开票接入能力的商户，在公众号后台选择任何一家开票平台的套餐，都可以使用本接口实现电子发票的开具
 See: https://developers.weixin.qq.com/doc/offiaccount/WeChat_Invoice/E_Invoice/Vendor_API_List.html
 POST https://api.weixin.qq.com/card/invoice/makeoutinvoice?access_token={access_token}
 */
-// func (api *InvoiceApi) MakeOutInvoice(
-// 	ctx context.Context,
-// 	payload []byte,
-// ) (resp []byte, err error) {
-// 	return api.Client.HTTPPost(
-// 		ctx,
-// 		apiMakeOutInvoice,
-// 		bytes.NewReader(payload),
-// 		"application/json;charset=utf-8",
-// 	)
-// }
+func (api *InvoiceApi) MakeOutInvoice(ctx context.Context, param *MakeOutInvoiceObj) error {
+	payload := struct {
+		InvoiceInfo *MakeOutInvoiceObj `json:"invoiceinfo"`
+	}{
+		InvoiceInfo: param,
+	}
+	return api.Client.HTTPPostJson(ctx, apiMakeOutInvoice, payload, nil)
+}
+
+type ClearOutInvoiceObj struct {
+	WxOpenID string `json:"wxopenid"` // 用户的openid 用户知道是谁在开票
+	Fpqqlsh  string `json:"fpqqlsh"`  // 发票请求流水号，唯一查询发票的流水号
+	Nsrsbh   string `json:"nsrsbh"`   // 纳税人识别码
+	Nsrmc    string `json:"nsrmc"`    // 纳税人名称
+	Yfpdm    string `json:"yfpdm"`    // 原发票代码，即要冲红的蓝票的发票代码
+	Yfphm    string `json:"yfphm"`    // 原发票号码，即要冲红的蓝票的发票号码
+}
 
 /*
 统一开票接口-发票冲红
@@ -434,17 +481,23 @@ POST https://api.weixin.qq.com/card/invoice/makeoutinvoice?access_token={access_
 See: https://developers.weixin.qq.com/doc/offiaccount/WeChat_Invoice/E_Invoice/Vendor_API_List.html
 POST https://api.weixin.qq.com/card/invoice/clearoutinvoice?access_token={access_token}
 */
-// func (api *InvoiceApi) ClearOutInvoice(
-// 	ctx context.Context,
-// 	payload []byte,
-// ) (resp []byte, err error) {
-// 	return api.Client.HTTPPost(
-// 		ctx,
-// 		apiClearOutInvoice,
-// 		bytes.NewReader(payload),
-// 		"application/json;charset=utf-8",
-// 	)
-// }
+func (api *InvoiceApi) ClearOutInvoice(ctx context.Context, param *ClearOutInvoiceObj) error {
+	payload := struct {
+		InvoiceInfo *ClearOutInvoiceObj `json:"invoiceinfo"`
+	}{
+		InvoiceInfo: param,
+	}
+	return api.Client.HTTPPostJson(ctx, apiClearOutInvoice, payload, nil)
+}
+
+type InvoiceDetail struct {
+	Fpqqlsh string `json:"fpqqlsh"` // 发票请求流水号
+	Jym     string `json:"jym"`     // 校验码，位于电子发票右上方，开票日期下
+	Kprq    string `json:"kprq"`    // 开票日期
+	Fpdm    string `json:"fpdm"`    // 发票代码
+	Fphm    string `json:"fphm"`    // 发票号码
+	PdfUrl  string `json:"pdfurl"`  // 发票 url
+}
 
 /*
 统一开票接口-查询已开发票
@@ -452,52 +505,115 @@ POST https://api.weixin.qq.com/card/invoice/clearoutinvoice?access_token={access
 See: https://developers.weixin.qq.com/doc/offiaccount/WeChat_Invoice/E_Invoice/Vendor_API_List.html
 POST https://api.weixin.qq.com/card/invoice/queryinvoceinfo?access_token={access_token}
 */
-// func (api *InvoiceApi) QueryInvoceInfo(
-// 	ctx context.Context,
-// 	payload []byte,
-// ) (resp []byte, err error) {
-// 	return api.Client.HTTPPost(
-// 		ctx,
-// 		apiQueryInvoceInfo,
-// 		bytes.NewReader(payload),
-// 		"application/json;charset=utf-8",
-// 	)
-// }
+func (api *InvoiceApi) QueryInvoceInfo(
+	ctx context.Context,
+	fpqqlsh, nsrsbh string,
+) (*InvoiceDetail, error) {
+	result := &struct {
+		utils.WeixinError
+		InvoiceDetail InvoiceDetail `json:"invoicedetail"`
+	}{}
+	if err := api.Client.HTTPPostJson(ctx, apiQueryInvoceInfo, map[string]string{
+		"fpqqlsh": fpqqlsh,
+		"nsrsbh":  nsrsbh,
+	}, result); err != nil {
+		return nil, err
+	}
+	return &result.InvoiceDetail, nil
+}
+
+type PdfUrlResult struct {
+	utils.WeixinError
+	PdfUrl           string `json:"pdf_url"`             // pdf 的 url ，两个小时有效期
+	PdfUrlExpireTime int64  `json:"pdf_url_expire_time"` // pdf_url 过期时间， 7200 秒
+}
 
 /*
 查询已上传的PDF文件
-用于供发票PDF的上传方查询已经上传的发票或消费凭证PDF
+用于供发票PDF的上传方查询已经上传的发票或消费凭证PDF, 返回的下载地址两个小时有效, 可以使用 DownloadPdf 下载
 See: https://developers.weixin.qq.com/doc/offiaccount/WeChat_Invoice/E_Invoice/Invoicing_Platform_API_List.html
 POST https://api.weixin.qq.com/card/invoice/platform/getpdf?action=get_url&access_token={access_token}
 */
-// func (api *InvoiceApi) PlatformGetpdf(
-// 	ctx context.Context,
-// 	payload []byte,
-// ) (resp []byte, err error) {
-// 	return api.Client.HTTPPost(
-// 		ctx,
-// 		apiPlatformGetpdf,
-// 		bytes.NewReader(payload),
-// 		"application/json;charset=utf-8",
-// 	)
-// }
+func (api *InvoiceApi) PlatformGetPdf(ctx context.Context, sMediaID string) (*PdfUrlResult, error) {
+	result := &PdfUrlResult{}
+	if err := api.Client.HTTPPost(ctx, apiPlatformGetpdf, map[string]string{
+		"s_media_id": sMediaID,
+	}, func(params url.Values) {
+		params.Add("action", "get_url")
+	}, result, ""); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// 发票报销状态
+const (
+	ReimburseStatusInit    = "INVOICE_REIMBURSE_INIT"    // 发票初始状态，未锁定，可提交报销
+	ReimburseStatusLock    = "INVOICE_REIMBURSE_LOCK"    // 发票已锁定，无法重复提交报销
+	ReimburseStatusClosure = "INVOICE_REIMBURSE_CLOSURE" // 发票已核销，从用户卡包中移除
+)
 
 /*
 更新发票卡券状态
+开票平台可以通过该接口更新发票卡券的报销状态, reimburseStatus 为 ReimburseStatus*
 See: https://developers.weixin.qq.com/doc/offiaccount/WeChat_Invoice/E_Invoice/Invoicing_Platform_API_List.html
 POST https://api.weixin.qq.com/card/invoice/platform/updatestatus?access_token={access_token}
 */
-// func (api *InvoiceApi) PlatformUpdateStatus(
-// 	ctx context.Context,
-// 	payload []byte,
-// ) (resp []byte, err error) {
-// 	return api.Client.HTTPPost(
-// 		ctx,
-// 		apiPlatformUpdateStatus,
-// 		bytes.NewReader(payload),
-// 		"application/json;charset=utf-8",
-// 	)
-// }
+func (api *InvoiceApi) PlatformUpdateStatus(
+	ctx context.Context,
+	cardID, code, reimburseStatus string,
+) error {
+	return api.Client.HTTPPostJson(ctx, apiPlatformUpdateStatus, map[string]string{
+		"card_id":          cardID,
+		"code":             code,
+		"reimburse_status": reimburseStatus,
+	}, nil)
+}
+
+// 报销方使用的发票, card_id 和 encrypt_code 来自 JSSDK chooseInvoice 或小程序 wx.chooseInvoice
+type ReimburseInvoiceItem struct {
+	CardID      string `json:"card_id"`
+	EncryptCode string `json:"encrypt_code"`
+}
+
+type ReimburseInvoiceInfo struct {
+	CardID    string `json:"card_id"`
+	BeginTime int64  `json:"begin_time"` // 发票的有效期起始时间
+	EndTime   int64  `json:"end_time"`   // 发票的有效期截止时间
+	OpenID    string `json:"openid"`     // 用户标识
+	Type      string `json:"type"`       // 发票的类型
+	Payee     string `json:"payee"`      // 发票的收款方
+	Detail    string `json:"detail"`     // 发票详情
+	UserInfo  struct {
+		Fee                   int    `json:"fee"`          // 发票加税合计金额，以分为单位
+		Title                 string `json:"title"`        // 发票的抬头
+		BillingTime           int64  `json:"billing_time"` // 开票时间
+		BillingNO             string `json:"billing_no"`   // 发票代码
+		BillingCode           string `json:"billing_code"` // 发票号码
+		FeeWithoutTax         int    `json:"fee_without_tax"`
+		Tax                   int    `json:"tax"`
+		PdfUrl                string `json:"pdf_url"`          // 这张发票对应的PDF_URL
+		TripPdfUrl            string `json:"trip_pdf_url"`     // 其它消费凭证附件对应的URL，如行程单、水单等
+		ReimburseStatus       string `json:"reimburse_status"` // 发票报销状态 ReimburseStatus*
+		CheckCode             string `json:"check_code"`       // 发票的校验码
+		BuyerNumber           string `json:"buyer_number"`
+		BuyerAddressAndPhone  string `json:"buyer_address_and_phone"`
+		BuyerBankAccount      string `json:"buyer_bank_account"`
+		SellerNumber          string `json:"seller_number"`
+		SellerAddressAndPhone string `json:"seller_address_and_phone"`
+		SellerBankAccount     string `json:"seller_bank_account"`
+		Remarks               string `json:"remarks"`
+		Cashier               string `json:"cashier"`
+		Maker                 string `json:"maker"`
+		Info                  []struct {
+			Name  string `json:"name"`
+			Num   int    `json:"num"`
+			Unit  string `json:"unit"`
+			Fee   int    `json:"fee"`
+			Price int    `json:"price"`
+		} `json:"info"` // 商品信息结构
+	} `json:"user_info"`
+}
 
 /*
 查询报销发票信息
@@ -505,68 +621,88 @@ POST https://api.weixin.qq.com/card/invoice/platform/updatestatus?access_token={
 See: https://developers.weixin.qq.com/doc/offiaccount/WeChat_Invoice/E_Invoice/Reimburser_API_List.html
 POST https://api.weixin.qq.com/card/invoice/reimburse/getinvoiceinfo?access_token={access_token}
 */
-// func (api *InvoiceApi) ReimburseGetInvoiceInfo(
-// 	ctx context.Context,
-// 	payload []byte,
-// ) (resp []byte, err error) {
-// 	return api.Client.HTTPPost(
-// 		ctx,
-// 		apiReimburseGetInvoiceInfo,
-// 		bytes.NewReader(payload),
-// 		"application/json;charset=utf-8",
-// 	)
-// }
+func (api *InvoiceApi) ReimburseGetInvoiceInfo(
+	ctx context.Context,
+	item *ReimburseInvoiceItem,
+) (*ReimburseInvoiceInfo, error) {
+	result := &struct {
+		utils.WeixinError
+		ReimburseInvoiceInfo
+	}{}
+	if err := api.Client.HTTPPostJson(ctx, apiReimburseGetInvoiceInfo, item, result); err != nil {
+		return nil, err
+	}
+	return &result.ReimburseInvoiceInfo, nil
+}
 
 /*
 批量查询报销发票信息
 See: https://developers.weixin.qq.com/doc/offiaccount/WeChat_Invoice/E_Invoice/Reimburser_API_List.html
 POST https://api.weixin.qq.com/card/invoice/reimburse/getinvoicebatch?access_token={access_token}
 */
-// func (api *InvoiceApi) ReimburseGetInvoiceBatch(
-// 	ctx context.Context,
-// 	payload []byte,
-// ) (resp []byte, err error) {
-// 	return api.Client.HTTPPost(
-// 		ctx,
-// 		apiReimburseGetInvoiceBatch,
-// 		bytes.NewReader(payload),
-// 		"application/json;charset=utf-8",
-// 	)
-// }
+func (api *InvoiceApi) ReimburseGetInvoiceBatch(
+	ctx context.Context,
+	items []ReimburseInvoiceItem,
+) ([]ReimburseInvoiceInfo, error) {
+	result := &struct {
+		utils.WeixinError
+		ItemList []ReimburseInvoiceInfo `json:"item_list"`
+	}{}
+	if err := api.Client.HTTPPostJson(ctx, apiReimburseGetInvoiceBatch, map[string]interface{}{
+		"item_list": items,
+	}, result); err != nil {
+		return nil, err
+	}
+	return result.ItemList, nil
+}
 
 /*
 报销方更新发票状态
+报销方只能将发票状态更新为锁定或者核销, reimburseStatus 为 ReimburseStatus*
 See: https://developers.weixin.qq.com/doc/offiaccount/WeChat_Invoice/E_Invoice/Reimburser_API_List.html
 POST https://api.weixin.qq.com/card/invoice/reimburse/updateinvoicestatus?access_token={access_token}
 */
-// func (api *InvoiceApi) ReimburseUpdateInvoiceStatus(
-// 	ctx context.Context,
-// 	payload []byte,
-// ) (resp []byte, err error) {
-// 	return api.Client.HTTPPost(
-// 		ctx,
-// 		apiReimburseUpdateInvoiceStatus,
-// 		bytes.NewReader(payload),
-// 		"application/json;charset=utf-8",
-// 	)
-// }
+func (api *InvoiceApi) ReimburseUpdateInvoiceStatus(
+	ctx context.Context,
+	item *ReimburseInvoiceItem,
+	reimburseStatus string,
+) error {
+	return api.Client.HTTPPostJson(ctx, apiReimburseUpdateInvoiceStatus, map[string]string{
+		"card_id":          item.CardID,
+		"encrypt_code":     item.EncryptCode,
+		"reimburse_status": reimburseStatus,
+	}, nil)
+}
 
 /*
 报销方批量更新发票状态
+同一个用户的发票, 要么全部成功要么全部失败
 See: https://developers.weixin.qq.com/doc/offiaccount/WeChat_Invoice/E_Invoice/Reimburser_API_List.html
 POST https://api.weixin.qq.com/card/invoice/reimburse/updatestatusbatch?access_token={access_token}
 */
-// func (api *InvoiceApi) ReimburseUpdateStatusBatch(
-// 	ctx context.Context,
-// 	payload []byte,
-// ) (resp []byte, err error) {
-// 	return api.Client.HTTPPost(
-// 		ctx,
-// 		apiReimburseUpdateStatusBatch,
-// 		bytes.NewReader(payload),
-// 		"application/json;charset=utf-8",
-// 	)
-// }
+func (api *InvoiceApi) ReimburseUpdateStatusBatch(
+	ctx context.Context,
+	openID string,
+	reimburseStatus string,
+	items []ReimburseInvoiceItem,
+) error {
+	return api.Client.HTTPPostJson(ctx, apiReimburseUpdateStatusBatch, map[string]interface{}{
+		"openid":           openID,
+		"reimburse_status": reimburseStatus,
+		"invoice_list":     items,
+	}, nil)
+}
+
+type UserTitleObj struct {
+	UserFill   int    `json:"user_fill"`              // 0 商户提供抬头信息，1 用户自己填写
+	Title      string `json:"title"`                  // 抬头，当 user_fill 为 0 时，必填
+	Phone      string `json:"phone,omitempty"`        // 联系方式
+	TaxNo      string `json:"tax_no,omitempty"`       // 税号
+	Addr       string `json:"addr,omitempty"`         // 地址
+	BankType   string `json:"bank_type,omitempty"`    // 银行类型
+	BankNo     string `json:"bank_no,omitempty"`      // 银行号码
+	OutTitleID string `json:"out_title_id,omitempty"` // 开票码
+}
 
 /*
 将发票抬头信息录入到用户微信中
@@ -574,35 +710,54 @@ POST https://api.weixin.qq.com/card/invoice/reimburse/updatestatusbatch?access_t
 See: https://developers.weixin.qq.com/doc/offiaccount/WeChat_Invoice/Quick_issuing/Interface_Instructions.html
 POST https://api.weixin.qq.com/card/invoice/biz/getusertitleurl?access_token={access_token
 */
-// func (api *InvoiceApi) GetUserTitleUrl(
-// 	ctx context.Context,
-// 	payload []byte,
-// ) (resp []byte, err error) {
-// 	return api.Client.HTTPPost(
-// 		ctx,
-// 		apiGetUserTitleUrl,
-// 		bytes.NewReader(payload),
-// 		"application/json;charset=utf-8",
-// 	)
-// }
+func (api *InvoiceApi) GetUserTitleUrl(ctx context.Context, param *UserTitleObj) (string, error) {
+	result := &struct {
+		utils.WeixinError
+		Url string `json:"url"`
+	}{}
+	if err := api.Client.HTTPPostJson(ctx, apiGetUserTitleUrl, param, result); err != nil {
+		return "", err
+	}
+	return result.Url, nil
+}
 
 /*
 获取用户抬头（方式一）:获取商户专属二维码，立在收银台
 商户调用接口，获取链接，将链接转成二维码，用户扫码，可以选择抬头发给商户
+用户选择的抬头通过 server_api.EventSubmitInvoiceTitle 推送, 其中带有 attach
 See: https://developers.weixin.qq.com/doc/offiaccount/WeChat_Invoice/Quick_issuing/Interface_Instructions.html
 POST https://api.weixin.qq.com/card/invoice/biz/getselecttitleurl?access_token={access_token}
 */
-// func (api *InvoiceApi) GetSelectTitleUrl(
-// 	ctx context.Context,
-// 	payload []byte,
-// ) (resp []byte, err error) {
-// 	return api.Client.HTTPPost(
-// 		ctx,
-// 		apiGetSelectTitleUrl,
-// 		bytes.NewReader(payload),
-// 		"application/json;charset=utf-8",
-// 	)
-// }
+func (api *InvoiceApi) GetSelectTitleUrl(ctx context.Context, attach, bizName string) (string, error) {
+	result := &struct {
+		utils.WeixinError
+		Url string `json:"url"`
+	}{}
+	if err := api.Client.HTTPPostJson(ctx, apiGetSelectTitleUrl, map[string]string{
+		"attach":   attach,
+		"biz_name": bizName,
+	}, result); err != nil {
+		return "", err
+	}
+	return result.Url, nil
+}
+
+// 抬头类型
+const (
+	TitleTypeBusiness = 0 // 单位
+	TitleTypePerson   = 1 // 个人
+)
+
+type ScanTitleResult struct {
+	utils.WeixinError
+	TitleType int    `json:"title_type"` // 抬头类型 TitleType*
+	Title     string `json:"title"`
+	Phone     string `json:"phone"`
+	TaxNo     string `json:"tax_no"`
+	Addr      string `json:"addr"`
+	BankType  string `json:"bank_type"`
+	BankNo    string `json:"bank_no"`
+}
 
 /*
 获取用户抬头（方式二）:商户扫描用户的发票抬头二维码
@@ -610,11 +765,12 @@ POST https://api.weixin.qq.com/card/invoice/biz/getselecttitleurl?access_token={
 See: https://developers.weixin.qq.com/doc/offiaccount/WeChat_Invoice/Quick_issuing/Interface_Instructions.html
 POST https://api.weixin.qq.com/card/invoice/scantitle?access_token={access_token}
 */
-// func (api *InvoiceApi) ScanTitle(ctx context.Context, payload []byte) (resp []byte, err error) {
-// 	return api.Client.HTTPPost(
-// 		ctx,
-// 		apiScanTitle,
-// 		bytes.NewReader(payload),
-// 		"application/json;charset=utf-8",
-// 	)
-// }
+func (api *InvoiceApi) ScanTitle(ctx context.Context, scanText string) (*ScanTitleResult, error) {
+	result := &ScanTitleResult{}
+	if err := api.Client.HTTPPostJson(ctx, apiScanTitle, map[string]string{
+		"scan_text": scanText,
+	}, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
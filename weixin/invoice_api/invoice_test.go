@@ -1,20 +1,31 @@
 package invoice_api
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
+	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/lixinio/weixin/test"
+	"github.com/lixinio/weixin/utils"
 	"github.com/lixinio/weixin/utils/redis"
 	"github.com/lixinio/weixin/weixin/official_account"
+	"github.com/lixinio/weixin/weixin/server_api"
 	"github.com/stretchr/testify/require"
 )
 
+var _ WxCardTicketGetter = (*official_account.OfficialAccount)(nil)
+
 func newInvoiceApi() (*InvoiceApi, *official_account.OfficialAccount) {
 	redis := redis.NewRedis(&redis.Config{RedisUrl: test.CacheUrl})
 	officialAccount := official_account.New(redis, redis, &official_account.Config{
@@ -240,3 +251,164 @@ func TestInvoice(t *testing.T) {
 		fmt.Println(result.InvoiceStatus)
 	}
 }
+
+func TestDownloadPdf(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/fapiao.pdf" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("%PDF-1.4"))
+	}))
+	defer server.Close()
+
+	buf := &bytes.Buffer{}
+	n, err := DownloadPdf(ctx, server.URL+"/fapiao.pdf", buf)
+	require.Equal(t, nil, err)
+	require.Equal(t, int64(8), n)
+	require.Equal(t, "%PDF-1.4", buf.String())
+
+	_, err = DownloadPdf(ctx, server.URL+"/none.pdf", buf)
+	require.NotEqual(t, nil, err)
+}
+
+func TestPlatformSetPdfFile(t *testing.T) {
+	ctx := context.Background()
+	filename := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if file, header, err := r.FormFile("pdf"); err == nil {
+			file.Close()
+			filename = header.Filename
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"errcode":0,"errmsg":"ok","s_media_id":"s_media_id"}`)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "fapiao.pdf")
+	require.Equal(t, nil, ioutil.WriteFile(path, []byte("%PDF-1.4"), 0644))
+
+	api := NewApi(utils.NewClient(server.URL, utils.StaticClientAccessTokenGetter("token")))
+	sMediaID, err := api.PlatformSetPdfFile(ctx, path)
+	require.Equal(t, nil, err)
+	require.Equal(t, "s_media_id", sMediaID)
+	// 不上传本地路径
+	require.Equal(t, "fapiao.pdf", filename)
+}
+
+type fakeTicket struct{}
+
+func (fakeTicket) GetWxCardApiTicket(ctx context.Context) (string, error) {
+	return "ticket", nil
+}
+
+func TestWorkflow(t *testing.T) {
+	ctx := context.Background()
+	var mutex sync.Mutex
+	insertedCards := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		params := map[string]interface{}{}
+		json.NewDecoder(r.Body).Decode(&params)
+		switch r.URL.Path {
+		case apiPlatformCreateCard:
+			fmt.Fprint(w, `{"errcode":0,"errmsg":"ok","card_id":"card_new"}`)
+		case apiGetAuthData:
+			status := InvoiceStatusNeverAuth
+			if params["order_id"] == "order_ok" {
+				status = InvoiceStatusAuthSuccess
+			}
+			fmt.Fprintf(w, `{"errcode":0,"errmsg":"ok","invoice_status":"%s"}`, status)
+		case apiInsert:
+			mutex.Lock()
+			insertedCards = append(insertedCards, params["card_id"].(string))
+			mutex.Unlock()
+			fmt.Fprint(w, `{"errcode":0,"errmsg":"ok","code":"code","openid":"openid"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	inserted := []string{}
+	failed := map[string]error{}
+	workflow := NewWorkflow(
+		NewApi(utils.NewClient(server.URL, utils.StaticClientAccessTokenGetter("token"))),
+		&WorkflowConfig{
+			SPappID: "s_pappid",
+			Appid:   "appid",
+			Ticket:  fakeTicket{},
+			Issue: func(ctx context.Context, order *AuthorizedOrder) (*InvoiceInsertCardExtUser, error) {
+				require.Equal(t, InvoiceStatusAuthSuccess, order.AuthData.InvoiceStatus)
+				return &InvoiceInsertCardExtUser{Fee: 100, Title: "title"}, nil
+			},
+			OnInserted: func(ctx context.Context, order *AuthorizedOrder, result *InvoiceInsertResult) {
+				inserted = append(inserted, order.OrderID+":"+result.Code)
+			},
+			OnFailed: func(ctx context.Context, orderID string, err error) {
+				failed[orderID] = err
+			},
+		},
+	)
+
+	serverApi := server_api.NewApi("appid", "token", "", nil)
+	parse := func(succ, fail string) interface{} {
+		event, err := serverApi.ParseXML([]byte(fmt.Sprintf(`<xml>
+  <ToUserName><![CDATA[gh_fc0a06a20993]]></ToUserName>
+  <FromUserName><![CDATA[openid]]></FromUserName>
+  <CreateTime>1475134700</CreateTime>
+  <MsgType><![CDATA[event]]></MsgType>
+  <Event><![CDATA[user_authorize_invoice]]></Event>
+  <SuccOrderId><![CDATA[%s]]></SuccOrderId>
+  <FailOrderId><![CDATA[%s]]></FailOrderId>
+  <AuthorizeAppId><![CDATA[wx_authorize]]></AuthorizeAppId>
+  <Source><![CDATA[web]]></Source>
+</xml>`, succ, fail)))
+		require.Equal(t, nil, err)
+		return event
+	}
+
+	// 没有卡券模板
+	handled, err := workflow.HandleEvent(ctx, parse("order_ok", ""))
+	require.True(t, handled)
+	require.NotEqual(t, nil, err)
+
+	// 并发创建模板和开票, 模板更新之后使用新模板
+	var createErr error
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, createErr = workflow.CreateCard(ctx, &CreateCardObj{})
+	}()
+	for i := 0; i < 5; i++ {
+		workflow.Process(ctx, &AuthorizedOrder{OrderID: "order_never"})
+	}
+	wg.Wait()
+	require.Equal(t, nil, createErr)
+	require.Equal(t, "card_new", workflow.CardID())
+
+	handled, err = workflow.HandleEvent(ctx, parse("order_ok", ""))
+	require.True(t, handled)
+	require.Equal(t, nil, err)
+	require.Equal(t, []string{"order_ok:code"}, inserted)
+	require.Equal(t, []string{"card_new"}, insertedCards)
+
+	// 用户拒绝授权
+	handled, err = workflow.HandleEvent(ctx, parse("", "order_fail"))
+	require.True(t, handled)
+	require.Equal(t, nil, err)
+	require.True(t, errors.Is(failed["order_fail"], ErrOrderNotAuthorized))
+
+	// 推送之后再次确认授权状态
+	handled, err = workflow.HandleEvent(ctx, parse("order_never", ""))
+	require.True(t, handled)
+	require.True(t, errors.Is(err, ErrOrderNotAuthorized))
+	require.True(t, errors.Is(failed["order_never"], ErrOrderNotAuthorized))
+
+	// 其他事件
+	handled, err = workflow.HandleEvent(ctx, &server_api.EventSubscribe{})
+	require.False(t, handled)
+	require.Equal(t, nil, err)
+}
@@ -0,0 +1,53 @@
+package invoice_api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// PlatformSetPdfFile 上传本地 PDF 文件, 文件内容直接从磁盘读取, 不会全部加载到内存
+func (api *InvoiceApi) PlatformSetPdfFile(ctx context.Context, filename string) (string, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	fi, err := file.Stat()
+	if err != nil {
+		return "", err
+	}
+	return api.PlatformSetPdf(ctx, filepath.Base(filename), fi.Size(), file)
+}
+
+// DownloadPdf 下载发票 PDF(PlatformGetPdf 或者 ReimburseGetInvoiceInfo 返回的 pdf_url), 写入 w
+func DownloadPdf(ctx context.Context, pdfUrl string, w io.Writer) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pdfUrl, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("download invoice pdf fail, http status %d", resp.StatusCode)
+	}
+	return io.Copy(w, resp.Body)
+}
+
+// PlatformDownloadPdf 根据 s_media_id 下载已上传的 PDF, 写入 w
+func (api *InvoiceApi) PlatformDownloadPdf(ctx context.Context, sMediaID string, w io.Writer) (int64, error) {
+	result, err := api.PlatformGetPdf(ctx, sMediaID)
+	if err != nil {
+		return 0, err
+	}
+	return DownloadPdf(ctx, result.PdfUrl, w)
+}
@@ -0,0 +1,178 @@
+package invoice_api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lixinio/weixin/utils"
+	"github.com/lixinio/weixin/weixin/server_api"
+)
+
+// 订单授权状态 AuthDataResult.InvoiceStatus
+const (
+	InvoiceStatusAuthSuccess  = "auth success"
+	InvoiceStatusRejectInsert = "reject insert"
+	InvoiceStatusNeverAuth    = "never auth"
+)
+
+var ErrOrderNotAuthorized = errors.New("invoice order not authorized")
+
+// WxCardTicketGetter 获取 wx_card api_ticket, official_account.OfficialAccount 和 authorizer.Authorizer 实现了该接口
+type WxCardTicketGetter interface {
+	GetWxCardApiTicket(ctx context.Context) (string, error)
+}
+
+// AuthorizedOrder 用户已授权开票的订单
+type AuthorizedOrder struct {
+	OrderID        string
+	AuthorizeAppID string // 获取授权页链接的 appid
+	Source         string // 授权来源
+	AuthData       *AuthDataResult
+}
+
+type WorkflowConfig struct {
+	SPappID string             // 开票平台标识, 通过 SetUrl 获取
+	Appid   string             // 订单授权使用的 appid, 一般为商户 appid
+	CardID  string             // 发票卡券模板, 为空时需要先调用 CreateCard
+	Ticket  WxCardTicketGetter // 获取授权页链接需要的 api_ticket
+	// 根据订单和用户填写的抬头开具发票(上传 PDF 等), 返回插卡需要的发票信息, 必填
+	Issue func(ctx context.Context, order *AuthorizedOrder) (*InvoiceInsertCardExtUser, error)
+	// 插卡成功, 可选
+	OnInserted func(ctx context.Context, order *AuthorizedOrder, result *InvoiceInsertResult)
+	// 用户拒绝授权或者授权失败, 可选
+	OnFailed func(ctx context.Context, orderID string, err error)
+}
+
+// Workflow 开票流程: GetAuthUrl → 用户授权(EventAuthorizeInvoice) → GetAuthData → Insert
+type Workflow struct {
+	api    *InvoiceApi
+	config *WorkflowConfig
+	mutex  sync.RWMutex
+	cardID string // 初始为 config.CardID, CreateCard 之后更新
+}
+
+func NewWorkflow(api *InvoiceApi, config *WorkflowConfig) *Workflow {
+	if config.Issue == nil {
+		panic("invoice_api: WorkflowConfig.Issue is required")
+	}
+	return &Workflow{api: api, config: config, cardID: config.CardID}
+}
+
+// CreateCard 创建发票卡券模板, 之后的开票使用新的模板
+func (w *Workflow) CreateCard(ctx context.Context, param *CreateCardObj) (string, error) {
+	cardID, err := w.api.PlatformCreateCard(ctx, param)
+	if err != nil {
+		return "", err
+	}
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.cardID = cardID
+	return cardID, nil
+}
+
+// CardID 当前使用的发票卡券模板
+func (w *Workflow) CardID() string {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	return w.cardID
+}
+
+// AuthUrl 获取订单的授权页链接, money 单位为分, authType 为 0 开票授权 1 填写字段开票授权 2 领票授权
+func (w *Workflow) AuthUrl(
+	ctx context.Context,
+	orderID string,
+	money int,
+	source, redirectUrl string,
+	authType int,
+) (*AuthUrlResult, error) {
+	ticket, err := w.config.Ticket.GetWxCardApiTicket(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return w.api.GetAuthUrl(ctx, &AuthUrlObj{
+		SPappID:     w.config.SPappID,
+		OrderID:     orderID,
+		Money:       money,
+		Timestamp:   time.Now().Unix(),
+		Source:      source,
+		RedirectURL: redirectUrl,
+		Ticket:      ticket,
+		Type:        authType,
+	})
+}
+
+// HandleEvent 处理授权完成事件推送, 授权成功之后开票并插入用户卡包
+// 非 EventAuthorizeInvoice 事件返回 false
+func (w *Workflow) HandleEvent(ctx context.Context, event interface{}) (bool, error) {
+	e, ok := event.(*server_api.EventAuthorizeInvoice)
+	if !ok {
+		return false, nil
+	}
+
+	if e.SuccOrderId == "" {
+		if w.config.OnFailed != nil {
+			w.config.OnFailed(ctx, e.FailOrderId, ErrOrderNotAuthorized)
+		}
+		return true, nil
+	}
+
+	_, err := w.Process(ctx, &AuthorizedOrder{
+		OrderID:        e.SuccOrderId,
+		AuthorizeAppID: e.AuthorizeAppId,
+		Source:         e.Source,
+	})
+	if err != nil && w.config.OnFailed != nil {
+		w.config.OnFailed(ctx, e.SuccOrderId, err)
+	}
+	return true, err
+}
+
+// Process 确认订单授权状态, 开票并插入用户卡包; 可用于遗漏推送之后的补偿
+func (w *Workflow) Process(ctx context.Context, order *AuthorizedOrder) (*InvoiceInsertResult, error) {
+	cardID := w.CardID()
+	if cardID == "" {
+		return nil, errors.New("invoice card id is empty, call CreateCard first")
+	}
+
+	authData, err := w.api.GetAuthData(ctx, &AuthDataObj{
+		OrderID: order.OrderID,
+		SPappID: w.config.SPappID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if authData.InvoiceStatus != InvoiceStatusAuthSuccess {
+		return nil, fmt.Errorf(
+			"order %s status '%s', %w", order.OrderID, authData.InvoiceStatus, ErrOrderNotAuthorized,
+		)
+	}
+	order.AuthData = authData
+
+	userData, err := w.config.Issue(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+
+	cardExt := &InvoiceInsertCardExt{NonceStr: utils.GetRandString(16)}
+	cardExt.UserCard.InvoiceUserData = userData
+	appid := order.AuthorizeAppID
+	if appid == "" {
+		appid = w.config.Appid
+	}
+	result, err := w.api.Insert(ctx, &InvoiceInsertObj{
+		OrderID: order.OrderID,
+		CardID:  cardID,
+		Appid:   appid,
+		CardExt: cardExt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if w.config.OnInserted != nil {
+		w.config.OnInserted(ctx, order, result)
+	}
+	return result, nil
+}
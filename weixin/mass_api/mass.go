@@ -0,0 +1,297 @@
+package mass_api
+
+// Package mass 群发消息
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/lixinio/weixin/utils"
+)
+
+const (
+	apiSendAll  = "/cgi-bin/message/mass/sendall"
+	apiSend     = "/cgi-bin/message/mass/send"
+	apiPreview  = "/cgi-bin/message/mass/preview"
+	apiDelete   = "/cgi-bin/message/mass/delete"
+	apiGet      = "/cgi-bin/message/mass/get"
+	apiSpeedGet = "/cgi-bin/message/mass/speed/get"
+	apiSpeedSet = "/cgi-bin/message/mass/speed/set"
+)
+
+const (
+	MsgTypeMpNews  = "mpnews"
+	MsgTypeText    = "text"
+	MsgTypeVoice   = "voice"
+	MsgTypeImage   = "image"
+	MsgTypeMpVideo = "mpvideo"
+	MsgTypeWxCard  = "wxcard"
+)
+
+const (
+	minOpenIDCount = 2     // 根据OpenID列表群发, 至少2个
+	maxOpenIDCount = 10000 // 根据OpenID列表群发, 最多10000个
+)
+
+var ErrTooFewOpenIDs = errors.New("mass send requires at least 2 openids")
+
+type MassApi struct {
+	*utils.Client
+}
+
+func NewApi(client *utils.Client) *MassApi {
+	return &MassApi{Client: client}
+}
+
+type MediaID struct {
+	MediaID string `json:"media_id"`
+}
+
+type MassImages struct {
+	MediaIDs           []string `json:"media_ids"`
+	Recommend          string   `json:"recommend,omitempty"`
+	NeedOpenComment    int      `json:"need_open_comment"`
+	OnlyFansCanComment int      `json:"only_fans_can_comment"`
+}
+
+// MassMessage 群发的消息内容, 根据 MsgType 填充对应的字段
+type MassMessage struct {
+	MsgType string   `json:"msgtype"`
+	MpNews  *MediaID `json:"mpnews,omitempty"`
+	Text    *struct {
+		Content string `json:"content"`
+	} `json:"text,omitempty"`
+	Voice   *MediaID    `json:"voice,omitempty"`
+	Images  *MassImages `json:"images,omitempty"`
+	MpVideo *MediaID    `json:"mpvideo,omitempty"`
+	WxCard  *struct {
+		CardID string `json:"card_id"`
+	} `json:"wxcard,omitempty"`
+	// 图文消息被判定为转载时，是否继续群发。 1为继续群发（转载），0为停止群发。 该参数默认为0
+	SendIgnoreReprint int `json:"send_ignore_reprint,omitempty"`
+	// 开发者侧群发msgid，长度限制64字节，如不填，则后台默认以群发范围和群发内容的摘要值做为clientmsgid
+	ClientMsgID string `json:"clientmsgid,omitempty"`
+}
+
+func NewMpNewsMessage(mediaID string) *MassMessage {
+	return &MassMessage{MsgType: MsgTypeMpNews, MpNews: &MediaID{mediaID}}
+}
+
+func NewTextMessage(content string) *MassMessage {
+	return &MassMessage{MsgType: MsgTypeText, Text: &struct {
+		Content string `json:"content"`
+	}{content}}
+}
+
+func NewVoiceMessage(mediaID string) *MassMessage {
+	return &MassMessage{MsgType: MsgTypeVoice, Voice: &MediaID{mediaID}}
+}
+
+func NewImageMessage(images *MassImages) *MassMessage {
+	return &MassMessage{MsgType: MsgTypeImage, Images: images}
+}
+
+func NewMpVideoMessage(mediaID string) *MassMessage {
+	return &MassMessage{MsgType: MsgTypeMpVideo, MpVideo: &MediaID{mediaID}}
+}
+
+func NewWxCardMessage(cardID string) *MassMessage {
+	return &MassMessage{MsgType: MsgTypeWxCard, WxCard: &struct {
+		CardID string `json:"card_id"`
+	}{cardID}}
+}
+
+type MassResult struct {
+	utils.WeixinError
+	MsgID     int64 `json:"msg_id"`
+	MsgDataID int64 `json:"msg_data_id"` // 消息的数据ID，该字段只有在群发图文消息时，才会出现
+}
+
+type massFilter struct {
+	IsToAll bool `json:"is_to_all"`
+	TagID   int  `json:"tag_id,omitempty"`
+}
+
+/*
+根据标签进行群发
+tagID 为 0 时, 发送给全部用户
+群发任务完成后, 会推送 MASSSENDJOBFINISH 事件, 参考 server_api.EventMassSendJobFinish
+See: https://developers.weixin.qq.com/doc/offiaccount/Message_Management/Batch_Sends_and_Originality_Checks.html
+POST https://api.weixin.qq.com/cgi-bin/message/mass/sendall?access_token=ACCESS_TOKEN
+*/
+func (api *MassApi) SendAll(
+	ctx context.Context, tagID int, message *MassMessage,
+) (*MassResult, error) {
+	param := &struct {
+		Filter *massFilter `json:"filter"`
+		*MassMessage
+	}{
+		Filter:      &massFilter{IsToAll: tagID == 0, TagID: tagID},
+		MassMessage: message,
+	}
+
+	result := &MassResult{}
+	if err := api.Client.HTTPPostJson(ctx, apiSendAll, param, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+/*
+根据OpenID列表群发
+OpenID 至少2个, 超过10000个时, 自动拆分成多次群发, 返回每次群发的结果
+拆分时每次群发的 ClientMsgID 追加序号(例如 id-0, id-1), 避免被微信当作重复群发(45065)
+See: https://developers.weixin.qq.com/doc/offiaccount/Message_Management/Batch_Sends_and_Originality_Checks.html
+POST https://api.weixin.qq.com/cgi-bin/message/mass/send?access_token=ACCESS_TOKEN
+*/
+func (api *MassApi) Send(
+	ctx context.Context, openIDs []string, message *MassMessage,
+) ([]*MassResult, error) {
+	if len(openIDs) < minOpenIDCount {
+		return nil, ErrTooFewOpenIDs
+	}
+
+	results := []*MassResult{}
+	chunks := chunkOpenIDs(openIDs)
+	for i, chunk := range chunks {
+		chunkMessage := *message
+		if message.ClientMsgID != "" && len(chunks) > 1 {
+			chunkMessage.ClientMsgID = fmt.Sprintf("%s-%d", message.ClientMsgID, i)
+		}
+		param := &struct {
+			ToUser []string `json:"touser"`
+			*MassMessage
+		}{
+			ToUser:      chunk,
+			MassMessage: &chunkMessage,
+		}
+
+		result := &MassResult{}
+		if err := api.Client.HTTPPostJson(ctx, apiSend, param, result); err != nil {
+			return results, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// chunkOpenIDs 按照最大10000个拆分, 并且保证每组至少2个
+func chunkOpenIDs(openIDs []string) [][]string {
+	chunks := [][]string{}
+	for len(openIDs) > maxOpenIDCount {
+		size := maxOpenIDCount
+		if len(openIDs)-size < minOpenIDCount {
+			// 剩余的不足2个, 从当前组匀一个出来
+			size = len(openIDs) - minOpenIDCount
+		}
+		chunks = append(chunks, openIDs[:size])
+		openIDs = openIDs[size:]
+	}
+	if len(openIDs) > 0 {
+		chunks = append(chunks, openIDs)
+	}
+	return chunks
+}
+
+/*
+预览接口
+开发者可通过该接口发送消息给指定用户，在手机端查看消息的样式和排版。为了满足第三方平台开发者的需求，在保留对openID预览能力的同时，增加了对指定微信号发送预览的能力，但该能力每日调用次数有限制（100次）
+openID 和 wxName 二选一, 同时存在时 wxName 优先
+See: https://developers.weixin.qq.com/doc/offiaccount/Message_Management/Batch_Sends_and_Originality_Checks.html
+POST https://api.weixin.qq.com/cgi-bin/message/mass/preview?access_token=ACCESS_TOKEN
+*/
+func (api *MassApi) Preview(
+	ctx context.Context, openID, wxName string, message *MassMessage,
+) (*MassResult, error) {
+	param := &struct {
+		ToUser   string `json:"touser,omitempty"`
+		ToWxName string `json:"towxname,omitempty"`
+		*MassMessage
+	}{
+		ToUser:      openID,
+		ToWxName:    wxName,
+		MassMessage: message,
+	}
+
+	result := &MassResult{}
+	if err := api.Client.HTTPPostJson(ctx, apiPreview, param, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+/*
+删除群发
+群发之后，随时可以通过该接口删除群发。只有已经发送成功的消息才能删除
+articleIdx 要删除的文章在图文消息中的位置，第一篇编号为1，该字段不填或填0会删除全部文章
+See: https://developers.weixin.qq.com/doc/offiaccount/Message_Management/Batch_Sends_and_Originality_Checks.html
+POST https://api.weixin.qq.com/cgi-bin/message/mass/delete?access_token=ACCESS_TOKEN
+*/
+func (api *MassApi) Delete(ctx context.Context, msgID int64, articleIdx int) error {
+	param := &struct {
+		MsgID      int64 `json:"msg_id"`
+		ArticleIdx int   `json:"article_idx,omitempty"`
+	}{msgID, articleIdx}
+	return api.Client.HTTPPostJson(ctx, apiDelete, param, nil)
+}
+
+const (
+	MassStatusSendSuccess = "SEND_SUCCESS" // 发送成功
+	MassStatusSending     = "SENDING"      // 发送中
+	MassStatusSendFail    = "SEND_FAIL"    // 发送失败
+	MassStatusDelete      = "DELETE"       // 已删除
+)
+
+/*
+查询群发消息发送状态
+See: https://developers.weixin.qq.com/doc/offiaccount/Message_Management/Batch_Sends_and_Originality_Checks.html
+POST https://api.weixin.qq.com/cgi-bin/message/mass/get?access_token=ACCESS_TOKEN
+*/
+func (api *MassApi) Get(ctx context.Context, msgID int64) (string, error) {
+	param := &struct {
+		MsgID int64 `json:"msg_id"`
+	}{msgID}
+
+	result := &struct {
+		utils.WeixinError
+		MsgID     int64  `json:"msg_id"`
+		MsgStatus string `json:"msg_status"`
+	}{}
+	if err := api.Client.HTTPPostJson(ctx, apiGet, param, result); err != nil {
+		return "", err
+	}
+	return result.MsgStatus, nil
+}
+
+// 群发速度的级别, 0: 80w/分钟, 1: 60w/分钟, 2: 45w/分钟, 3: 30w/分钟, 4: 10w/分钟
+type MassSpeed struct {
+	utils.WeixinError
+	Speed     int `json:"speed"`
+	RealSpeed int `json:"realspeed"` // 群发速度的真实值 单位：万/分钟
+}
+
+/*
+获取群发速度
+See: https://developers.weixin.qq.com/doc/offiaccount/Message_Management/Batch_Sends_and_Originality_Checks.html
+POST https://api.weixin.qq.com/cgi-bin/message/mass/speed/get?access_token=ACCESS_TOKEN
+*/
+func (api *MassApi) GetSpeed(ctx context.Context) (*MassSpeed, error) {
+	result := &MassSpeed{}
+	if err := api.Client.HTTPPostJson(ctx, apiSpeedGet, struct{}{}, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+/*
+设置群发速度
+See: https://developers.weixin.qq.com/doc/offiaccount/Message_Management/Batch_Sends_and_Originality_Checks.html
+POST https://api.weixin.qq.com/cgi-bin/message/mass/speed/set?access_token=ACCESS_TOKEN
+*/
+func (api *MassApi) SetSpeed(ctx context.Context, speed int) error {
+	param := &struct {
+		Speed int `json:"speed"`
+	}{speed}
+	return api.Client.HTTPPostJson(ctx, apiSpeedSet, param, nil)
+}
@@ -0,0 +1,112 @@
+package mass_api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/lixinio/weixin/test"
+	"github.com/lixinio/weixin/utils"
+	"github.com/lixinio/weixin/utils/redis"
+	"github.com/lixinio/weixin/weixin/official_account"
+	"github.com/stretchr/testify/require"
+)
+
+func makeOpenIDs(count int) []string {
+	openIDs := make([]string, count)
+	for i := range openIDs {
+		openIDs[i] = fmt.Sprintf("openid-%d", i)
+	}
+	return openIDs
+}
+
+func TestChunkOpenIDs(t *testing.T) {
+	for _, item := range []struct {
+		count int
+		sizes []int
+	}{
+		{0, []int{}},
+		{2, []int{2}},
+		{10000, []int{10000}},
+		{10001, []int{9999, 2}},
+		{10002, []int{10000, 2}},
+		{20001, []int{10000, 9999, 2}},
+		{25000, []int{10000, 10000, 5000}},
+	} {
+		chunks := chunkOpenIDs(makeOpenIDs(item.count))
+		sizes := []int{}
+		for _, chunk := range chunks {
+			sizes = append(sizes, len(chunk))
+		}
+		require.Equal(t, item.sizes, sizes)
+	}
+}
+
+func TestSend(t *testing.T) {
+	var (
+		mutex    sync.Mutex
+		payloads []map[string]interface{}
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, apiSend, r.URL.Path)
+		payload := map[string]interface{}{}
+		json.NewDecoder(r.Body).Decode(&payload)
+		mutex.Lock()
+		payloads = append(payloads, payload)
+		msgID := len(payloads)
+		mutex.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"errcode":0,"errmsg":"send job submission success","msg_id":%d}`, msgID)
+	}))
+	defer server.Close()
+	massApi := NewApi(utils.NewClient(server.URL, utils.StaticClientAccessTokenGetter("token")))
+	ctx := context.Background()
+
+	_, err := massApi.Send(ctx, makeOpenIDs(1), NewTextMessage("hello"))
+	require.Equal(t, ErrTooFewOpenIDs, err)
+	require.Equal(t, 0, len(payloads))
+
+	message := NewTextMessage("hello")
+	message.ClientMsgID = "id"
+	results, err := massApi.Send(ctx, makeOpenIDs(10001), message)
+	require.Equal(t, nil, err)
+	require.Equal(t, 2, len(results))
+	require.Equal(t, int64(1), results[0].MsgID)
+	require.Equal(t, int64(2), results[1].MsgID)
+	require.Equal(t, "id", message.ClientMsgID)
+
+	require.Equal(t, 2, len(payloads))
+	for i, size := range []int{9999, 2} {
+		require.Equal(t, fmt.Sprintf("id-%d", i), payloads[i]["clientmsgid"])
+		require.Equal(t, MsgTypeText, payloads[i]["msgtype"])
+		require.Equal(t, size, len(payloads[i]["touser"].([]interface{})))
+	}
+	require.Equal(t, "openid-9999", payloads[1]["touser"].([]interface{})[0])
+
+	// 不拆分时 ClientMsgID 保持不变
+	payloads = nil
+	_, err = massApi.Send(ctx, makeOpenIDs(2), message)
+	require.Equal(t, nil, err)
+	require.Equal(t, "id", payloads[0]["clientmsgid"])
+}
+
+func TestPreview(t *testing.T) {
+	redis := redis.NewRedis(&redis.Config{RedisUrl: test.CacheUrl})
+	officialAccount := official_account.New(redis, redis, &official_account.Config{
+		Appid:  test.OfficialAccountAppid,
+		Secret: test.OfficialAccountSecret,
+	})
+	ctx := context.Background()
+	massApi := NewApi(officialAccount.Client)
+
+	_, err := massApi.Preview(ctx, test.OfficialAccountOpenid, "", NewTextMessage("群发预览"))
+	require.Equal(t, nil, err)
+
+	speed, err := massApi.GetSpeed(ctx)
+	require.Equal(t, nil, err)
+	fmt.Println(speed.Speed, speed.RealSpeed)
+}
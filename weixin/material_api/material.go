@@ -0,0 +1,199 @@
+package material_api
+
+// Package material 素材管理
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+
+	"github.com/lixinio/weixin/utils"
+)
+
+const (
+	apiUpload    = "/cgi-bin/media/upload"
+	apiUploadImg = "/cgi-bin/media/uploadimg"
+	apiGet       = "/cgi-bin/media/get"
+	apiJssdk     = "/cgi-bin/media/get/jssdk"
+)
+
+const (
+	MediaTypeImage = "image"
+	MediaTypeVoice = "voice"
+	MediaTypeVideo = "video"
+	MediaTypeThumb = "thumb" // 缩略图, 主要用于视频与音乐格式的缩略图
+	MediaTypeNews  = "news"  // 图文, 只用于获取素材列表
+)
+
+// 素材是图文/视频, 接口返回的是json而不是文件内容
+var ErrMaterialNotFile = errors.New("material is not a file")
+
+type MaterialApi struct {
+	*utils.Client
+}
+
+func NewApi(client *utils.Client) *MaterialApi {
+	return &MaterialApi{Client: client}
+}
+
+type MaterialID struct {
+	utils.WeixinError
+	Type         string `json:"type"`
+	MediaID      string `json:"media_id"`
+	ThumbMediaID string `json:"thumb_media_id"` // 类型为thumb时, 返回的是thumb_media_id
+	CreatedAt    int64  `json:"created_at"`
+}
+
+/*
+新增临时素材
+媒体文件在微信后台保存时间为3天，即3天后media_id失效
+See: https://developers.weixin.qq.com/doc/offiaccount/Asset_Management/New_temporary_materials.html
+POST(@media) https://api.weixin.qq.com/cgi-bin/media/upload?access_token=ACCESS_TOKEN&type=TYPE
+*/
+func (api *MaterialApi) Upload(
+	ctx context.Context,
+	filename string,
+	content io.Reader,
+	mediaType string,
+) (result *MaterialID, err error) {
+	result = &MaterialID{}
+	if err := api.Client.HttpFile(
+		ctx, apiUpload, "media", filename, content, func(params url.Values) {
+			params.Add("type", mediaType)
+		}, result,
+	); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+/*
+新增临时素材, 已知文件大小, 不使用分块上传
+*/
+func (api *MaterialApi) UploadWithLength(
+	ctx context.Context,
+	filename string,
+	content io.Reader,
+	length int64,
+	mediaType string,
+) (result *MaterialID, err error) {
+	params := url.Values{}
+	params.Add("type", mediaType)
+
+	result = &MaterialID{}
+	if err := api.Client.HTTPUpload(
+		ctx, apiUpload+"?"+params.Encode(), content, "media", filename, length, result,
+	); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type MaterialUrl struct {
+	utils.WeixinError
+	URL string `json:"url"`
+}
+
+/*
+上传图文消息内的图片获取URL
+本接口所上传的图片不占用公众号的素材库中图片数量的100000个的限制。图片仅支持jpg/png格式，大小必须在1MB以下
+See: https://developers.weixin.qq.com/doc/offiaccount/Asset_Management/Adding_Permanent_Assets.html
+POST(@media) https://api.weixin.qq.com/cgi-bin/media/uploadimg?access_token=ACCESS_TOKEN
+*/
+func (api *MaterialApi) UploadImg(
+	ctx context.Context,
+	filename string,
+	content io.Reader,
+) (url string, err error) {
+	result := &MaterialUrl{}
+	if err := api.Client.HttpFile(
+		ctx, apiUploadImg, "media", filename, content, nil, result,
+	); err != nil {
+		return "", err
+	}
+	return result.URL, nil
+}
+
+// videoError 临时视频素材返回的是下载地址(json)
+func videoError(mediaID string, err error) error {
+	if errors.Is(err, utils.ErrorJsonResponse) {
+		return fmt.Errorf("media %s is video, use GetVideo, %w", mediaID, ErrMaterialNotFile)
+	}
+	return err
+}
+
+/*
+获取临时素材 (视频素材请使用 GetVideo, 否则返回 ErrMaterialNotFile)
+See: https://developers.weixin.qq.com/doc/offiaccount/Asset_Management/Get_temporary_materials.html
+GET https://api.weixin.qq.com/cgi-bin/media/get?access_token=ACCESS_TOKEN&media_id=MEDIA_ID
+*/
+func (api *MaterialApi) Get(ctx context.Context, mediaID string) ([]byte, error) {
+	resp, err := api.Client.HTTPGetRaw(ctx, apiGet, func(params url.Values) {
+		params.Add("media_id", mediaID)
+	})
+	if err != nil {
+		return nil, videoError(mediaID, err)
+	}
+
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// Save 获取临时素材, 直接写入 saver, 避免大文件占用内存
+func (api *MaterialApi) Save(ctx context.Context, mediaID string, saver io.Writer) error {
+	return api.save(ctx, apiGet, mediaID, saver)
+}
+
+/*
+获取临时视频素材
+视频文件不支持https下载，调用该接口返回的是视频的下载地址
+See: https://developers.weixin.qq.com/doc/offiaccount/Asset_Management/Get_temporary_materials.html
+GET https://api.weixin.qq.com/cgi-bin/media/get?access_token=ACCESS_TOKEN&media_id=MEDIA_ID
+*/
+func (api *MaterialApi) GetVideo(ctx context.Context, mediaID string) (string, error) {
+	result := &struct {
+		utils.WeixinError
+		VideoUrl string `json:"video_url"`
+	}{}
+	if err := api.Client.HTTPGetWithParams(ctx, apiGet, func(params url.Values) {
+		params.Add("media_id", mediaID)
+	}, result); err != nil {
+		return "", err
+	}
+	return result.VideoUrl, nil
+}
+
+/*
+高清语音素材获取接口
+公众号可以使用本接口获取从JSSDK的uploadVoice接口上传的临时语音素材，格式为speex，16K采样率
+See: https://developers.weixin.qq.com/doc/offiaccount/Asset_Management/Get_temporary_materials.html
+GET https://api.weixin.qq.com/cgi-bin/media/get/jssdk?access_token=ACCESS_TOKEN&media_id=MEDIA_ID
+*/
+func (api *MaterialApi) SaveJssdk(ctx context.Context, mediaID string, saver io.Writer) error {
+	return api.save(ctx, apiJssdk, mediaID, saver)
+}
+
+func (api *MaterialApi) save(
+	ctx context.Context, uri, mediaID string, saver io.Writer,
+) error {
+	resp, err := api.Client.HTTPGetRaw(ctx, uri, func(params url.Values) {
+		params.Add("media_id", mediaID)
+	})
+	if err != nil {
+		return videoError(mediaID, err)
+	}
+	defer resp.Body.Close()
+
+	_, err = io.Copy(saver, resp.Body)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package material_api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/lixinio/weixin/test"
+	"github.com/lixinio/weixin/utils"
+	"github.com/lixinio/weixin/utils/redis"
+	"github.com/lixinio/weixin/weixin/official_account"
+	"github.com/stretchr/testify/require"
+)
+
+func initOfficialAccount() *utils.Client {
+	redis := redis.NewRedis(&redis.Config{RedisUrl: test.CacheUrl})
+	officialAccount := official_account.New(redis, redis, &official_account.Config{
+		Appid:  test.OfficialAccountAppid,
+		Secret: test.OfficialAccountSecret,
+	})
+	return officialAccount.Client
+}
+
+func fileHash(t *testing.T, r io.Reader) string {
+	hasher := sha256.New()
+	_, err := io.Copy(hasher, r)
+	require.Empty(t, err)
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+func TestTemporaryMaterial(t *testing.T) {
+	ctx := context.Background()
+	materialApi := NewApi(initOfficialAccount())
+
+	file, err := os.Open(test.ImagePath)
+	require.Empty(t, err)
+	defer file.Close()
+
+	result, err := materialApi.Upload(ctx, test.ImagePath, file, MediaTypeImage)
+	require.Empty(t, err)
+	require.Equal(t, result.Type, MediaTypeImage)
+	fmt.Println(result.MediaID, result.CreatedAt, result.Type)
+
+	file.Seek(0, 0)
+	originHash := fileHash(t, file)
+
+	{
+		resp, err := materialApi.Get(ctx, result.MediaID)
+		require.Empty(t, err)
+		h := sha256.Sum256(resp)
+		require.Equal(t, hex.EncodeToString(h[:]), originHash)
+	}
+
+	{
+		r, w := io.Pipe()
+		go func(t *testing.T) {
+			defer w.Close()
+			err := materialApi.Save(ctx, result.MediaID, w)
+			require.Empty(t, err)
+		}(t)
+		require.Equal(t, fileHash(t, r), originHash)
+	}
+
+	{
+		file.Seek(0, 0)
+		url, err := materialApi.UploadImg(ctx, test.ImagePath, file)
+		require.Empty(t, err)
+		require.NotEmpty(t, url)
+	}
+}
+
+func TestPermanentMaterial(t *testing.T) {
+	ctx := context.Background()
+	materialApi := NewApi(initOfficialAccount())
+
+	file, err := os.Open(test.ImagePath)
+	require.Empty(t, err)
+	defer file.Close()
+
+	result, err := materialApi.AddMaterial(ctx, test.ImagePath, file, MediaTypeImage)
+	require.Empty(t, err)
+	require.NotEmpty(t, result.URL)
+
+	file.Seek(0, 0)
+	originHash := fileHash(t, file)
+
+	{
+		r, w := io.Pipe()
+		go func(t *testing.T) {
+			defer w.Close()
+			err := materialApi.SaveMaterial(ctx, result.MediaID, w)
+			require.Empty(t, err)
+		}(t)
+		require.Equal(t, fileHash(t, r), originHash)
+	}
+
+	{
+		count, err := materialApi.GetMaterialCount(ctx)
+		require.Empty(t, err)
+		require.Greater(t, count.ImageCount, 0)
+	}
+
+	{
+		// 遍历素材, 一定能找到刚刚上传的
+		exist := false
+		it := materialApi.NewMaterialIterator(MediaTypeImage)
+		for {
+			item, err := it.Next(ctx)
+			if err == io.EOF {
+				break
+			}
+			require.Empty(t, err)
+			if item.MediaID == result.MediaID {
+				exist = true
+			}
+		}
+		require.True(t, exist)
+	}
+
+	err = materialApi.DelMaterial(ctx, result.MediaID)
+	require.Empty(t, err)
+}
+
+func TestMaterialJsonResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; encoding=utf-8")
+		if r.URL.Path == apiGet {
+			fmt.Fprint(w, `{"video_url":"http://example.com/video.mp4"}`)
+			return
+		}
+		fmt.Fprint(w, `{"news_item":[{"title":"title","thumb_media_id":"thumb","content":"content"}]}`)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	materialApi := NewApi(utils.NewClient(server.URL, utils.StaticClientAccessTokenGetter("token")))
+
+	err := materialApi.SaveMaterial(ctx, "news_media_id", ioutil.Discard)
+	require.True(t, errors.Is(err, ErrMaterialNotFile), err)
+
+	news, err := materialApi.GetNewsMaterial(ctx, "news_media_id")
+	require.Empty(t, err)
+	require.Equal(t, 1, len(news.NewsItem))
+	require.Equal(t, "thumb", news.NewsItem[0].ThumbMediaID)
+
+	_, err = materialApi.Get(ctx, "video_media_id")
+	require.True(t, errors.Is(err, ErrMaterialNotFile), err)
+	err = materialApi.Save(ctx, "video_media_id", ioutil.Discard)
+	require.True(t, errors.Is(err, ErrMaterialNotFile), err)
+}
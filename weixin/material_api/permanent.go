@@ -0,0 +1,284 @@
+package material_api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+
+	"github.com/lixinio/weixin/utils"
+)
+
+const (
+	apiAddMaterial      = "/cgi-bin/material/add_material"
+	apiGetMaterial      = "/cgi-bin/material/get_material"
+	apiDelMaterial      = "/cgi-bin/material/del_material"
+	apiGetMaterialCount = "/cgi-bin/material/get_materialcount"
+	apiBatchGetMaterial = "/cgi-bin/material/batchget_material"
+)
+
+const maxBatchGetCount = 20 // 素材列表 每次最多返回20个
+
+type PermanentMaterialID struct {
+	utils.WeixinError
+	MediaID string `json:"media_id"`
+	URL     string `json:"url"` // 新增的图片素材的图片URL（仅新增图片素材时会返回该字段）
+}
+
+/*
+新增其他类型永久素材 (图片/语音/缩略图)
+See: https://developers.weixin.qq.com/doc/offiaccount/Asset_Management/Adding_Permanent_Assets.html
+POST(@media) https://api.weixin.qq.com/cgi-bin/material/add_material?access_token=ACCESS_TOKEN&type=TYPE
+*/
+func (api *MaterialApi) AddMaterial(
+	ctx context.Context,
+	filename string,
+	content io.Reader,
+	mediaType string,
+) (*PermanentMaterialID, error) {
+	result := &PermanentMaterialID{}
+	if err := api.Client.HttpFile(
+		ctx, apiAddMaterial, "media", filename, content, func(params url.Values) {
+			params.Add("type", mediaType)
+		}, result,
+	); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type VideoDescription struct {
+	Title        string `json:"title"`
+	Introduction string `json:"introduction"`
+}
+
+/*
+新增永久视频素材
+在上传视频素材时需要POST另一个表单，id为description，包含素材的描述信息
+See: https://developers.weixin.qq.com/doc/offiaccount/Asset_Management/Adding_Permanent_Assets.html
+POST(@media) https://api.weixin.qq.com/cgi-bin/material/add_material?access_token=ACCESS_TOKEN&type=video
+*/
+func (api *MaterialApi) AddVideoMaterial(
+	ctx context.Context,
+	filename string,
+	content io.Reader,
+	description *VideoDescription,
+) (*PermanentMaterialID, error) {
+	desc, err := json.Marshal(description)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PermanentMaterialID{}
+	if err := api.Client.HttpFileWithFields(
+		ctx, apiAddMaterial, "media", filename, content, map[string]string{
+			"description": string(desc),
+		}, func(params url.Values) {
+			params.Add("type", MediaTypeVideo)
+		}, result,
+	); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type mediaIDParam struct {
+	MediaID string `json:"media_id"`
+}
+
+/*
+获取永久素材 (图片/语音/缩略图), 直接写入 saver
+图文素材请使用 GetNewsMaterial, 视频素材请使用 GetVideoMaterial, 否则返回 ErrMaterialNotFile
+See: https://developers.weixin.qq.com/doc/offiaccount/Asset_Management/Getting_Permanent_Assets.html
+POST https://api.weixin.qq.com/cgi-bin/material/get_material?access_token=ACCESS_TOKEN
+*/
+func (api *MaterialApi) SaveMaterial(
+	ctx context.Context, mediaID string, saver io.Writer,
+) error {
+	resp, err := api.Client.HTTPPostDownload(ctx, apiGetMaterial, &mediaIDParam{mediaID}, nil)
+	if err != nil {
+		if errors.Is(err, utils.ErrorJsonResponse) {
+			return fmt.Errorf(
+				"material %s is news or video, use GetNewsMaterial or GetVideoMaterial, %w",
+				mediaID, ErrMaterialNotFile,
+			)
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	_, err = io.Copy(saver, resp.Body)
+	return err
+}
+
+type VideoMaterial struct {
+	utils.WeixinError
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	DownUrl     string `json:"down_url"`
+}
+
+/*
+获取永久视频素材
+See: https://developers.weixin.qq.com/doc/offiaccount/Asset_Management/Getting_Permanent_Assets.html
+POST https://api.weixin.qq.com/cgi-bin/material/get_material?access_token=ACCESS_TOKEN
+*/
+func (api *MaterialApi) GetVideoMaterial(
+	ctx context.Context, mediaID string,
+) (*VideoMaterial, error) {
+	result := &VideoMaterial{}
+	if err := api.Client.HTTPPostJson(
+		ctx, apiGetMaterial, &mediaIDParam{mediaID}, result,
+	); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type NewsMaterial struct {
+	utils.WeixinError
+	NewsItem []NewsItem `json:"news_item"`
+}
+
+/*
+获取永久图文素材
+See: https://developers.weixin.qq.com/doc/offiaccount/Asset_Management/Getting_Permanent_Assets.html
+POST https://api.weixin.qq.com/cgi-bin/material/get_material?access_token=ACCESS_TOKEN
+*/
+func (api *MaterialApi) GetNewsMaterial(
+	ctx context.Context, mediaID string,
+) (*NewsMaterial, error) {
+	result := &NewsMaterial{}
+	if err := api.Client.HTTPPostJson(
+		ctx, apiGetMaterial, &mediaIDParam{mediaID}, result,
+	); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+/*
+删除永久素材
+See: https://developers.weixin.qq.com/doc/offiaccount/Asset_Management/Deleting_Permanent_Assets.html
+POST https://api.weixin.qq.com/cgi-bin/material/del_material?access_token=ACCESS_TOKEN
+*/
+func (api *MaterialApi) DelMaterial(ctx context.Context, mediaID string) error {
+	return api.Client.HTTPPostJson(ctx, apiDelMaterial, &mediaIDParam{mediaID}, nil)
+}
+
+type MaterialCount struct {
+	utils.WeixinError
+	VoiceCount int `json:"voice_count"`
+	VideoCount int `json:"video_count"`
+	ImageCount int `json:"image_count"`
+	NewsCount  int `json:"news_count"`
+}
+
+/*
+获取素材总数
+See: https://developers.weixin.qq.com/doc/offiaccount/Asset_Management/Get_the_total_of_all_materials.html
+GET https://api.weixin.qq.com/cgi-bin/material/get_materialcount?access_token=ACCESS_TOKEN
+*/
+func (api *MaterialApi) GetMaterialCount(ctx context.Context) (*MaterialCount, error) {
+	result := &MaterialCount{}
+	if err := api.Client.HTTPGet(ctx, apiGetMaterialCount, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type NewsItem struct {
+	Title              string `json:"title"`
+	ThumbMediaID       string `json:"thumb_media_id"`
+	ShowCoverPic       int    `json:"show_cover_pic"`
+	Author             string `json:"author"`
+	Digest             string `json:"digest"`
+	Content            string `json:"content"`
+	URL                string `json:"url"`
+	ContentSourceURL   string `json:"content_source_url"`
+	NeedOpenComment    int    `json:"need_open_comment"`
+	OnlyFansCanComment int    `json:"only_fans_can_comment"`
+}
+
+type MaterialItem struct {
+	MediaID    string `json:"media_id"`
+	Name       string `json:"name"`
+	UpdateTime int64  `json:"update_time"`
+	URL        string `json:"url"`
+	Content    *struct {
+		NewsItem []NewsItem `json:"news_item"`
+	} `json:"content,omitempty"` // 图文素材才有
+}
+
+type MaterialList struct {
+	utils.WeixinError
+	TotalCount int            `json:"total_count"`
+	ItemCount  int            `json:"item_count"`
+	Item       []MaterialItem `json:"item"`
+}
+
+/*
+获取素材列表
+See: https://developers.weixin.qq.com/doc/offiaccount/Asset_Management/Get_materials_list.html
+POST https://api.weixin.qq.com/cgi-bin/material/batchget_material?access_token=ACCESS_TOKEN
+*/
+func (api *MaterialApi) BatchGetMaterial(
+	ctx context.Context, mediaType string, offset, count int,
+) (*MaterialList, error) {
+	param := &struct {
+		Type   string `json:"type"`
+		Offset int    `json:"offset"`
+		Count  int    `json:"count"`
+	}{mediaType, offset, count}
+
+	result := &MaterialList{}
+	if err := api.Client.HTTPPostJson(ctx, apiBatchGetMaterial, param, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// MaterialIterator 素材列表迭代器, 自动翻页
+type MaterialIterator struct {
+	api       *MaterialApi
+	mediaType string
+	offset    int
+	items     []MaterialItem
+	total     int
+	started   bool
+}
+
+func (api *MaterialApi) NewMaterialIterator(mediaType string) *MaterialIterator {
+	return &MaterialIterator{
+		api:       api,
+		mediaType: mediaType,
+	}
+}
+
+// Next 返回下一个素材, 遍历完毕返回 io.EOF
+func (it *MaterialIterator) Next(ctx context.Context) (*MaterialItem, error) {
+	if len(it.items) == 0 {
+		if it.started && it.offset >= it.total {
+			return nil, io.EOF
+		}
+
+		result, err := it.api.BatchGetMaterial(ctx, it.mediaType, it.offset, maxBatchGetCount)
+		if err != nil {
+			return nil, err
+		}
+		it.started = true
+		it.total = result.TotalCount
+		it.offset += len(result.Item)
+		it.items = result.Item
+
+		if len(it.items) == 0 {
+			return nil, io.EOF
+		}
+	}
+
+	item := it.items[0]
+	it.items = it.items[1:]
+	return &item, nil
+}
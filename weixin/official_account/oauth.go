@@ -85,6 +85,7 @@ type OauthAccessToken struct {
 	RefreshToken string `json:"refresh_token"`
 	Openid       string `json:"openid"`
 	Scope        string `json:"scope"`
+	Unionid      string `json:"unionid"` // 公众号绑定到开放平台帐号之后才有
 }
 
 /*
@@ -193,10 +194,10 @@ func (officialAccount *OfficialAccount) Auth(
 	ctx context.Context, accessToken string, openid string,
 ) error {
 	// 无需 access token
-	return officialAccount.Client.HTTPGetToken(context.TODO(), apiAuth, func(params url.Values) {
+	return officialAccount.Client.HTTPGetToken(ctx, apiAuth, func(params url.Values) {
 		params.Add("access_token", accessToken)
 		params.Add("openid", openid)
-	}, nil)
+	}, &utils.WeixinError{})
 }
 
 type MpSession struct {
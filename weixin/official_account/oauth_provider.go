@@ -0,0 +1,47 @@
+package official_account
+
+import (
+	"context"
+
+	"github.com/lixinio/weixin/utils/oauthflow"
+)
+
+type oauthProvider struct {
+	officialAccount *OfficialAccount
+	scope           string
+}
+
+// OAuthProvider 公众号网页授权, 用于 oauthflow, scope 为 snsapi_base 或者 snsapi_userinfo
+func (officialAccount *OfficialAccount) OAuthProvider(scope string) oauthflow.Provider {
+	return &oauthProvider{officialAccount: officialAccount, scope: scope}
+}
+
+func (p *oauthProvider) AuthorizeUrl(redirectUri, state string) string {
+	return p.officialAccount.GetAuthorizeUrl(redirectUri, p.scope, state) + "#wechat_redirect"
+}
+
+func (p *oauthProvider) Exchange(ctx context.Context, code string) (*oauthflow.Identity, error) {
+	token, err := p.officialAccount.GetSnsAccessToken(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	identity := &oauthflow.Identity{
+		AppID:        p.officialAccount.Config.Appid,
+		OpenID:       token.Openid,
+		UnionID:      token.Unionid,
+		Scope:        token.Scope,
+		AccessToken:  token.AccessToken,
+		RefreshToken: token.RefreshToken,
+		ExpiresIn:    token.ExpiresIn,
+	}
+	if p.scope == ScopeSnsapiUserinfo {
+		// access_token 接口不一定返回 unionid, 通过用户信息获取
+		userInfo, err := p.officialAccount.GetUserInfo(ctx, token.AccessToken, token.Openid, LANG_zh_CN)
+		if err != nil {
+			return nil, err
+		}
+		identity.UnionID = userInfo.Unionid
+	}
+	return identity, nil
+}
@@ -0,0 +1,178 @@
+package qrcode_api
+
+// Package qrcode 带参数的二维码
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/lixinio/weixin/utils"
+	"github.com/lixinio/weixin/weixin/server_api"
+)
+
+var ShowQrcodeServerUrl = "https://mp.weixin.qq.com"
+
+const (
+	apiCreate     = "/cgi-bin/qrcode/create"
+	apiShowQrcode = "/cgi-bin/showqrcode"
+)
+
+const (
+	ActionQrScene         = "QR_SCENE"           // 临时的整型参数值
+	ActionQrStrScene      = "QR_STR_SCENE"       // 临时的字符串参数值
+	ActionQrLimitScene    = "QR_LIMIT_SCENE"     // 永久的整型参数值
+	ActionQrLimitStrScene = "QR_LIMIT_STR_SCENE" // 永久的字符串参数值
+)
+
+const (
+	MaxExpireSeconds = 2592000 // 临时二维码最长有效期 30天
+	MaxLimitSceneID  = 100000  // 永久二维码 场景值ID 最大值
+
+	subscribeScenePrefix = "qrscene_" // 未关注用户扫码关注, EventKey 的前缀
+)
+
+type QrcodeApi struct {
+	*utils.Client
+}
+
+func NewApi(client *utils.Client) *QrcodeApi {
+	return &QrcodeApi{Client: client}
+}
+
+type Qrcode struct {
+	utils.WeixinError
+	Ticket        string `json:"ticket"`         // 获取的二维码ticket，凭借此ticket可以在有效时间内换取二维码
+	ExpireSeconds int    `json:"expire_seconds"` // 该二维码有效时间，以秒为单位。 最大不超过2592000（即30天）
+	URL           string `json:"url"`            // 二维码图片解析后的地址，开发者可根据该地址自行生成需要的二维码图片
+}
+
+type sceneParam struct {
+	SceneID  int    `json:"scene_id,omitempty"`
+	SceneStr string `json:"scene_str,omitempty"`
+}
+
+type createParam struct {
+	ExpireSeconds int    `json:"expire_seconds,omitempty"`
+	ActionName    string `json:"action_name"`
+	ActionInfo    struct {
+		Scene sceneParam `json:"scene"`
+	} `json:"action_info"`
+}
+
+/*
+生成带参数的二维码
+See: https://developers.weixin.qq.com/doc/offiaccount/Account_Management/Generating_a_Parametric_QR_Code.html
+POST https://api.weixin.qq.com/cgi-bin/qrcode/create?access_token=TOKEN
+*/
+func (api *QrcodeApi) create(ctx context.Context, param *createParam) (*Qrcode, error) {
+	result := &Qrcode{}
+	if err := api.Client.HTTPPostJson(ctx, apiCreate, param, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CreateTemporary 临时二维码, 整型场景值, expireSeconds 为0时缺省60秒
+func (api *QrcodeApi) CreateTemporary(
+	ctx context.Context, sceneID int, expireSeconds int,
+) (*Qrcode, error) {
+	param := &createParam{ExpireSeconds: expireSeconds, ActionName: ActionQrScene}
+	param.ActionInfo.Scene.SceneID = sceneID
+	return api.create(ctx, param)
+}
+
+// CreateTemporaryStr 临时二维码, 字符串场景值(长度限制为1到64)
+func (api *QrcodeApi) CreateTemporaryStr(
+	ctx context.Context, sceneStr string, expireSeconds int,
+) (*Qrcode, error) {
+	param := &createParam{ExpireSeconds: expireSeconds, ActionName: ActionQrStrScene}
+	param.ActionInfo.Scene.SceneStr = sceneStr
+	return api.create(ctx, param)
+}
+
+// CreatePermanent 永久二维码, 整型场景值(1--100000)
+func (api *QrcodeApi) CreatePermanent(ctx context.Context, sceneID int) (*Qrcode, error) {
+	if sceneID <= 0 || sceneID > MaxLimitSceneID {
+		return nil, fmt.Errorf("invalid permanent scene id %d, should be 1 - %d", sceneID, MaxLimitSceneID)
+	}
+	param := &createParam{ActionName: ActionQrLimitScene}
+	param.ActionInfo.Scene.SceneID = sceneID
+	return api.create(ctx, param)
+}
+
+// CreatePermanentStr 永久二维码, 字符串场景值(长度限制为1到64)
+func (api *QrcodeApi) CreatePermanentStr(ctx context.Context, sceneStr string) (*Qrcode, error) {
+	param := &createParam{ActionName: ActionQrLimitStrScene}
+	param.ActionInfo.Scene.SceneStr = sceneStr
+	return api.create(ctx, param)
+}
+
+/*
+通过ticket换取二维码图片地址
+ticket正确情况下，http 返回码是200，是一张图片，可以直接展示或者下载
+See: https://developers.weixin.qq.com/doc/offiaccount/Account_Management/Generating_a_Parametric_QR_Code.html
+GET https://mp.weixin.qq.com/cgi-bin/showqrcode?ticket=TICKET
+*/
+func GetQrcodeUrl(ticket string) string {
+	params := url.Values{}
+	params.Add("ticket", ticket)
+	return ShowQrcodeServerUrl + apiShowQrcode + "?" + params.Encode()
+}
+
+// SaveQrcode 下载二维码图片, 直接写入 saver, 无需 access token
+func SaveQrcode(ctx context.Context, ticket string, saver io.Writer) error {
+	req, err := http.NewRequest(http.MethodGet, GetQrcodeUrl(ticket), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %s", resp.Status)
+	}
+
+	_, err = io.Copy(saver, resp.Body)
+	return err
+}
+
+// QrScene 扫描带参数二维码事件 中的场景信息
+type QrScene struct {
+	Scene     string // 场景值, 已去掉 qrscene_ 前缀
+	Ticket    string
+	Subscribe bool // 是否扫码关注(用户未关注时)
+}
+
+/*
+从扫码事件中提取场景值
+兼容 用户未关注时扫码关注(EventSubscribe, 带qrscene_前缀) 和 用户已关注时扫码(EventScan)
+非扫码事件(例如普通关注)返回 false
+See: https://developers.weixin.qq.com/doc/offiaccount/Message_Management/Receiving_event_pushes.html
+*/
+func GetEventScene(event interface{}) (*QrScene, bool) {
+	switch e := event.(type) {
+	case *server_api.EventSubscribe:
+		if e.Ticket == "" || !strings.HasPrefix(e.EventKey, subscribeScenePrefix) {
+			return nil, false
+		}
+		return &QrScene{
+			Scene:     strings.TrimPrefix(e.EventKey, subscribeScenePrefix),
+			Ticket:    e.Ticket,
+			Subscribe: true,
+		}, true
+	case *server_api.EventScan:
+		return &QrScene{
+			Scene:  e.EventKey,
+			Ticket: e.Ticket,
+		}, true
+	}
+	return nil, false
+}
@@ -0,0 +1,82 @@
+package qrcode_api
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/lixinio/weixin/test"
+	"github.com/lixinio/weixin/utils/redis"
+	"github.com/lixinio/weixin/weixin/official_account"
+	"github.com/lixinio/weixin/weixin/server_api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetEventScene(t *testing.T) {
+	serverApi := &server_api.ServerApi{}
+	for _, item := range []struct {
+		body  string
+		scene *QrScene
+	}{
+		{
+			body: `<xml>
+  <ToUserName><![CDATA[toUser]]></ToUserName>
+  <FromUserName><![CDATA[FromUser]]></FromUserName>
+  <CreateTime>123456789</CreateTime>
+  <MsgType><![CDATA[event]]></MsgType>
+  <Event><![CDATA[subscribe]]></Event>
+  <EventKey><![CDATA[qrscene_123123]]></EventKey>
+  <Ticket><![CDATA[TICKET]]></Ticket>
+</xml>`,
+			scene: &QrScene{Scene: "123123", Ticket: "TICKET", Subscribe: true},
+		},
+		{
+			body: `<xml>
+  <ToUserName><![CDATA[toUser]]></ToUserName>
+  <FromUserName><![CDATA[FromUser]]></FromUserName>
+  <CreateTime>123456789</CreateTime>
+  <MsgType><![CDATA[event]]></MsgType>
+  <Event><![CDATA[SCAN]]></Event>
+  <EventKey><![CDATA[channel_a]]></EventKey>
+  <Ticket><![CDATA[TICKET]]></Ticket>
+</xml>`,
+			scene: &QrScene{Scene: "channel_a", Ticket: "TICKET"},
+		},
+		{
+			// 普通关注
+			body: `<xml>
+  <ToUserName><![CDATA[toUser]]></ToUserName>
+  <FromUserName><![CDATA[FromUser]]></FromUserName>
+  <CreateTime>123456789</CreateTime>
+  <MsgType><![CDATA[event]]></MsgType>
+  <Event><![CDATA[subscribe]]></Event>
+</xml>`,
+		},
+	} {
+		event, err := serverApi.ParseXML([]byte(item.body))
+		require.Equal(t, nil, err)
+
+		scene, ok := GetEventScene(event)
+		require.Equal(t, item.scene != nil, ok)
+		require.Equal(t, item.scene, scene)
+	}
+}
+
+func TestQrcode(t *testing.T) {
+	redis := redis.NewRedis(&redis.Config{RedisUrl: test.CacheUrl})
+	officialAccount := official_account.New(redis, redis, &official_account.Config{
+		Appid:  test.OfficialAccountAppid,
+		Secret: test.OfficialAccountSecret,
+	})
+	ctx := context.Background()
+	qrcodeApi := NewApi(officialAccount.Client)
+
+	qrcode, err := qrcodeApi.CreateTemporaryStr(ctx, "test", 60)
+	require.Equal(t, nil, err)
+	require.NotEmpty(t, qrcode.Ticket)
+
+	buf := &bytes.Buffer{}
+	err = SaveQrcode(ctx, qrcode.Ticket, buf)
+	require.Equal(t, nil, err)
+	require.NotEmpty(t, buf.Bytes())
+}
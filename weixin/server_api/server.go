@@ -409,6 +409,34 @@ func parseEvent(body []byte) (m interface{}, err error) {
 			return
 		}
 		return msg, nil
+	case EventTypeSubmitInvoiceTitle:
+		msg := &EventSubmitInvoiceTitle{}
+		if err = xml.Unmarshal(body, msg); err != nil {
+			return
+		}
+		return msg, nil
+	case EventTypeUpdateInvoiceStatus:
+		msg := &EventUpdateInvoiceStatus{}
+		if err = xml.Unmarshal(body, msg); err != nil {
+			return
+		}
+		return msg, nil
+
+		// 群发任务完成
+	case EventTypeMassSendJobFinish:
+		msg := &EventMassSendJobFinish{}
+		if err = xml.Unmarshal(body, msg); err != nil {
+			return
+		}
+		return msg, nil
+
+		// 发布任务完成
+	case EventTypePublishJobFinish:
+		msg := &EventPublishJobFinish{}
+		if err = xml.Unmarshal(body, msg); err != nil {
+			return
+		}
+		return msg, nil
 
 		// 开放平台审核
 	case EventTypeWxaNickNameAudit:
@@ -441,6 +469,24 @@ func parseEvent(body []byte) (m interface{}, err error) {
 			return
 		}
 		return msg, nil
+	case EventTypeWxaMediaCheck:
+		msg := &EventWxaMediaCheck{}
+		if err = xml.Unmarshal(body, msg); err != nil {
+			return
+		}
+		return msg, nil
+	case EventTypeTradeManageRemindAccessApi:
+		msg := &EventTradeManageRemindAccessApi{}
+		if err = xml.Unmarshal(body, msg); err != nil {
+			return
+		}
+		return msg, nil
+	case EventTypeTradeManageOrderSettlement:
+		msg := &EventTradeManageOrderSettlement{}
+		if err = xml.Unmarshal(body, msg); err != nil {
+			return
+		}
+		return msg, nil
 	}
 
 	return
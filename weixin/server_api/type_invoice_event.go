@@ -1,5 +1,10 @@
 package server_api
 
+const (
+	EventTypeSubmitInvoiceTitle  = "submit_invoice_title"  // 用户提交发票抬头
+	EventTypeUpdateInvoiceStatus = "update_invoice_status" // 发票报销状态更新
+)
+
 // EventAuthorizeInvoice https://developers.weixin.qq.com/doc/offiaccount/WeChat_Invoice/E_Invoice/Vendor_API_List.html#6
 type EventAuthorizeInvoice struct {
 	Event
@@ -8,3 +13,55 @@ type EventAuthorizeInvoice struct {
 	AuthorizeAppId string // 获取授权页链接的AppId
 	Source         string // 授权来源，web：公众号开票，app：app开票，wxa：小程序开票，wap：h5开票
 }
+
+// 用户在商户专属二维码(getselecttitleurl)页面选择抬头之后推送
+// https://developers.weixin.qq.com/doc/offiaccount/WeChat_Invoice/Quick_issuing/Interface_Instructions.html
+/*
+<xml>
+  <ToUserName><![CDATA[gh_fc0a06a20993]]></ToUserName>
+  <FromUserName><![CDATA[oZI8Fj040-be6rlDohc6gkoPOQTQ]]></FromUserName>
+  <CreateTime>1475134700</CreateTime>
+  <MsgType><![CDATA[event]]></MsgType>
+  <Event><![CDATA[submit_invoice_title]]></Event>
+  <title><![CDATA[样例公司抬头]]></title>
+  <tax_no><![CDATA[1486715661]]></tax_no>
+  <addr><![CDATA[abc]]></addr>
+  <phone><![CDATA[13313331333]]></phone>
+  <bank_type><![CDATA[bt]]></bank_type>
+  <bank_no><![CDATA[bn]]></bank_no>
+  <attach><![CDATA[at]]></attach>
+  <title_type><![CDATA[InvoiceUserTitleBusinessType]]></title_type>
+</xml>
+*/
+type EventSubmitInvoiceTitle struct {
+	Event
+	Title     string `xml:"title"`
+	TaxNo     string `xml:"tax_no"`
+	Addr      string `xml:"addr"`
+	Phone     string `xml:"phone"`
+	BankType  string `xml:"bank_type"`
+	BankNo    string `xml:"bank_no"`
+	Attach    string `xml:"attach"`     // 获取二维码时传入的 attach
+	TitleType string `xml:"title_type"` // InvoiceUserTitleBusinessType 单位, InvoiceUserTitlePersonType 个人
+}
+
+// 发票报销状态更新推送(报销方锁定/核销发票时推送给开票平台)
+// https://developers.weixin.qq.com/doc/offiaccount/WeChat_Invoice/E_Invoice/Invoicing_Platform_API_List.html
+/*
+<xml>
+  <ToUserName><![CDATA[gh_9e1765b5568e]]></ToUserName>
+  <FromUserName><![CDATA[ojZ8YtyVyr30HheH3CM73y7h4jJE]]></FromUserName>
+  <CreateTime>1478068440</CreateTime>
+  <MsgType><![CDATA[event]]></MsgType>
+  <Event><![CDATA[update_invoice_status]]></Event>
+  <Status><![CDATA[INVOICE_REIMBURSE_INIT]]></Status>
+  <CardId><![CDATA[pjZ8Yt1XGILfi-FUsewpnnolGgZk]]></CardId>
+  <Code><![CDATA[030541800711]]></Code>
+</xml>
+*/
+type EventUpdateInvoiceStatus struct {
+	Event
+	Status string // 报销状态 INVOICE_REIMBURSE_INIT / INVOICE_REIMBURSE_LOCK / INVOICE_REIMBURSE_CLOSURE
+	CardId string
+	Code   string
+}
@@ -0,0 +1,80 @@
+package server_api
+
+const (
+	EventTypeMassSendJobFinish = "MASSSENDJOBFINISH" // 群发任务完成
+)
+
+// 群发结果事件推送
+// https://developers.weixin.qq.com/doc/offiaccount/Message_Management/Batch_Sends_and_Originality_Checks.html
+/*
+<xml>
+  <ToUserName><![CDATA[gh_4d00ed8d6399]]></ToUserName>
+  <FromUserName><![CDATA[oV5CrjpxgaGXNHIQigzNlgLTnwic]]></FromUserName>
+  <CreateTime>1481013459</CreateTime>
+  <MsgType><![CDATA[event]]></MsgType>
+  <Event><![CDATA[MASSSENDJOBFINISH]]></Event>
+  <MsgID>1000001625</MsgID>
+  <Status><![CDATA[err(30003)]]></Status>
+  <TotalCount>0</TotalCount>
+  <FilterCount>0</FilterCount>
+  <SentCount>0</SentCount>
+  <ErrorCount>0</ErrorCount>
+  <CopyrightCheckResult>
+    <Count>2</Count>
+    <ResultList>
+      <item>
+        <ArticleIdx>1</ArticleIdx>
+        <UserDeclareState>0</UserDeclareState>
+        <AuditState>2</AuditState>
+        <OriginalArticleUrl><![CDATA[Url_1]]></OriginalArticleUrl>
+        <OriginalArticleType>1</OriginalArticleType>
+        <CanReprint>1</CanReprint>
+        <NeedReplaceContent>1</NeedReplaceContent>
+        <NeedShowReprintSource>1</NeedShowReprintSource>
+      </item>
+    </ResultList>
+    <CheckState>2</CheckState>
+  </CopyrightCheckResult>
+  <ArticleUrlResult>
+    <Count>1</Count>
+    <ResultList>
+      <item>
+        <ArticleIdx>1</ArticleIdx>
+        <ArticleUrl><![CDATA[Url]]></ArticleUrl>
+      </item>
+    </ResultList>
+  </ArticleUrlResult>
+</xml>
+*/
+type EventMassSendJobFinish struct {
+	Event
+	MsgID       int64  // 与群发接口返回的 msg_id 对应
+	Status      string // send success / send fail / err(num)
+	TotalCount  int    // 标签的粉丝数，或者openid列表中的粉丝数
+	FilterCount int    // 过滤后准备发送的粉丝数
+	SentCount   int    // 发送成功的粉丝数
+	ErrorCount  int    // 发送失败的粉丝数
+
+	CopyrightCheckResult struct {
+		Count      int
+		ResultList []struct {
+			ArticleIdx            int    // 群发文章的序号，从1开始
+			UserDeclareState      int    // 用户声明文章的状态
+			AuditState            int    // 系统校验的状态
+			OriginalArticleUrl    string // 相似原创文的url
+			OriginalArticleType   int    // 相似原创文的类型
+			CanReprint            int    // 是否能转载
+			NeedReplaceContent    int    // 是否需要替换成原创文内容
+			NeedShowReprintSource int    // 是否需要注明转载来源
+		} `xml:"ResultList>item"`
+		CheckState int // 整体校验结果 1-未被判为转载，可以群发，2-被判为转载，可以群发，3-被判为转载，不能群发
+	}
+
+	ArticleUrlResult struct {
+		Count      int
+		ResultList []struct {
+			ArticleIdx int
+			ArticleUrl string
+		} `xml:"ResultList>item"`
+	}
+}
@@ -0,0 +1,45 @@
+package server_api
+
+const (
+	EventTypePublishJobFinish = "PUBLISHJOBFINISH" // 发布任务完成
+)
+
+// 发布结果事件推送
+// https://developers.weixin.qq.com/doc/offiaccount/Publish/Callback_on_finish.html
+/*
+<xml>
+  <ToUserName><![CDATA[gh_4d00ed8d6399]]></ToUserName>
+  <FromUserName><![CDATA[oV5CrjpxgaGXNHIQigzNlgLTnwic]]></FromUserName>
+  <CreateTime>1481013459</CreateTime>
+  <MsgType><![CDATA[event]]></MsgType>
+  <Event><![CDATA[PUBLISHJOBFINISH]]></Event>
+  <PublishEventInfo>
+    <publish_id>2247503051</publish_id>
+    <publish_status>0</publish_status>
+    <article_id><![CDATA[b5O2OUs25HBxRceL7hfReg-U9QGeq9zQjiDvyWP4Hq4]]></article_id>
+    <article_detail>
+      <count>1</count>
+      <item>
+        <idx>1</idx>
+        <article_url><![CDATA[ARTICLE_URL]]></article_url>
+      </item>
+    </article_detail>
+  </PublishEventInfo>
+</xml>
+*/
+type EventPublishJobFinish struct {
+	Event
+	PublishEventInfo struct {
+		PublishID     string `xml:"publish_id"`
+		PublishStatus int    `xml:"publish_status"` // 0:成功, 1:发布中，2:原创失败, 3: 常规失败, 4:平台审核不通过, 5:成功后用户删除所有文章, 6: 成功后系统封禁所有文章
+		ArticleID     string `xml:"article_id"`
+		ArticleDetail struct {
+			Count int `xml:"count"`
+			Item  []struct {
+				Idx        int    `xml:"idx"`
+				ArticleUrl string `xml:"article_url"`
+			} `xml:"item"`
+		} `xml:"article_detail"`
+		FailIdx []int `xml:"fail_idx"` // 当发布状态为2或4时，返回不通过的文章编号，第一篇为 1
+	}
+}
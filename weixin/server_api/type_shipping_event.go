@@ -0,0 +1,58 @@
+package server_api
+
+// 小程序发货信息管理服务
+const (
+	EventTypeTradeManageRemindAccessApi = "trade_manage_remind_access_api" // 提醒接入发货信息管理服务API
+	EventTypeTradeManageOrderSettlement = "trade_manage_order_settlement"  // 订单将要结算或已经结算
+)
+
+// 提醒接入发货信息管理服务API
+// https://developers.weixin.qq.com/miniprogram/dev/platform-capabilities/business-capabilities/order-shipping/order-shipping.html
+/*
+<xml>
+	<ToUserName><![CDATA[gh_xxxxxxxxxxxx]]></ToUserName>
+	<FromUserName><![CDATA[oXXXXXXXXXXXXXXXXXXXXXXXXXXX]]></FromUserName>
+	<CreateTime>1680000000</CreateTime>
+	<MsgType><![CDATA[event]]></MsgType>
+	<Event><![CDATA[trade_manage_remind_access_api]]></Event>
+	<msg><![CDATA[xxx]]></msg>
+</xml>
+*/
+type EventTradeManageRemindAccessApi struct {
+	Event
+	Msg string `xml:"msg"` // 消息文本内容
+}
+
+// 订单将要结算或已经结算
+/*
+<xml>
+	<ToUserName><![CDATA[gh_xxxxxxxxxxxx]]></ToUserName>
+	<FromUserName><![CDATA[oXXXXXXXXXXXXXXXXXXXXXXXXXXX]]></FromUserName>
+	<CreateTime>1680000000</CreateTime>
+	<MsgType><![CDATA[event]]></MsgType>
+	<Event><![CDATA[trade_manage_order_settlement]]></Event>
+	<transaction_id><![CDATA[4200000000000000000000000000]]></transaction_id>
+	<merchant_id><![CDATA[1230000109]]></merchant_id>
+	<sub_merchant_id><![CDATA[]]></sub_merchant_id>
+	<merchant_trade_no><![CDATA[20230101000001]]></merchant_trade_no>
+	<pay_time>1680000000</pay_time>
+	<shipped_time>1680000000</shipped_time>
+	<estimated_settlement_time>1680000000</estimated_settlement_time>
+	<confirm_receive_method>1</confirm_receive_method>
+	<confirm_receive_time>1680000000</confirm_receive_time>
+	<settlement_time>1680000000</settlement_time>
+</xml>
+*/
+type EventTradeManageOrderSettlement struct {
+	Event
+	TransactionID           string `xml:"transaction_id"`            // 支付订单号
+	MerchantID              string `xml:"merchant_id"`               // 商户号
+	SubMerchantID           string `xml:"sub_merchant_id"`           // 子商户号
+	MerchantTradeNo         string `xml:"merchant_trade_no"`         // 商户订单号
+	PayTime                 int64  `xml:"pay_time"`                  // 支付成功时间，秒级时间戳
+	ShippedTime             int64  `xml:"shipped_time"`              // 发货时间，秒级时间戳
+	EstimatedSettlementTime int64  `xml:"estimated_settlement_time"` // 预计结算时间，秒级时间戳。发货时推送才有该字段
+	ConfirmReceiveMethod    int    `xml:"confirm_receive_method"`    // 确认收货方式：1. 手动确认收货；2. 自动确认收货。发生确认收货时推送才有该字段
+	ConfirmReceiveTime      int64  `xml:"confirm_receive_time"`      // 确认收货时间，秒级时间戳。发生确认收货时推送才有该字段
+	SettlementTime          int64  `xml:"settlement_time"`           // 订单结算时间，秒级时间戳。订单结算时推送才有该字段
+}
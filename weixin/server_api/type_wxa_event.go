@@ -109,3 +109,54 @@ type EventWxaCategoryAudit struct {
 	First  string `xml:"first"`
 	Second string `xml:"second"`
 }
+
+const (
+	EventTypeWxaMediaCheck = "wxa_media_check" // 音视频内容安全识别结果推送
+)
+
+// 音视频内容安全识别结果推送
+// https://developers.weixin.qq.com/miniprogram/dev/OpenApiDoc/sec-center/sec-check/mediaCheckAsync.html
+/*
+<xml>
+	<ToUserName><![CDATA[gh_38cc49f9733b]]></ToUserName>
+	<FromUserName><![CDATA[oH1fu0FdHqpToe2T6gBj0WyB8iS1]]></FromUserName>
+	<CreateTime>1626959646</CreateTime>
+	<MsgType><![CDATA[event]]></MsgType>
+	<Event><![CDATA[wxa_media_check]]></Event>
+	<appid><![CDATA[wx8f16a5e5b8a4da04]]></appid>
+	<trace_id><![CDATA[60f96f1d-3845297a-1976a3ae]]></trace_id>
+	<version>2</version>
+	<detail>
+		<strategy><![CDATA[content_model]]></strategy>
+		<errcode>0</errcode>
+		<suggest><![CDATA[pass]]></suggest>
+		<label>100</label>
+		<prob>90</prob>
+	</detail>
+	<errcode>0</errcode>
+	<errmsg><![CDATA[ok]]></errmsg>
+	<result>
+		<suggest><![CDATA[pass]]></suggest>
+		<label>100</label>
+	</result>
+</xml>
+*/
+type EventWxaMediaCheck struct {
+	Event
+	Appid   string `xml:"appid"`    // 小程序的appid
+	TraceID string `xml:"trace_id"` // 任务id, 与 mediaCheckAsync 返回的 trace_id 对应
+	Version int    `xml:"version"`  // 可用于区分接口版本
+	Detail  []struct {
+		Strategy string `xml:"strategy"` // 策略类型
+		ErrCode  int    `xml:"errcode"`  // 错误码，仅当该值为0时，该项结果有效
+		Suggest  string `xml:"suggest"`  // 建议，有risky、pass、review三种值
+		Label    int    `xml:"label"`    // 命中标签枚举值，100 正常；20001 时政；20002 色情；20006 违法犯罪；21000 其他
+		Prob     int    `xml:"prob"`     // 0-100，代表置信度，越高代表越有可能属于当前返回的标签（label）
+	} `xml:"detail"` // 详细检测结果
+	ErrCode int    `xml:"errcode"` // 错误码，仅当该值为0时，该项结果有效
+	ErrMsg  string `xml:"errmsg"`
+	Result  struct {
+		Suggest string `xml:"suggest"` // 建议，有risky、pass、review三种值
+		Label   int    `xml:"label"`   // 命中标签枚举值
+	} `xml:"result"` // 综合结果
+}
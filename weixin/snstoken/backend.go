@@ -0,0 +1,217 @@
+package snstoken
+
+import (
+	"context"
+
+	"github.com/lixinio/weixin/weixin/official_account"
+	"github.com/lixinio/weixin/weixin/web_sso"
+	"github.com/lixinio/weixin/wxopen"
+)
+
+// UserInfo 网页授权获取的用户信息
+type UserInfo struct {
+	Openid     string   `json:"openid"`
+	Nickname   string   `json:"nickname"`
+	Sex        int64    `json:"sex"`
+	Province   string   `json:"province"`
+	City       string   `json:"city"`
+	Country    string   `json:"country"`
+	Headimgurl string   `json:"headimgurl"`
+	Privilege  []string `json:"privilege"`
+	Unionid    string   `json:"unionid"`
+}
+
+// Backend 网页授权接口
+type Backend interface {
+	AppID() string
+	GetSnsAccessToken(ctx context.Context, code string) (*Token, error)
+	RefreshSnsToken(ctx context.Context, refreshToken string) (*Token, error)
+	Auth(ctx context.Context, accessToken, openid string) error
+	GetUserInfo(ctx context.Context, accessToken, openid, lang string) (*UserInfo, error)
+}
+
+type officialAccountBackend struct {
+	officialAccount *official_account.OfficialAccount
+}
+
+// 公众号网页授权
+func OfficialAccountBackend(officialAccount *official_account.OfficialAccount) Backend {
+	return &officialAccountBackend{officialAccount: officialAccount}
+}
+
+func (b *officialAccountBackend) AppID() string {
+	return b.officialAccount.Config.Appid
+}
+
+func (b *officialAccountBackend) GetSnsAccessToken(
+	ctx context.Context, code string,
+) (*Token, error) {
+	result, err := b.officialAccount.GetSnsAccessToken(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	return NewToken(
+		result.AccessToken, result.RefreshToken, result.Openid,
+		result.Unionid, result.Scope, result.ExpiresIn,
+	), nil
+}
+
+func (b *officialAccountBackend) RefreshSnsToken(
+	ctx context.Context, refreshToken string,
+) (*Token, error) {
+	result, err := b.officialAccount.RefreshSnsToken(ctx, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	return NewToken(
+		result.AccessToken, result.RefreshToken, result.Openid,
+		result.Unionid, result.Scope, result.ExpiresIn,
+	), nil
+}
+
+func (b *officialAccountBackend) Auth(ctx context.Context, accessToken, openid string) error {
+	return b.officialAccount.Auth(ctx, accessToken, openid)
+}
+
+func (b *officialAccountBackend) GetUserInfo(
+	ctx context.Context, accessToken, openid, lang string,
+) (*UserInfo, error) {
+	result, err := b.officialAccount.GetUserInfo(ctx, accessToken, openid, lang)
+	if err != nil {
+		return nil, err
+	}
+	return &UserInfo{
+		Openid:     result.Openid,
+		Nickname:   result.Nickname,
+		Sex:        result.Sex,
+		Province:   result.Province,
+		City:       result.City,
+		Country:    result.Country,
+		Headimgurl: result.Headimgurl,
+		Privilege:  result.Privilege,
+		Unionid:    result.Unionid,
+	}, nil
+}
+
+type webSSOBackend struct {
+	sso *web_sso.WebSSO
+}
+
+// 网站应用扫码登录
+func WebSSOBackend(sso *web_sso.WebSSO) Backend {
+	return &webSSOBackend{sso: sso}
+}
+
+func (b *webSSOBackend) AppID() string {
+	return b.sso.Config.Appid
+}
+
+func (b *webSSOBackend) GetSnsAccessToken(ctx context.Context, code string) (*Token, error) {
+	result, err := b.sso.GetSnsAccessToken(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	return NewToken(
+		result.AccessToken, result.RefreshToken, result.Openid,
+		result.Unionid, result.Scope, result.ExpiresIn,
+	), nil
+}
+
+func (b *webSSOBackend) RefreshSnsToken(
+	ctx context.Context, refreshToken string,
+) (*Token, error) {
+	result, err := b.sso.RefreshSnsToken(ctx, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	return NewToken(
+		result.AccessToken, result.RefreshToken, result.Openid,
+		result.Unionid, result.Scope, result.ExpiresIn,
+	), nil
+}
+
+func (b *webSSOBackend) Auth(ctx context.Context, accessToken, openid string) error {
+	return b.sso.Auth(ctx, accessToken, openid)
+}
+
+func (b *webSSOBackend) GetUserInfo(
+	ctx context.Context, accessToken, openid, lang string,
+) (*UserInfo, error) {
+	result, err := b.sso.GetUserInfo(ctx, accessToken, openid, lang)
+	if err != nil {
+		return nil, err
+	}
+	return &UserInfo{
+		Openid:     result.Openid,
+		Nickname:   result.Nickname,
+		Sex:        result.Sex,
+		Province:   result.Province,
+		City:       result.City,
+		Country:    result.Country,
+		Headimgurl: result.Headimgurl,
+		Privilege:  result.Privilege,
+		Unionid:    result.Unionid,
+	}, nil
+}
+
+type wxopenBackend struct {
+	wxopen          *wxopen.WxOpen
+	authorizerAppID string
+}
+
+// 第三方平台代公众号网页授权
+func WxOpenBackend(wxopen *wxopen.WxOpen, authorizerAppID string) Backend {
+	return &wxopenBackend{wxopen: wxopen, authorizerAppID: authorizerAppID}
+}
+
+func (b *wxopenBackend) AppID() string {
+	return b.authorizerAppID
+}
+
+func (b *wxopenBackend) GetSnsAccessToken(ctx context.Context, code string) (*Token, error) {
+	result, err := b.wxopen.GetSnsAccessToken(ctx, b.authorizerAppID, code)
+	if err != nil {
+		return nil, err
+	}
+	return NewToken(
+		result.AccessToken, result.RefreshToken, result.Openid,
+		result.Unionid, result.Scope, result.ExpiresIn,
+	), nil
+}
+
+func (b *wxopenBackend) RefreshSnsToken(
+	ctx context.Context, refreshToken string,
+) (*Token, error) {
+	result, err := b.wxopen.RefreshSnsToken(ctx, b.authorizerAppID, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	return NewToken(
+		result.AccessToken, result.RefreshToken, result.Openid,
+		result.Unionid, result.Scope, result.ExpiresIn,
+	), nil
+}
+
+func (b *wxopenBackend) Auth(ctx context.Context, accessToken, openid string) error {
+	return b.wxopen.Auth(ctx, accessToken, openid)
+}
+
+func (b *wxopenBackend) GetUserInfo(
+	ctx context.Context, accessToken, openid, lang string,
+) (*UserInfo, error) {
+	result, err := b.wxopen.GetUserInfo(ctx, accessToken, openid, lang)
+	if err != nil {
+		return nil, err
+	}
+	return &UserInfo{
+		Openid:     result.Openid,
+		Nickname:   result.Nickname,
+		Sex:        result.Sex,
+		Province:   result.Province,
+		City:       result.City,
+		Country:    result.Country,
+		Headimgurl: result.Headimgurl,
+		Privilege:  result.Privilege,
+		Unionid:    result.Unionid,
+	}, nil
+}
@@ -0,0 +1,144 @@
+package snstoken
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/lixinio/weixin/utils"
+)
+
+// 需要刷新 access_token 的错误码
+var accessTokenErrCodes = map[int64]bool{
+	40001: true, // access_token 无效
+	42001: true, // access_token 超时
+}
+
+// refresh_token 失效的错误码, 需要重新授权
+var refreshTokenErrCodes = map[int64]bool{
+	40030: true, // 不合法的 refresh_token
+	42002: true, // refresh_token 超时
+	42007: true, // 用户修改微信密码, access_token 和 refresh_token 失效
+}
+
+func weixinErrCode(err error) int64 {
+	var we *utils.WeixinError
+	if errors.As(err, &we) {
+		return we.ErrCode
+	}
+	return 0
+}
+
+// Manager 管理用户的网页授权凭证
+type Manager struct {
+	backend Backend
+	storage Storage
+}
+
+func New(backend Backend, storage Storage) *Manager {
+	return &Manager{backend: backend, storage: storage}
+}
+
+func NewWithCache(backend Backend, cache utils.Cache) *Manager {
+	return New(backend, NewCacheStorage(cache, ""))
+}
+
+// Exchange 通过授权 code 换取 access_token 并保存
+func (m *Manager) Exchange(ctx context.Context, code string) (*Token, error) {
+	token, err := m.backend.GetSnsAccessToken(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if err = m.storage.Save(ctx, m.backend.AppID(), token); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// Save 保存已经获得的 access_token (比如 oauthflow 回调获得)
+func (m *Manager) Save(ctx context.Context, token *Token) error {
+	return m.storage.Save(ctx, m.backend.AppID(), token)
+}
+
+// Delete 删除用户的授权凭证
+func (m *Manager) Delete(ctx context.Context, openid string) error {
+	return m.storage.Delete(ctx, m.backend.AppID(), openid)
+}
+
+// GetToken 获取有效的 access_token, 即将过期时通过 refresh_token 刷新
+func (m *Manager) GetToken(ctx context.Context, openid string) (*Token, error) {
+	token, err := m.storage.Load(ctx, m.backend.AppID(), openid)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if token.refreshTokenExpired(now) {
+		_ = m.Delete(ctx, openid)
+		return nil, ErrTokenExpired
+	}
+	if token.accessTokenExpired(now) {
+		return m.refresh(ctx, token)
+	}
+	return token, nil
+}
+
+// Validate 通过 /sns/auth 校验 access_token 是否有效, 失效时自动刷新
+func (m *Manager) Validate(ctx context.Context, openid string) (*Token, error) {
+	token, err := m.GetToken(ctx, openid)
+	if err != nil {
+		return nil, err
+	}
+	if err = m.backend.Auth(ctx, token.AccessToken, token.Openid); err != nil {
+		if accessTokenErrCodes[weixinErrCode(err)] || errors.Is(err, utils.ErrorAccessToken) {
+			return m.refresh(ctx, token)
+		}
+		return nil, err
+	}
+	return token, nil
+}
+
+// GetUserInfo 获取用户信息 (需授权作用域为 snsapi_userinfo), access_token 失效时自动刷新重试
+func (m *Manager) GetUserInfo(ctx context.Context, openid string) (*UserInfo, error) {
+	token, err := m.GetToken(ctx, openid)
+	if err != nil {
+		return nil, err
+	}
+
+	userInfo, err := m.backend.GetUserInfo(ctx, token.AccessToken, token.Openid, "zh_CN")
+	if err == nil {
+		return userInfo, nil
+	}
+	if !accessTokenErrCodes[weixinErrCode(err)] && !errors.Is(err, utils.ErrorAccessToken) {
+		return nil, err
+	}
+
+	if token, err = m.refresh(ctx, token); err != nil {
+		return nil, err
+	}
+	return m.backend.GetUserInfo(ctx, token.AccessToken, token.Openid, "zh_CN")
+}
+
+func (m *Manager) refresh(ctx context.Context, token *Token) (*Token, error) {
+	newToken, err := m.backend.RefreshSnsToken(ctx, token.RefreshToken)
+	if err != nil {
+		if refreshTokenErrCodes[weixinErrCode(err)] {
+			_ = m.Delete(ctx, token.Openid)
+			return nil, ErrTokenExpired
+		}
+		return nil, err
+	}
+
+	// 刷新不会延长 refresh_token 的有效期
+	newToken.RefreshExpiresAt = token.RefreshExpiresAt
+	if newToken.Openid == "" {
+		newToken.Openid = token.Openid
+	}
+	if newToken.Unionid == "" {
+		newToken.Unionid = token.Unionid
+	}
+	if err = m.storage.Save(ctx, m.backend.AppID(), newToken); err != nil {
+		return nil, err
+	}
+	return newToken, nil
+}
@@ -0,0 +1,181 @@
+package snstoken
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/lixinio/weixin/utils"
+	"github.com/lixinio/weixin/utils/memcache"
+	"github.com/lixinio/weixin/weixin/official_account"
+	"github.com/lixinio/weixin/weixin/web_sso"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeBackend struct {
+	refreshCount int
+	validToken   string
+	refreshErr   error
+}
+
+func (b *fakeBackend) AppID() string {
+	return "appid"
+}
+
+func (b *fakeBackend) GetSnsAccessToken(ctx context.Context, code string) (*Token, error) {
+	b.validToken = "token_" + code
+	return NewToken(b.validToken, "refresh_"+code, "openid", "unionid", "snsapi_userinfo", 7200), nil
+}
+
+func (b *fakeBackend) RefreshSnsToken(ctx context.Context, refreshToken string) (*Token, error) {
+	if b.refreshErr != nil {
+		return nil, b.refreshErr
+	}
+	b.refreshCount++
+	b.validToken = fmt.Sprintf("token_refreshed_%d", b.refreshCount)
+	return NewToken(b.validToken, refreshToken, "openid", "", "snsapi_userinfo", 7200), nil
+}
+
+func (b *fakeBackend) Auth(ctx context.Context, accessToken, openid string) error {
+	if accessToken != b.validToken {
+		return &utils.WeixinError{ErrCode: 40001, ErrMsg: "invalid credential"}
+	}
+	return nil
+}
+
+func (b *fakeBackend) GetUserInfo(
+	ctx context.Context, accessToken, openid, lang string,
+) (*UserInfo, error) {
+	if err := b.Auth(ctx, accessToken, openid); err != nil {
+		return nil, err
+	}
+	return &UserInfo{Openid: openid, Nickname: accessToken}, nil
+}
+
+func TestManager(t *testing.T) {
+	ctx := context.Background()
+	backend := &fakeBackend{}
+	manager := NewWithCache(backend, memcache.NewMemcache())
+
+	_, err := manager.GetToken(ctx, "openid")
+	require.Equal(t, ErrTokenNotFound, err)
+
+	token, err := manager.Exchange(ctx, "code")
+	require.Equal(t, nil, err)
+	require.Equal(t, "token_code", token.AccessToken)
+
+	userInfo, err := manager.GetUserInfo(ctx, "openid")
+	require.Equal(t, nil, err)
+	require.Equal(t, "token_code", userInfo.Nickname)
+	require.Equal(t, 0, backend.refreshCount)
+
+	// access_token 被微信提前作废, 自动刷新重试
+	backend.validToken = "other"
+	userInfo, err = manager.GetUserInfo(ctx, "openid")
+	require.Equal(t, nil, err)
+	require.Equal(t, "token_refreshed_1", userInfo.Nickname)
+
+	// Auth 校验失败自动刷新
+	backend.validToken = "other"
+	token, err = manager.Validate(ctx, "openid")
+	require.Equal(t, nil, err)
+	require.Equal(t, "token_refreshed_2", token.AccessToken)
+	require.Equal(t, "unionid", token.Unionid)
+
+	// access_token 过期, 获取时刷新
+	token.ExpiresAt = time.Now().Unix()
+	require.Equal(t, nil, manager.Save(ctx, token))
+	token, err = manager.GetToken(ctx, "openid")
+	require.Equal(t, nil, err)
+	require.Equal(t, "token_refreshed_3", token.AccessToken)
+
+	// refresh_token 失效, 删除凭证
+	token.ExpiresAt = time.Now().Unix()
+	require.Equal(t, nil, manager.Save(ctx, token))
+	backend.refreshErr = &utils.WeixinError{ErrCode: 42002, ErrMsg: "refresh_token timeout"}
+	_, err = manager.GetToken(ctx, "openid")
+	require.True(t, errors.Is(err, ErrTokenExpired))
+	_, err = manager.GetToken(ctx, "openid")
+	require.Equal(t, ErrTokenNotFound, err)
+}
+
+// 模拟微信网页授权接口, 只有最近一次下发的 access_token 有效
+func newSnsServer(t *testing.T) *httptest.Server {
+	validToken := ""
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		query := r.URL.Query()
+		switch r.URL.Path {
+		case "/sns/oauth2/access_token":
+			validToken = "token_" + query.Get("code")
+			fmt.Fprintf(w, `{"access_token":"%s","expires_in":7200,"refresh_token":"refresh",`+
+				`"openid":"openid","scope":"snsapi_userinfo","unionid":"unionid"}`, validToken)
+		case "/sns/oauth2/refresh_token":
+			validToken = "token_refreshed"
+			fmt.Fprintf(w, `{"access_token":"%s","expires_in":7200,"refresh_token":"refresh",`+
+				`"openid":"openid","scope":"snsapi_userinfo"}`, validToken)
+		case "/sns/auth":
+			if query.Get("access_token") == validToken {
+				fmt.Fprint(w, `{"errcode":0,"errmsg":"ok"}`)
+			} else {
+				fmt.Fprint(w, `{"errcode":40001,"errmsg":"invalid credential"}`)
+			}
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+}
+
+func testBackendValidate(t *testing.T, backend Backend, invalidate func()) {
+	ctx := context.Background()
+	manager := NewWithCache(backend, memcache.NewMemcache())
+
+	token, err := manager.Exchange(ctx, "code")
+	require.Equal(t, nil, err)
+	require.Equal(t, "unionid", token.Unionid)
+
+	token, err = manager.Validate(ctx, "openid")
+	require.Equal(t, nil, err)
+	require.Equal(t, "token_code", token.AccessToken)
+
+	// 校验失败自动刷新, unionid 保留
+	invalidate()
+	token, err = manager.Validate(ctx, "openid")
+	require.Equal(t, nil, err)
+	require.Equal(t, "token_refreshed", token.AccessToken)
+	require.Equal(t, "unionid", token.Unionid)
+}
+
+func TestOfficialAccountBackend(t *testing.T) {
+	server := newSnsServer(t)
+	defer server.Close()
+
+	cache := memcache.NewMemcache()
+	officialAccount := official_account.New(cache, cache, &official_account.Config{
+		Appid: "appid", Secret: "secret",
+	})
+	officialAccount.Client = utils.NewClient(server.URL, utils.EmptyClientAccessTokenGetter(0))
+
+	testBackendValidate(t, OfficialAccountBackend(officialAccount), func() {
+		// 刷新一次, 之前的 access_token 失效
+		_, err := officialAccount.RefreshSnsToken(context.Background(), "refresh")
+		require.Equal(t, nil, err)
+	})
+}
+
+func TestWebSSOBackend(t *testing.T) {
+	server := newSnsServer(t)
+	defer server.Close()
+
+	sso := web_sso.New(&web_sso.Config{Appid: "appid", Secret: "secret"})
+	sso.Client = utils.NewClient(server.URL, utils.EmptyClientAccessTokenGetter(0))
+
+	testBackendValidate(t, WebSSOBackend(sso), func() {
+		_, err := sso.RefreshSnsToken(context.Background(), "refresh")
+		require.Equal(t, nil, err)
+	})
+}
@@ -0,0 +1,105 @@
+package snstoken
+
+// Package snstoken 网页授权 access_token 的持久化和自动刷新
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lixinio/weixin/utils"
+)
+
+const (
+	refreshTokenTTL  = 30 * 24 * time.Hour // refresh_token 有效期 30 天
+	expireAdvance    = 5 * time.Minute     // 提前刷新 access_token
+	defaultKeyPrefix = "weixin:sns_token:"
+)
+
+var (
+	ErrTokenNotFound = errors.New("sns token not found")
+	// refresh_token 失效, 需要用户重新授权
+	ErrTokenExpired = errors.New("sns token expired, need authorize again")
+)
+
+// Token 用户网页授权凭证
+type Token struct {
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"`
+	Openid           string `json:"openid"`
+	Unionid          string `json:"unionid,omitempty"`
+	Scope            string `json:"scope"`
+	ExpiresAt        int64  `json:"expires_at"`         // access_token 过期时间
+	RefreshExpiresAt int64  `json:"refresh_expires_at"` // refresh_token 过期时间
+}
+
+// 通过接口返回的 access_token 构造, refresh_token 的有效期从当前时间开始计算
+func NewToken(accessToken, refreshToken, openid, unionid, scope string, expiresIn int) *Token {
+	now := time.Now()
+	return &Token{
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		Openid:           openid,
+		Unionid:          unionid,
+		Scope:            scope,
+		ExpiresAt:        now.Add(time.Duration(expiresIn) * time.Second).Unix(),
+		RefreshExpiresAt: now.Add(refreshTokenTTL).Unix(),
+	}
+}
+
+func (token *Token) accessTokenExpired(now time.Time) bool {
+	return now.Add(expireAdvance).Unix() >= token.ExpiresAt
+}
+
+func (token *Token) refreshTokenExpired(now time.Time) bool {
+	return now.Unix() >= token.RefreshExpiresAt
+}
+
+// Storage 凭证存储
+type Storage interface {
+	Load(ctx context.Context, appID, openid string) (*Token, error) // 不存在返回 ErrTokenNotFound
+	Save(ctx context.Context, appID string, token *Token) error
+	Delete(ctx context.Context, appID, openid string) error
+}
+
+type cacheStorage struct {
+	cache     utils.Cache
+	keyPrefix string
+}
+
+// 基于 utils.Cache 的存储, 过期时间与 refresh_token 一致
+func NewCacheStorage(cache utils.Cache, keyPrefix string) Storage {
+	if keyPrefix == "" {
+		keyPrefix = defaultKeyPrefix
+	}
+	return &cacheStorage{cache: cache, keyPrefix: keyPrefix}
+}
+
+func (s *cacheStorage) key(appID, openid string) string {
+	return fmt.Sprintf("%s%s:%s", s.keyPrefix, appID, openid)
+}
+
+func (s *cacheStorage) Load(ctx context.Context, appID, openid string) (*Token, error) {
+	token := &Token{}
+	exist, err := utils.CacheGetJSON(s.cache, s.key(appID, openid), token)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, ErrTokenNotFound
+	}
+	return token, nil
+}
+
+func (s *cacheStorage) Save(ctx context.Context, appID string, token *Token) error {
+	ttl := time.Until(time.Unix(token.RefreshExpiresAt, 0))
+	if ttl <= 0 {
+		return s.Delete(ctx, appID, token.Openid)
+	}
+	return utils.CacheSetJSON(s.cache, s.key(appID, token.Openid), token, ttl)
+}
+
+func (s *cacheStorage) Delete(ctx context.Context, appID, openid string) error {
+	return s.cache.Delete(s.key(appID, openid))
+}
@@ -0,0 +1,266 @@
+package user_api
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+const (
+	maxBatchGetUserInfo = 100 // 批量获取用户信息 每次最多100个
+	defaultConcurrency  = 4
+)
+
+type openIDPageFetcher func(ctx context.Context, nextOpenID string) ([]string, string, error)
+
+// OpenIDIterator openid 列表迭代器, 自动翻页
+type OpenIDIterator struct {
+	fetch      openIDPageFetcher
+	nextOpenID string
+	checkpoint string
+	openIDs    []string
+	done       bool
+}
+
+func newOpenIDIterator(fetch openIDPageFetcher, checkpoint string) *OpenIDIterator {
+	return &OpenIDIterator{
+		fetch:      fetch,
+		nextOpenID: checkpoint,
+		checkpoint: checkpoint,
+	}
+}
+
+// 遍历关注者列表, checkpoint 为上次遍历到的 openid, 为空从头开始
+func (api *UserApi) NewFollowerIterator(checkpoint string) *OpenIDIterator {
+	return newOpenIDIterator(func(ctx context.Context, nextOpenID string) ([]string, string, error) {
+		result, err := api.Get(ctx, nextOpenID)
+		if err != nil {
+			return nil, "", err
+		}
+		return result.Data.OpenIDs, result.NextOpenID, nil
+	}, checkpoint)
+}
+
+// 遍历黑名单列表
+func (api *UserApi) NewBlackListIterator(checkpoint string) *OpenIDIterator {
+	return newOpenIDIterator(func(ctx context.Context, nextOpenID string) ([]string, string, error) {
+		result, err := api.GetBlackList(ctx, nextOpenID)
+		if err != nil {
+			return nil, "", err
+		}
+		return result.Data.OpenIDs, result.NextOpenID, nil
+	}, checkpoint)
+}
+
+// 遍历标签下粉丝列表
+func (api *UserApi) NewTagUserIterator(tagID int, checkpoint string) *OpenIDIterator {
+	return newOpenIDIterator(func(ctx context.Context, nextOpenID string) ([]string, string, error) {
+		result, err := api.GetUsersByTag(ctx, tagID, nextOpenID)
+		if err != nil {
+			return nil, "", err
+		}
+		return result.Data.OpenIDs, result.NextOpenID, nil
+	}, checkpoint)
+}
+
+func (it *OpenIDIterator) fill(ctx context.Context) error {
+	if len(it.openIDs) > 0 {
+		return nil
+	}
+	if it.done {
+		return io.EOF
+	}
+
+	openIDs, nextOpenID, err := it.fetch(ctx, it.nextOpenID)
+	if err != nil {
+		return err
+	}
+	// 最后一页之后返回空列表, next_openid 为空也说明没有下一页
+	if len(openIDs) == 0 {
+		it.done = true
+		return io.EOF
+	}
+	if nextOpenID == "" || nextOpenID == it.nextOpenID {
+		it.done = true
+	}
+	it.nextOpenID = nextOpenID
+	it.openIDs = openIDs
+	return nil
+}
+
+// Next 返回下一个 openid, 遍历完毕返回 io.EOF
+func (it *OpenIDIterator) Next(ctx context.Context) (string, error) {
+	if err := it.fill(ctx); err != nil {
+		return "", err
+	}
+	openID := it.openIDs[0]
+	it.openIDs = it.openIDs[1:]
+	it.checkpoint = openID
+	return openID, nil
+}
+
+// NextPage 返回剩余的当前页(最多10000个), 遍历完毕返回 io.EOF
+func (it *OpenIDIterator) NextPage(ctx context.Context) ([]string, error) {
+	if err := it.fill(ctx); err != nil {
+		return nil, err
+	}
+	openIDs := it.openIDs
+	it.openIDs = nil
+	it.checkpoint = openIDs[len(openIDs)-1]
+	return openIDs, nil
+}
+
+// Checkpoint 最后返回的 openid, 用于中断之后继续遍历
+func (it *OpenIDIterator) Checkpoint() string {
+	return it.checkpoint
+}
+
+type UserIteratorOptions struct {
+	Lang        string // 国家地区语言版本，zh_CN 简体，zh_TW 繁体，en 英语，默认为zh-CN
+	Concurrency int    // 并发请求 BatchGetUserInfo 的数量, 默认4
+	RateLimit   int    // 每秒最多请求 BatchGetUserInfo 的次数, 0 不限制
+}
+
+// UserIterator 遍历关注者详细信息
+// 每页 openid 按照100个一组并发调用 BatchGetUserInfo, 返回顺序与关注者列表一致
+// 出错之后应使用 Checkpoint 重新创建迭代器继续遍历
+type UserIterator struct {
+	openIDs    *OpenIDIterator
+	batchGet   func(ctx context.Context, openIDs []string) ([]User, error)
+	options    UserIteratorOptions
+	users      []User
+	checkpoint string
+}
+
+// 遍历所有关注者的详细信息, checkpoint 为上次遍历到的 openid, 为空从头开始
+func (api *UserApi) NewUserIterator(checkpoint string, options *UserIteratorOptions) *UserIterator {
+	it := &UserIterator{
+		openIDs:    api.NewFollowerIterator(checkpoint),
+		checkpoint: checkpoint,
+	}
+	if options != nil {
+		it.options = *options
+	}
+	if it.options.Concurrency <= 0 {
+		it.options.Concurrency = defaultConcurrency
+	}
+	it.batchGet = func(ctx context.Context, openIDs []string) ([]User, error) {
+		param := &BatchGetUserParams{}
+		for _, openID := range openIDs {
+			param.UserList = append(param.UserList, struct {
+				OpenID string `json:"openid"`
+				Lang   string `json:"lang"`
+			}{openID, it.options.Lang})
+		}
+		result, err := api.BatchGetUserInfo(ctx, param)
+		if err != nil {
+			return nil, err
+		}
+		return result.UserInfoList, nil
+	}
+	return it
+}
+
+func chunkStrings(items []string, size int) [][]string {
+	chunks := [][]string{}
+	for len(items) > size {
+		chunks = append(chunks, items[:size])
+		items = items[size:]
+	}
+	if len(items) > 0 {
+		chunks = append(chunks, items)
+	}
+	return chunks
+}
+
+// 并发获取一页用户的详细信息
+func (it *UserIterator) fetchPage(ctx context.Context, openIDs []string) ([]User, error) {
+	chunks := chunkStrings(openIDs, maxBatchGetUserInfo)
+	results := make([][]User, len(chunks))
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var ticker *time.Ticker
+	if it.options.RateLimit > 0 {
+		ticker = time.NewTicker(time.Second / time.Duration(it.options.RateLimit))
+		defer ticker.Stop()
+	}
+
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+	tasks := make(chan int)
+	for i := 0; i < it.options.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range tasks {
+				users, err := it.batchGet(ctx, chunks[index])
+				if err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					continue
+				}
+				results[index] = users
+			}
+		}()
+	}
+
+dispatch:
+	for index := range chunks {
+		if ticker != nil {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				break dispatch
+			}
+		}
+		select {
+		case tasks <- index:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(tasks)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	users := make([]User, 0, len(openIDs))
+	for _, result := range results {
+		users = append(users, result...)
+	}
+	return users, nil
+}
+
+// Next 返回下一个用户, 遍历完毕返回 io.EOF
+func (it *UserIterator) Next(ctx context.Context) (*User, error) {
+	for len(it.users) == 0 {
+		openIDs, err := it.openIDs.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if it.users, err = it.fetchPage(ctx, openIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	user := it.users[0]
+	it.users = it.users[1:]
+	it.checkpoint = user.OpenID
+	return &user, nil
+}
+
+// Checkpoint 最后返回的用户 openid, 用于中断之后继续遍历
+func (it *UserIterator) Checkpoint() string {
+	return it.checkpoint
+}
@@ -0,0 +1,126 @@
+package user_api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// 模拟关注者列表, 每页 pageSize 个
+func fakeFollowers(total, pageSize int) openIDPageFetcher {
+	openIDs := make([]string, total)
+	for i := range openIDs {
+		openIDs[i] = fmt.Sprintf("openid_%05d", i)
+	}
+
+	return func(ctx context.Context, nextOpenID string) ([]string, string, error) {
+		begin := 0
+		if nextOpenID != "" {
+			for i, openID := range openIDs {
+				if openID == nextOpenID {
+					begin = i + 1
+				}
+			}
+		}
+		end := begin + pageSize
+		if end > total {
+			end = total
+		}
+		page := openIDs[begin:end]
+		if len(page) == 0 {
+			return nil, "", nil
+		}
+		return page, page[len(page)-1], nil
+	}
+}
+
+func TestOpenIDIterator(t *testing.T) {
+	ctx := context.Background()
+
+	it := newOpenIDIterator(fakeFollowers(25, 10), "")
+	count := 0
+	for {
+		openID, err := it.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		require.Equal(t, nil, err)
+		require.Equal(t, fmt.Sprintf("openid_%05d", count), openID)
+		count++
+	}
+	require.Equal(t, 25, count)
+	require.Equal(t, "openid_00024", it.Checkpoint())
+
+	// 从断点继续
+	it = newOpenIDIterator(fakeFollowers(25, 10), "openid_00004")
+	page, err := it.NextPage(ctx)
+	require.Equal(t, nil, err)
+	require.Equal(t, 10, len(page))
+	require.Equal(t, "openid_00005", page[0])
+	page, err = it.NextPage(ctx)
+	require.Equal(t, nil, err)
+	require.Equal(t, 10, len(page))
+	require.Equal(t, "openid_00024", page[9])
+	require.Equal(t, "openid_00024", it.Checkpoint())
+	_, err = it.NextPage(ctx)
+	require.Equal(t, io.EOF, err)
+}
+
+func TestUserIterator(t *testing.T) {
+	ctx := context.Background()
+	var calls int32
+
+	newIterator := func(checkpoint string, fail bool) *UserIterator {
+		return &UserIterator{
+			openIDs: newOpenIDIterator(fakeFollowers(2345, 1000), checkpoint),
+			options: UserIteratorOptions{Concurrency: 3, RateLimit: 1000},
+			batchGet: func(ctx context.Context, openIDs []string) ([]User, error) {
+				atomic.AddInt32(&calls, 1)
+				require.LessOrEqual(t, len(openIDs), maxBatchGetUserInfo)
+				if fail && openIDs[0] == "openid_01500" {
+					return nil, errors.New("batchget fail")
+				}
+				users := make([]User, len(openIDs))
+				for i, openID := range openIDs {
+					users[i].OpenID = openID
+				}
+				return users, nil
+			},
+		}
+	}
+
+	// 第二页出错
+	it := newIterator("", true)
+	count := 0
+	for {
+		user, err := it.Next(ctx)
+		if err != nil {
+			require.Equal(t, "batchget fail", err.Error())
+			break
+		}
+		require.Equal(t, fmt.Sprintf("openid_%05d", count), user.OpenID)
+		count++
+	}
+	require.Equal(t, 1000, count)
+	require.Equal(t, "openid_00999", it.Checkpoint())
+
+	// 从断点继续, 顺序与列表一致
+	atomic.StoreInt32(&calls, 0)
+	it = newIterator(it.Checkpoint(), false)
+	for {
+		user, err := it.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		require.Equal(t, nil, err)
+		require.Equal(t, fmt.Sprintf("openid_%05d", count), user.OpenID)
+		count++
+	}
+	require.Equal(t, 2345, count)
+	require.Equal(t, int32(14), atomic.LoadInt32(&calls))
+}
@@ -12,6 +12,7 @@ const (
 	apiAccessToken  = "/sns/oauth2/access_token"
 	apiRefreshToken = "/sns/oauth2/refresh_token"
 	apiUserInfo     = "/sns/userinfo"
+	apiAuth         = "/sns/auth"
 	WXServerUrl     = "https://api.weixin.qq.com" // 微信 api 服务器地址
 )
 
@@ -140,3 +141,16 @@ func (sso *WebSSO) GetUserInfo(
 	}
 	return result, nil
 }
+
+// https://developers.weixin.qq.com/doc/oplatform/Website_App/WeChat_Login/Authorized_Interface_Calling_UnionID.html
+// 检验授权凭证（access_token）是否有效
+// https://api.weixin.qq.com/sns/auth?access_token=ACCESS_TOKEN&openid=OPENID
+func (sso *WebSSO) Auth(
+	ctx context.Context, accessToken string, openid string,
+) error {
+	// 无需 access token
+	return sso.Client.HTTPGetToken(ctx, apiAuth, func(params url.Values) {
+		params.Add("access_token", accessToken)
+		params.Add("openid", openid)
+	}, &utils.WeixinError{})
+}
@@ -0,0 +1,36 @@
+package web_sso
+
+import (
+	"context"
+
+	"github.com/lixinio/weixin/utils/oauthflow"
+)
+
+type oauthProvider struct {
+	sso *WebSSO
+}
+
+// OAuthProvider 网站应用扫码登录, 用于 oauthflow
+func (sso *WebSSO) OAuthProvider() oauthflow.Provider {
+	return &oauthProvider{sso: sso}
+}
+
+func (p *oauthProvider) AuthorizeUrl(redirectUri, state string) string {
+	return p.sso.GetAuthorizeUrl(redirectUri, state)
+}
+
+func (p *oauthProvider) Exchange(ctx context.Context, code string) (*oauthflow.Identity, error) {
+	token, err := p.sso.GetSnsAccessToken(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	return &oauthflow.Identity{
+		AppID:        p.sso.Config.Appid,
+		OpenID:       token.Openid,
+		UnionID:      token.Unionid,
+		Scope:        token.Scope,
+		AccessToken:  token.AccessToken,
+		RefreshToken: token.RefreshToken,
+		ExpiresIn:    token.ExpiresIn,
+	}, nil
+}
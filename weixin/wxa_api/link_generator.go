@@ -0,0 +1,448 @@
+package wxa_api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/lixinio/weixin/utils"
+)
+
+const (
+	linkKeyPrefix       = "weixin:wxalink:"
+	defaultLinkInterval = 30 // 默认有效期30天(最长)
+	defaultLinkQPS      = 50
+	defaultConcurrency  = 4
+)
+
+// 链接类型
+const (
+	LinkTypeUrlLink   = "urllink"
+	LinkTypeScheme    = "scheme"
+	LinkTypeShortLink = "shortlink"
+)
+
+var (
+	ErrLinkQuotaExhausted = errors.New("wxa link quota exhausted")
+	ErrLinkRateLimited    = errors.New("wxa link rate limited")
+)
+
+// LinkError 额度或者频率限制的错误, 可以使用 errors.Is 判断 ErrLinkQuotaExhausted / ErrLinkRateLimited,
+// 微信返回的错误可以通过 errors.As 获取 *utils.WeixinError
+type LinkError struct {
+	Kind        error
+	WeixinError *utils.WeixinError // 本地额度耗尽时为空
+}
+
+func (e *LinkError) Error() string {
+	if e.WeixinError == nil {
+		return e.Kind.Error()
+	}
+	return fmt.Sprintf("%s, %s", e.Kind.Error(), e.WeixinError.Error())
+}
+
+func (e *LinkError) Unwrap() error {
+	return e.Kind
+}
+
+func (e *LinkError) As(target interface{}) bool {
+	if we, ok := target.(**utils.WeixinError); ok && e.WeixinError != nil {
+		*we = e.WeixinError
+		return true
+	}
+	return false
+}
+
+// 额度相关的错误码
+var linkErrCodes = map[int64]error{
+	45009: ErrLinkQuotaExhausted, // 调用超过天级别频率限制
+	85400: ErrLinkQuotaExhausted, // 长期有效 Scheme/URL Link 达到生成上限
+	85401: ErrLinkQuotaExhausted, // 当天生成数量达到上限
+	44990: ErrLinkRateLimited,    // 生成频率过快(超过100次/秒)
+}
+
+// toLinkError 将额度相关的微信错误转换为 *LinkError, 其他错误原样返回
+func toLinkError(err error) error {
+	weixinErr := &utils.WeixinError{}
+	if errors.As(err, &weixinErr) {
+		if kind, ok := linkErrCodes[weixinErr.ErrCode]; ok {
+			return &LinkError{Kind: kind, WeixinError: weixinErr}
+		}
+	}
+	return err
+}
+
+// LinkRequest 生成链接的参数, 相同的参数会复用缓存的链接
+type LinkRequest struct {
+	Path       string
+	Query      string
+	EnvVersion string // release / trial / develop, 默认正式版
+	// 有效天数(1-30), 默认30天; Short Link 为 0 时生成短期链接, 小于 0 时生成永久链接
+	ExpireInterval int
+	PageTitle      string // 仅 Short Link 使用
+}
+
+// LinkResult 批量生成的结果, 顺序与请求一致
+type LinkResult struct {
+	Request *LinkRequest
+	Link    string
+	Cached  bool
+	Err     error
+}
+
+type LinkGeneratorConfig struct {
+	Api         *WxaApi
+	Type        string      // LinkType*, 默认 LinkTypeUrlLink
+	Cache       utils.Cache // 可选, 缓存已生成的链接和当日用量
+	Locker      utils.Lock  // 可选, 多进程共享当日用量时避免覆盖
+	DailyQuota  int         // 每日最多生成数量, 0 不限制(以微信返回为准)
+	QPS         int         // 每秒最多请求数, 默认50
+	Concurrency int         // 批量生成的并发数, 默认4
+	// 读写缓存失败时回调, 可选; 缓存失败不影响生成链接
+	OnCacheError func(err error)
+}
+
+// LinkGenerator 带缓存/限速/额度控制的 URL Link / Scheme / Short Link 生成器
+type LinkGenerator struct {
+	config  *LinkGeneratorConfig
+	limiter *rateLimiter
+
+	mutex     sync.Mutex
+	usedDate  string
+	usedCount int // 未配置 Cache 时在内存中统计
+}
+
+func NewLinkGenerator(config *LinkGeneratorConfig) *LinkGenerator {
+	if config.Api == nil {
+		panic("wxa_api: LinkGeneratorConfig.Api is required")
+	}
+	if config.Type == "" {
+		config.Type = LinkTypeUrlLink
+	}
+	if config.QPS <= 0 {
+		config.QPS = defaultLinkQPS
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = defaultConcurrency
+	}
+	return &LinkGenerator{
+		config:  config,
+		limiter: &rateLimiter{interval: time.Second / time.Duration(config.QPS)},
+	}
+}
+
+// Generate 生成单个链接, 命中缓存时不消耗额度
+func (g *LinkGenerator) Generate(ctx context.Context, req *LinkRequest) (string, error) {
+	result := g.generate(ctx, req)
+	return result.Link, result.Err
+}
+
+// GenerateBatch 并发生成链接, 相同参数的请求只生成一次;
+// 额度耗尽之后剩余的请求不再调用微信, 直接返回同样的错误
+func (g *LinkGenerator) GenerateBatch(ctx context.Context, reqs []*LinkRequest) []LinkResult {
+	results := make([]LinkResult, len(reqs))
+	var (
+		wg       sync.WaitGroup
+		mutex    sync.Mutex
+		quotaErr error
+	)
+
+	// 相同 key 的请求复用第一个请求的结果
+	firsts := make([]int, len(reqs))
+	keys := map[string]int{}
+	for idx, req := range reqs {
+		key := g.cacheKey(req)
+		if first, ok := keys[key]; ok {
+			firsts[idx] = first
+			continue
+		}
+		keys[key] = idx
+		firsts[idx] = idx
+	}
+
+	indexes := make(chan int)
+	for i := 0; i < g.config.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range indexes {
+				mutex.Lock()
+				err := quotaErr
+				mutex.Unlock()
+				if err != nil {
+					results[idx] = LinkResult{Request: reqs[idx], Err: err}
+					continue
+				}
+
+				results[idx] = g.generate(ctx, reqs[idx])
+				if errors.Is(results[idx].Err, ErrLinkQuotaExhausted) {
+					mutex.Lock()
+					quotaErr = results[idx].Err
+					mutex.Unlock()
+				}
+			}
+		}()
+	}
+	for idx := range reqs {
+		if firsts[idx] == idx {
+			indexes <- idx
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	for idx, first := range firsts {
+		if first != idx {
+			results[idx] = results[first]
+			results[idx].Request = reqs[idx]
+		}
+	}
+	return results
+}
+
+func (g *LinkGenerator) generate(ctx context.Context, req *LinkRequest) LinkResult {
+	result := LinkResult{Request: req}
+	key := g.cacheKey(req)
+	if g.config.Cache != nil {
+		var link string
+		exist, err := g.config.Cache.Get(key, &link)
+		if err != nil {
+			g.cacheError(err)
+		} else if exist && link != "" {
+			result.Link, result.Cached = link, true
+			return result
+		}
+	}
+
+	date, err := g.reserve()
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	if err = g.limiter.wait(ctx); err != nil {
+		g.release(date)
+		result.Err = err
+		return result
+	}
+
+	link, err := g.call(ctx, req)
+	if err != nil {
+		result.Err = toLinkError(err)
+		// 额度耗尽之外的失败没有生成链接, 归还占用的额度
+		if !errors.Is(result.Err, ErrLinkQuotaExhausted) {
+			g.release(date)
+		}
+		return result
+	}
+	result.Link = link
+
+	if ttl := g.cacheTTL(req); g.config.Cache != nil && ttl > 0 {
+		if err = g.config.Cache.Set(key, link, ttl); err != nil {
+			g.cacheError(err)
+		}
+	}
+	return result
+}
+
+func (g *LinkGenerator) cacheError(err error) {
+	if g.config.OnCacheError != nil {
+		g.config.OnCacheError(err)
+	}
+}
+
+// expireInterval 有效天数, 默认30天
+func expireInterval(req *LinkRequest) int {
+	if req.ExpireInterval <= 0 {
+		return defaultLinkInterval
+	}
+	return req.ExpireInterval
+}
+
+func (g *LinkGenerator) call(ctx context.Context, req *LinkRequest) (string, error) {
+	interval := expireInterval(req)
+
+	switch g.config.Type {
+	case LinkTypeScheme:
+		param := &GenerateSchemeRequest{
+			IsExpire:       true,
+			ExpireType:     ExpireTypeInterval,
+			ExpireInterval: interval,
+		}
+		param.JumpWxa = &struct {
+			Path       string `json:"path"`
+			Query      string `json:"query,omitempty"`
+			EnvVersion string `json:"env_version,omitempty"`
+		}{req.Path, req.Query, req.EnvVersion}
+		return g.config.Api.GenerateScheme(ctx, param)
+	case LinkTypeShortLink:
+		pageUrl := req.Path
+		if req.Query != "" {
+			pageUrl += "?" + req.Query
+		}
+		return g.config.Api.GenerateShortLink(ctx, &GenerateShortLinkRequest{
+			PageUrl:     pageUrl,
+			PageTitle:   req.PageTitle,
+			IsPermanent: req.ExpireInterval < 0,
+		})
+	default:
+		return g.config.Api.GenerateUrlLink(ctx, &GenerateUrlLinkRequest{
+			Path:           req.Path,
+			Query:          req.Query,
+			EnvVersion:     req.EnvVersion,
+			IsExpire:       true,
+			ExpireType:     ExpireTypeInterval,
+			ExpireInterval: interval,
+		})
+	}
+}
+
+// cacheTTL 比链接的有效期提前一天过期, 避免发出即将失效的链接
+func (g *LinkGenerator) cacheTTL(req *LinkRequest) time.Duration {
+	if g.config.Type == LinkTypeShortLink {
+		if req.ExpireInterval < 0 {
+			return defaultLinkInterval * 24 * time.Hour
+		}
+		// 短期 Short Link 有效期30天
+		return (defaultLinkInterval - 1) * 24 * time.Hour
+	}
+
+	return time.Duration(expireInterval(req)-1) * 24 * time.Hour
+}
+
+// cacheKey 有效期与 call 一样归一化, 默认值与显式的30天是同一个链接
+func (g *LinkGenerator) cacheKey(req *LinkRequest) string {
+	interval := expireInterval(req)
+	if g.config.Type == LinkTypeShortLink {
+		// Short Link 只区分短期和永久
+		interval = defaultLinkInterval
+		if req.ExpireInterval < 0 {
+			interval = -1
+		}
+	}
+	sum := sha256.Sum256([]byte(fmt.Sprintf(
+		"%s\n%s\n%s\n%s\n%d\n%s",
+		g.config.Type, req.Path, req.Query, req.EnvVersion, interval, req.PageTitle,
+	)))
+	return linkKeyPrefix + hex.EncodeToString(sum[:])
+}
+
+func (g *LinkGenerator) usedKey(date string) string {
+	return fmt.Sprintf("%s%s:used:%s", linkKeyPrefix, g.config.Type, date)
+}
+
+// reserve 占用一个当日额度, 返回占用额度的日期
+func (g *LinkGenerator) reserve() (string, error) {
+	date := time.Now().Format("20060102")
+	return date, g.updateUsed(date, func(used int) (int, error) {
+		if used >= g.config.DailyQuota {
+			return used, &LinkError{Kind: ErrLinkQuotaExhausted}
+		}
+		return used + 1, nil
+	})
+}
+
+// release 归还 reserve 占用的额度, 失败时只会少用额度, 因此忽略错误
+func (g *LinkGenerator) release(date string) {
+	g.updateUsed(date, func(used int) (int, error) {
+		if used <= 0 {
+			return used, nil
+		}
+		return used - 1, nil
+	})
+}
+
+// updateUsed 修改 date 当日已使用的额度
+func (g *LinkGenerator) updateUsed(date string, fn func(used int) (int, error)) error {
+	if g.config.DailyQuota <= 0 {
+		return nil
+	}
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if g.config.Cache == nil {
+		if g.usedDate != date {
+			if date < g.usedDate {
+				return nil // 已经跨天
+			}
+			g.usedDate, g.usedCount = date, 0
+		}
+		used, err := fn(g.usedCount)
+		if err != nil {
+			return err
+		}
+		g.usedCount = used
+		return nil
+	}
+
+	key := g.usedKey(date)
+	return utils.WithLock(g.config.Locker, key+":lock", func() error {
+		var value string
+		if _, err := g.config.Cache.Get(key, &value); err != nil {
+			return err
+		}
+		used, _ := strconv.Atoi(value)
+		next, err := fn(used)
+		if err != nil || next == used {
+			return err
+		}
+		return g.config.Cache.Set(key, strconv.Itoa(next), 25*time.Hour)
+	})
+}
+
+// Used 当日已使用的额度(仅统计本生成器调用微信的次数)
+func (g *LinkGenerator) Used() (int, error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	date := time.Now().Format("20060102")
+	if g.config.Cache == nil {
+		if g.usedDate != date {
+			return 0, nil
+		}
+		return g.usedCount, nil
+	}
+
+	var value string
+	key := g.usedKey(date)
+	if _, err := g.config.Cache.Get(key, &value); err != nil {
+		return 0, err
+	}
+	used, _ := strconv.Atoi(value)
+	return used, nil
+}
+
+// rateLimiter 按固定间隔放行请求
+type rateLimiter struct {
+	mutex    sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func (l *rateLimiter) wait(ctx context.Context) error {
+	l.mutex.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mutex.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
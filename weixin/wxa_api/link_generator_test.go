@@ -0,0 +1,259 @@
+package wxa_api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lixinio/weixin/utils"
+	"github.com/lixinio/weixin/utils/memcache"
+	"github.com/stretchr/testify/require"
+)
+
+// failingCache 读写都失败的缓存
+type failingCache struct {
+	*memcache.Memcache
+}
+
+func (c *failingCache) Get(key string, value interface{}) (bool, error) {
+	return false, errors.New("cache unavailable")
+}
+
+func (c *failingCache) Set(key string, value interface{}, timeout time.Duration) error {
+	return errors.New("cache unavailable")
+}
+
+// 模拟微信接口, 生成 limit 个链接之后返回 85401
+func newLinkServer(limit int) (*httptest.Server, *int) {
+	var mutex sync.Mutex
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		calls++
+		n := calls
+		mutex.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if n > limit {
+			w.Write([]byte(`{"errcode":85401,"errmsg":"quota limit"}`))
+			return
+		}
+
+		param := map[string]interface{}{}
+		json.NewDecoder(r.Body).Decode(&param)
+		if param["path"] == "pages/invalid" {
+			w.Write([]byte(`{"errcode":40165,"errmsg":"invalid weapp pagepath"}`))
+			return
+		}
+		switch r.URL.Path {
+		case apiGenerateUrlLink:
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"errcode": 0, "url_link": fmt.Sprintf("https://wxaurl.cn/%s?%s", param["path"], param["query"]),
+			})
+		case apiGenerateShortLink:
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"errcode": 0, "link": fmt.Sprintf("#小程序://demo/%s", param["page_url"]),
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return server, &calls
+}
+
+func TestLinkGenerator(t *testing.T) {
+	ctx := context.Background()
+	server, calls := newLinkServer(3)
+	defer server.Close()
+
+	g := NewLinkGenerator(&LinkGeneratorConfig{
+		Api:         NewApi(utils.NewClient(server.URL, utils.StaticClientAccessTokenGetter("token"))),
+		Cache:       memcache.NewMemcache(),
+		QPS:         1000,
+		Concurrency: 2,
+	})
+
+	link, err := g.Generate(ctx, &LinkRequest{Path: "pages/index", Query: "uid=1"})
+	require.Equal(t, nil, err)
+	require.Equal(t, "https://wxaurl.cn/pages/index?uid=1", link)
+
+	reqs := []*LinkRequest{}
+	for i := 1; i <= 6; i++ {
+		reqs = append(reqs, &LinkRequest{Path: "pages/index", Query: fmt.Sprintf("uid=%d", i)})
+	}
+	results := g.GenerateBatch(ctx, reqs)
+	require.Equal(t, 6, len(results))
+	require.True(t, results[0].Cached)
+
+	failed := 0
+	for i, result := range results {
+		if result.Err != nil {
+			failed++
+			require.True(t, errors.Is(result.Err, ErrLinkQuotaExhausted))
+			continue
+		}
+		require.Equal(t, fmt.Sprintf("https://wxaurl.cn/pages/index?uid=%d", i+1), result.Link)
+	}
+	require.Equal(t, 3, failed)
+	// 第一次返回 85401 之后不再调用微信, 并发的请求最多多调用一次
+	require.LessOrEqual(t, *calls, 5)
+
+	_, err = g.Generate(ctx, &LinkRequest{Path: "pages/index", Query: "uid=100"})
+	linkErr := &LinkError{}
+	require.True(t, errors.As(err, &linkErr))
+	weixinErr := &utils.WeixinError{}
+	require.True(t, errors.As(err, &weixinErr))
+	require.Equal(t, int64(85401), weixinErr.ErrCode)
+}
+
+func TestLinkGeneratorDailyQuota(t *testing.T) {
+	ctx := context.Background()
+	server, calls := newLinkServer(100)
+	defer server.Close()
+
+	for _, cache := range []utils.Cache{nil, memcache.NewMemcache()} {
+		*calls = 0
+		g := NewLinkGenerator(&LinkGeneratorConfig{
+			Api:        NewApi(utils.NewClient(server.URL, utils.StaticClientAccessTokenGetter("token"))),
+			Type:       LinkTypeShortLink,
+			Cache:      cache,
+			DailyQuota: 2,
+			QPS:        1000,
+		})
+
+		link, err := g.Generate(ctx, &LinkRequest{Path: "pages/index", Query: "uid=1", ExpireInterval: -1})
+		require.Equal(t, nil, err)
+		require.Equal(t, "#小程序://demo/pages/index?uid=1", link)
+		_, err = g.Generate(ctx, &LinkRequest{Path: "pages/index", Query: "uid=2"})
+		require.Equal(t, nil, err)
+		_, err = g.Generate(ctx, &LinkRequest{Path: "pages/index", Query: "uid=3"})
+		require.True(t, errors.Is(err, ErrLinkQuotaExhausted))
+		require.Equal(t, 2, *calls)
+
+		used, err := g.Used()
+		require.Equal(t, nil, err)
+		require.Equal(t, 2, used)
+	}
+}
+
+func TestLinkGeneratorRelease(t *testing.T) {
+	ctx := context.Background()
+	server, calls := newLinkServer(100)
+	defer server.Close()
+
+	for _, cache := range []utils.Cache{nil, memcache.NewMemcache()} {
+		*calls = 0
+		g := NewLinkGenerator(&LinkGeneratorConfig{
+			Api:        NewApi(utils.NewClient(server.URL, utils.StaticClientAccessTokenGetter("token"))),
+			Cache:      cache,
+			DailyQuota: 1,
+			QPS:        1000,
+		})
+
+		// 非额度错误归还额度
+		_, err := g.Generate(ctx, &LinkRequest{Path: "pages/invalid"})
+		weixinErr := &utils.WeixinError{}
+		require.True(t, errors.As(err, &weixinErr))
+		require.Equal(t, int64(40165), weixinErr.ErrCode)
+		require.False(t, errors.Is(err, ErrLinkQuotaExhausted))
+		used, err := g.Used()
+		require.Equal(t, nil, err)
+		require.Equal(t, 0, used)
+
+		_, err = g.Generate(ctx, &LinkRequest{Path: "pages/index"})
+		require.Equal(t, nil, err)
+		used, err = g.Used()
+		require.Equal(t, nil, err)
+		require.Equal(t, 1, used)
+		require.Equal(t, 2, *calls)
+	}
+}
+
+func TestLinkGeneratorCacheError(t *testing.T) {
+	server, calls := newLinkServer(100)
+	defer server.Close()
+
+	cacheErrors := 0
+	g := NewLinkGenerator(&LinkGeneratorConfig{
+		Api:          NewApi(utils.NewClient(server.URL, utils.StaticClientAccessTokenGetter("token"))),
+		Cache:        &failingCache{memcache.NewMemcache()},
+		QPS:          1000,
+		OnCacheError: func(err error) { cacheErrors++ },
+	})
+
+	// 读缓存失败时继续生成, 写缓存失败仍然返回链接
+	for i := 1; i <= 2; i++ {
+		link, err := g.Generate(context.Background(), &LinkRequest{Path: "pages/index", Query: "uid=1"})
+		require.Equal(t, nil, err)
+		require.Equal(t, "https://wxaurl.cn/pages/index?uid=1", link)
+		require.Equal(t, i, *calls)
+		require.Equal(t, i*2, cacheErrors)
+	}
+}
+
+func TestLinkGeneratorCacheKey(t *testing.T) {
+	ctx := context.Background()
+	server, calls := newLinkServer(100)
+	defer server.Close()
+
+	g := NewLinkGenerator(&LinkGeneratorConfig{
+		Api:   NewApi(utils.NewClient(server.URL, utils.StaticClientAccessTokenGetter("token"))),
+		Cache: memcache.NewMemcache(),
+		QPS:   1000,
+	})
+
+	// 默认有效期与显式的30天是同一个链接
+	_, err := g.Generate(ctx, &LinkRequest{Path: "pages/index"})
+	require.Equal(t, nil, err)
+	results := g.GenerateBatch(ctx, []*LinkRequest{
+		{Path: "pages/index", ExpireInterval: 30},
+		{Path: "pages/index", ExpireInterval: 7},
+	})
+	require.True(t, results[0].Cached)
+	require.False(t, results[1].Cached)
+	require.Equal(t, 2, *calls)
+}
+
+func TestLinkGeneratorBatchDedup(t *testing.T) {
+	server, calls := newLinkServer(100)
+	defer server.Close()
+
+	g := NewLinkGenerator(&LinkGeneratorConfig{
+		Api:         NewApi(utils.NewClient(server.URL, utils.StaticClientAccessTokenGetter("token"))),
+		QPS:         1000,
+		Concurrency: 4,
+	})
+
+	reqs := []*LinkRequest{}
+	for i := 0; i < 8; i++ {
+		reqs = append(reqs, &LinkRequest{Path: "pages/index", Query: fmt.Sprintf("uid=%d", i%2)})
+	}
+	results := g.GenerateBatch(context.Background(), reqs)
+	require.Equal(t, 2, *calls)
+	for i, result := range results {
+		require.Equal(t, nil, result.Err)
+		require.Equal(t, reqs[i], result.Request)
+		require.Equal(t, fmt.Sprintf("https://wxaurl.cn/pages/index?uid=%d", i%2), result.Link)
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	ctx := context.Background()
+	limiter := &rateLimiter{interval: 20 * time.Millisecond}
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		require.Equal(t, nil, limiter.wait(ctx))
+	}
+	require.GreaterOrEqual(t, int64(time.Since(start)), int64(60*time.Millisecond))
+
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	limiter.wait(ctx)
+	require.Equal(t, context.Canceled, limiter.wait(ctx))
+}
@@ -1,8 +1,9 @@
 package wxa_api
 
 import (
+	"bytes"
 	"context"
-	"io/ioutil"
+	"io"
 )
 
 const (
@@ -33,19 +34,20 @@ type GetWxaCodeUnlimitRequest struct {
 }
 
 func (api *WxaApi) GetWxaCodeUnlimit(
-	ctx context.Context, param *GetWxaCodeUnlimitRequest, // content io.Writer,
+	ctx context.Context, param *GetWxaCodeUnlimitRequest,
 ) ([]byte, error) {
-	resp, err := api.Client.HTTPPostDownload(ctx, apiGetWxaCodeUnlimit, param, nil)
-	if err != nil {
+	body := &bytes.Buffer{}
+	if _, err := api.GetWxaCodeUnlimitTo(ctx, param, body); err != nil {
 		return nil, err
 	}
+	return body.Bytes(), nil
+}
 
-	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	return body, nil
+// GetWxaCodeUnlimitTo 同 GetWxaCodeUnlimit, 图片直接写入 content, 不在内存中缓冲
+func (api *WxaApi) GetWxaCodeUnlimitTo(
+	ctx context.Context, param *GetWxaCodeUnlimitRequest, content io.Writer,
+) (int64, error) {
+	return api.download(ctx, apiGetWxaCodeUnlimit, param, content)
 }
 
 /**
@@ -69,17 +71,18 @@ type GetWxaCodeRequest struct {
 func (api *WxaApi) GetWxaCode(
 	ctx context.Context, param *GetWxaCodeRequest,
 ) ([]byte, error) {
-	resp, err := api.Client.HTTPPostDownload(ctx, apiGetWxaCode, param, nil)
-	if err != nil {
+	body := &bytes.Buffer{}
+	if _, err := api.GetWxaCodeTo(ctx, param, body); err != nil {
 		return nil, err
 	}
+	return body.Bytes(), nil
+}
 
-	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	return body, nil
+// GetWxaCodeTo 同 GetWxaCode, 图片直接写入 content, 不在内存中缓冲
+func (api *WxaApi) GetWxaCodeTo(
+	ctx context.Context, param *GetWxaCodeRequest, content io.Writer,
+) (int64, error) {
+	return api.download(ctx, apiGetWxaCode, param, content)
 }
 
 /**
@@ -90,19 +93,32 @@ https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/api/qrc
 func (api *WxaApi) CreateWxaQRCode(
 	ctx context.Context, path string, width int,
 ) ([]byte, error) {
+	body := &bytes.Buffer{}
+	if _, err := api.CreateWxaQRCodeTo(ctx, path, width, body); err != nil {
+		return nil, err
+	}
+	return body.Bytes(), nil
+}
+
+// CreateWxaQRCodeTo 同 CreateWxaQRCode, 图片直接写入 content, 不在内存中缓冲
+func (api *WxaApi) CreateWxaQRCodeTo(
+	ctx context.Context, path string, width int, content io.Writer,
+) (int64, error) {
 	param := &struct {
 		Path  string `json:"path"`
 		Width int    `json:"width,omitempty"`
 	}{path, width}
-	resp, err := api.Client.HTTPPostDownload(ctx, apiCreateWxaQrcode, param, nil)
+	return api.download(ctx, apiCreateWxaQrcode, param, content)
+}
+
+func (api *WxaApi) download(
+	ctx context.Context, path string, param interface{}, content io.Writer,
+) (int64, error) {
+	resp, err := api.Client.HTTPPostDownload(ctx, path, param, nil)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 
 	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	return body, nil
+	return io.Copy(content, resp.Body)
 }
@@ -7,10 +7,18 @@ import (
 )
 
 const (
-	apiGenerateUrlLink = "/wxa/generate_urllink"
-	apiQueryUrlLink    = "/wxa/query_urllink"
-	apiGenerateScheme  = "/wxa/generatescheme"
-	apiQueryScheme     = "/wxa/queryscheme"
+	apiGenerateUrlLink   = "/wxa/generate_urllink"
+	apiQueryUrlLink      = "/wxa/query_urllink"
+	apiGenerateScheme    = "/wxa/generatescheme"
+	apiQueryScheme       = "/wxa/queryscheme"
+	apiGenerateNFCScheme = "/wxa/generatenfcscheme"
+	apiGenerateShortLink = "/wxa/genwxashortlink"
+)
+
+// 到期失效类型
+const (
+	ExpireTypeTime     = 0 // 指定失效时间 ExpireTime
+	ExpireTypeInterval = 1 // 指定失效天数 ExpireInterval
 )
 
 type WxaApi struct {
@@ -28,11 +36,13 @@ https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/api/Bus
 */
 
 type GenerateUrlLinkRequest struct {
-	Path       string `json:"path"`
-	Query      string `json:"query,omitempty"`
-	EnvVersion string `json:"env_version,omitempty"`
-	IsExpire   bool   `json:"is_expire"`
-	ExpireTime int64  `json:"expire_time,omitempty"`
+	Path           string `json:"path"`
+	Query          string `json:"query,omitempty"`
+	EnvVersion     string `json:"env_version,omitempty"`
+	IsExpire       bool   `json:"is_expire"`
+	ExpireType     int    `json:"expire_type,omitempty"` // ExpireType*
+	ExpireTime     int64  `json:"expire_time,omitempty"`
+	ExpireInterval int    `json:"expire_interval,omitempty"` // 失效天数, 最长30天
 }
 
 func (api *WxaApi) GenerateUrlLink(
@@ -95,8 +105,10 @@ type GenerateSchemeRequest struct {
 		Query      string `json:"query,omitempty"`
 		EnvVersion string `json:"env_version,omitempty"`
 	} `json:"jump_wxa"`
-	IsExpire   bool  `json:"is_expire"`
-	ExpireTime int64 `json:"expire_time,omitempty"`
+	IsExpire       bool  `json:"is_expire"`
+	ExpireType     int   `json:"expire_type,omitempty"` // ExpireType*
+	ExpireTime     int64 `json:"expire_time,omitempty"`
+	ExpireInterval int   `json:"expire_interval,omitempty"` // 失效天数, 最长30天
 }
 
 func (api *WxaApi) GenerateScheme(
@@ -147,3 +159,53 @@ func (api *WxaApi) GetSchema(
 	}
 	return &result.SchemaInfo, nil
 }
+
+/**
+获取 NFC 的小程序 scheme，适用于 NFC 拉起小程序的业务场景
+https://developers.weixin.qq.com/miniprogram/dev/OpenApiDoc/qrcode-link/url-scheme/generateNFCScheme.html
+**/
+type GenerateNFCSchemeRequest struct {
+	JumpWxa *struct {
+		Path       string `json:"path"`
+		Query      string `json:"query,omitempty"`
+		EnvVersion string `json:"env_version,omitempty"`
+	} `json:"jump_wxa,omitempty"`
+	ModelID string `json:"model_id"`     // scheme 对应的设备 model_id
+	SN      string `json:"sn,omitempty"` // scheme 对应的设备 sn，仅一机一码时填写
+}
+
+func (api *WxaApi) GenerateNFCScheme(
+	ctx context.Context, param *GenerateNFCSchemeRequest,
+) (string, error) {
+	result := &struct {
+		utils.WeixinError
+		OpenLink string `json:"openlink"`
+	}{}
+	if err := api.Client.HTTPPostJson(ctx, apiGenerateNFCScheme, param, result); err != nil {
+		return "", err
+	}
+	return result.OpenLink, nil
+}
+
+/**
+获取小程序 Short Link，适用于微信内拉起小程序的业务场景
+https://developers.weixin.qq.com/miniprogram/dev/OpenApiDoc/qrcode-link/short-link/generateShortLink.html
+**/
+type GenerateShortLinkRequest struct {
+	PageUrl     string `json:"page_url"`             // 通过 Short Link 进入的小程序页面路径，必须是已经发布的小程序存在的页面，可携带 query
+	PageTitle   string `json:"page_title,omitempty"` // 页面标题，不能包含违法信息，超过20字符会用... 截断代替
+	IsPermanent bool   `json:"is_permanent"`         // 生成的 Short Link 类型，短期有效：false，永久有效：true
+}
+
+func (api *WxaApi) GenerateShortLink(
+	ctx context.Context, param *GenerateShortLinkRequest,
+) (string, error) {
+	result := &struct {
+		utils.WeixinError
+		Link string `json:"link"`
+	}{}
+	if err := api.Client.HTTPPostJson(ctx, apiGenerateShortLink, param, result); err != nil {
+		return "", err
+	}
+	return result.Link, nil
+}
@@ -0,0 +1,168 @@
+// 小程序码生成服务: 相同参数只生成一次, 图片保存到 BlobStore
+
+package wxacode
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"path"
+	"sync"
+
+	"github.com/lixinio/weixin/weixin/wxa_api"
+)
+
+// 小程序码类型
+const (
+	KindUnlimit = "unlimit" // GetWxaCodeUnlimit, 数量不限
+	KindCode    = "code"    // GetWxaCode, 与 QRCode 合计10万个
+	KindQRCode  = "qrcode"  // CreateWxaQRCode
+)
+
+type Config struct {
+	Api    *wxa_api.WxaApi
+	Store  BlobStore
+	Prefix string // key 前缀, 多个小程序共用一个 Store 时用于区分, 一般使用 appid
+}
+
+// Code 已生成的小程序码
+type Code struct {
+	Key    string
+	URL    string
+	Cached bool // 是否复用已生成的图片
+}
+
+type Service struct {
+	config *Config
+
+	mutex    sync.Mutex
+	inflight map[string]*call
+}
+
+// call 正在生成的请求, 相同的请求等待同一个结果
+type call struct {
+	done chan struct{}
+	err  error
+}
+
+func New(config *Config) *Service {
+	if config.Api == nil || config.Store == nil {
+		panic("wxacode: Config.Api and Config.Store are required")
+	}
+	return &Service{config: config, inflight: map[string]*call{}}
+}
+
+// Unlimit 获取不限数量的小程序码
+func (s *Service) Unlimit(ctx context.Context, param *wxa_api.GetWxaCodeUnlimitRequest) (*Code, error) {
+	return s.get(ctx, KindUnlimit, param, func(w io.Writer) error {
+		_, err := s.config.Api.GetWxaCodeUnlimitTo(ctx, param, w)
+		return err
+	})
+}
+
+// Code 获取有数量限制的小程序码, 相同参数永远不会重复消耗额度
+func (s *Service) Code(ctx context.Context, param *wxa_api.GetWxaCodeRequest) (*Code, error) {
+	return s.get(ctx, KindCode, param, func(w io.Writer) error {
+		_, err := s.config.Api.GetWxaCodeTo(ctx, param, w)
+		return err
+	})
+}
+
+// QRCode 获取小程序二维码
+func (s *Service) QRCode(ctx context.Context, pagePath string, width int) (*Code, error) {
+	param := &struct {
+		Path  string `json:"path"`
+		Width int    `json:"width"`
+	}{pagePath, width}
+	return s.get(ctx, KindQRCode, param, func(w io.Writer) error {
+		_, err := s.config.Api.CreateWxaQRCodeTo(ctx, pagePath, width, w)
+		return err
+	})
+}
+
+// Open 读取已生成的图片
+func (s *Service) Open(ctx context.Context, code *Code) (io.ReadCloser, error) {
+	return s.config.Store.Open(ctx, code.Key)
+}
+
+// Key 根据类型和参数计算保存的 key, 格式 prefix/kind/xx/hash
+func (s *Service) Key(kind string, param interface{}) (string, error) {
+	data, err := json.Marshal(param)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	return path.Join(s.config.Prefix, kind, hash[:2], hash), nil
+}
+
+func (s *Service) get(
+	ctx context.Context, kind string, param interface{}, download func(io.Writer) error,
+) (*Code, error) {
+	key, err := s.Key(kind, param)
+	if err != nil {
+		return nil, err
+	}
+
+	exist, err := s.config.Store.Exists(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if exist {
+		return s.code(key, true), nil
+	}
+
+	s.mutex.Lock()
+	if c, ok := s.inflight[key]; ok {
+		s.mutex.Unlock()
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-c.done:
+		}
+		if c.err != nil {
+			return nil, c.err
+		}
+		return s.code(key, true), nil
+	}
+	c := &call{done: make(chan struct{})}
+	s.inflight[key] = c
+	s.mutex.Unlock()
+
+	c.err = s.generate(ctx, key, download)
+
+	s.mutex.Lock()
+	delete(s.inflight, key)
+	s.mutex.Unlock()
+	close(c.done)
+
+	if c.err != nil {
+		return nil, c.err
+	}
+	return s.code(key, false), nil
+}
+
+// generate 下载的同时写入 Store, 不在内存中缓冲整张图片
+func (s *Service) generate(ctx context.Context, key string, download func(io.Writer) error) error {
+	reader, writer := io.Pipe()
+	errc := make(chan error, 1)
+	go func() {
+		err := download(writer)
+		writer.CloseWithError(err)
+		errc <- err
+	}()
+
+	putErr := s.config.Store.Put(ctx, key, reader)
+	reader.Close()
+	// Store 先失败时下载返回 io.ErrClosedPipe, 以 Store 的错误为准
+	if err := <-errc; err != nil && err != io.ErrClosedPipe {
+		return err
+	}
+	return putErr
+}
+
+func (s *Service) code(key string, cached bool) *Code {
+	return &Code{Key: key, URL: s.config.Store.URL(key), Cached: cached}
+}
@@ -0,0 +1,106 @@
+package wxacode
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lixinio/weixin/utils"
+	"github.com/lixinio/weixin/weixin/wxa_api"
+	"github.com/stretchr/testify/require"
+)
+
+func newCodeServer() (*httptest.Server, *int) {
+	var mutex sync.Mutex
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		calls++
+		mutex.Unlock()
+
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.URL.Path == "/wxa/getwxacode" {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"errcode":45029,"errmsg":"qrcode count out of limit"}`))
+			return
+		}
+		// 模拟生成耗时, 让并发的相同请求合并
+		time.Sleep(20 * time.Millisecond)
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Write(append([]byte("image:"), body...))
+	}))
+	return server, &calls
+}
+
+func TestService(t *testing.T) {
+	ctx := context.Background()
+	server, calls := newCodeServer()
+	defer server.Close()
+
+	dir := t.TempDir()
+	service := New(&Config{
+		Api:    wxa_api.NewApi(utils.NewClient(server.URL, utils.StaticClientAccessTokenGetter("token"))),
+		Store:  NewLocalStore(dir, "https://static.example.com/wxacode/"),
+		Prefix: "wx_demo",
+	})
+
+	param := &wxa_api.GetWxaCodeUnlimitRequest{Scene: "id=1", Page: "pages/index/index"}
+	var wg sync.WaitGroup
+	codes := make([]*Code, 5)
+	errs := make([]error, 5)
+	for i := range codes {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			codes[i], errs[i] = service.Unlimit(ctx, param)
+		}(i)
+	}
+	wg.Wait()
+	require.Equal(t, make([]error, 5), errs)
+	require.Equal(t, 1, *calls)
+
+	code := codes[0]
+	require.Equal(t, "https://static.example.com/wxacode/"+code.Key, code.URL)
+	require.Equal(t, "wx_demo/unlimit/", code.Key[:16])
+
+	reader, err := service.Open(ctx, code)
+	require.Equal(t, nil, err)
+	data, err := ioutil.ReadAll(reader)
+	reader.Close()
+	require.Equal(t, nil, err)
+	require.Contains(t, string(data), `"scene":"id=1"`)
+
+	code, err = service.Unlimit(ctx, param)
+	require.Equal(t, nil, err)
+	require.True(t, code.Cached)
+	require.Equal(t, 1, *calls)
+
+	// 参数不同重新生成
+	code, err = service.QRCode(ctx, "pages/index/index?id=1", 430)
+	require.Equal(t, nil, err)
+	require.False(t, code.Cached)
+	require.Equal(t, 2, *calls)
+
+	// 失败不会留下文件
+	_, err = service.Code(ctx, &wxa_api.GetWxaCodeRequest{Path: "pages/index/index"})
+	weixinErr := &utils.WeixinError{}
+	require.True(t, errors.As(err, &weixinErr))
+	require.Equal(t, int64(45029), weixinErr.ErrCode)
+	key, err := service.Key(KindCode, &wxa_api.GetWxaCodeRequest{Path: "pages/index/index"})
+	require.Equal(t, nil, err)
+	_, err = os.Stat(filepath.Join(dir, key))
+	require.True(t, os.IsNotExist(err))
+	files, err := ioutil.ReadDir(filepath.Dir(filepath.Join(dir, key)))
+	require.Equal(t, nil, err)
+	require.Equal(t, 0, len(files))
+
+	_, err = service.Open(ctx, &Code{Key: key})
+	require.Equal(t, ErrBlobNotFound, err)
+}
@@ -0,0 +1,187 @@
+package wxa_live
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"github.com/lixinio/weixin/utils"
+)
+
+const (
+	apiAddGoods          = "/wxaapi/broadcast/goods/add"
+	apiResetAuditGoods   = "/wxaapi/broadcast/goods/resetaudit"
+	apiAuditGoods        = "/wxaapi/broadcast/goods/audit"
+	apiDeleteGoods       = "/wxaapi/broadcast/goods/delete"
+	apiUpdateGoods       = "/wxaapi/broadcast/goods/update"
+	apiGetGoodsWarehouse = "/wxa/business/getgoodswarehouse"
+	apiGetApprovedGoods  = "/wxaapi/broadcast/goods/getapproved"
+
+	maxGoodsPageSize = 100 // getapproved 每页最多100个
+)
+
+// 价格类型
+const (
+	PriceTypeFixed    = 1 // 一口价, 只需要传 Price
+	PriceTypeRange    = 2 // 价格区间, Price 左边界, Price2 右边界
+	PriceTypeDiscount = 3 // 显示折扣价, Price 原价, Price2 现价
+)
+
+// 商品审核状态
+const (
+	GoodsAuditStatusPending  = 0 // 未审核
+	GoodsAuditStatusAuditing = 1 // 审核中
+	GoodsAuditStatusApproved = 2 // 审核通过
+	GoodsAuditStatusRejected = 3 // 审核驳回
+)
+
+type GoodsInfo struct {
+	GoodsID         int     `json:"goodsId,omitempty"`         // 仅更新时使用
+	CoverImgUrl     string  `json:"coverImgUrl,omitempty"`     // 商品图片 media_id, 图片规则：图片尺寸最大300像素*300像素
+	Name            string  `json:"name,omitempty"`            // 商品名称，最长14个汉字
+	PriceType       int     `json:"priceType,omitempty"`       // PriceType*
+	Price           float64 `json:"price,omitempty"`           // 价格(元)
+	Price2          float64 `json:"price2,omitempty"`          // 价格(元)
+	Url             string  `json:"url,omitempty"`             // 商品详情页的小程序路径
+	ThirdPartyAppid string  `json:"thirdPartyAppid,omitempty"` // 当商品为第三方小程序的商品则填写为对应第三方小程序的appid
+}
+
+type AddGoodsResult struct {
+	utils.WeixinError
+	GoodsID int `json:"goodsId"`
+	AuditID int `json:"auditId"`
+}
+
+/*
+商品添加并提审
+https://developers.weixin.qq.com/miniprogram/dev/platform-capabilities/industry/liveplayer/commodity-api.html
+POST https://api.weixin.qq.com/wxaapi/broadcast/goods/add?access_token=ACCESS_TOKEN
+*/
+func (api *WxaLiveApi) AddGoods(ctx context.Context, goods *GoodsInfo) (*AddGoodsResult, error) {
+	result := &AddGoodsResult{}
+	if err := api.Client.HTTPPostJson(ctx, apiAddGoods, map[string]interface{}{
+		"goodsInfo": goods,
+	}, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+/*
+撤回商品审核
+https://developers.weixin.qq.com/miniprogram/dev/platform-capabilities/industry/liveplayer/commodity-api.html
+POST https://api.weixin.qq.com/wxaapi/broadcast/goods/resetaudit?access_token=ACCESS_TOKEN
+*/
+func (api *WxaLiveApi) ResetAuditGoods(ctx context.Context, auditID, goodsID int) error {
+	return api.Client.HTTPPostJson(ctx, apiResetAuditGoods, map[string]int{
+		"auditId": auditID,
+		"goodsId": goodsID,
+	}, nil)
+}
+
+/*
+重新提交审核, 返回审核单 id
+https://developers.weixin.qq.com/miniprogram/dev/platform-capabilities/industry/liveplayer/commodity-api.html
+POST https://api.weixin.qq.com/wxaapi/broadcast/goods/audit?access_token=ACCESS_TOKEN
+*/
+func (api *WxaLiveApi) AuditGoods(ctx context.Context, goodsID int) (int, error) {
+	result := &struct {
+		utils.WeixinError
+		AuditID int `json:"auditId"`
+	}{}
+	if err := api.Client.HTTPPostJson(ctx, apiAuditGoods, map[string]int{
+		"goodsId": goodsID,
+	}, result); err != nil {
+		return 0, err
+	}
+	return result.AuditID, nil
+}
+
+/*
+删除商品
+https://developers.weixin.qq.com/miniprogram/dev/platform-capabilities/industry/liveplayer/commodity-api.html
+POST https://api.weixin.qq.com/wxaapi/broadcast/goods/delete?access_token=ACCESS_TOKEN
+*/
+func (api *WxaLiveApi) DeleteGoods(ctx context.Context, goodsID int) error {
+	return api.Client.HTTPPostJson(ctx, apiDeleteGoods, map[string]int{
+		"goodsId": goodsID,
+	}, nil)
+}
+
+/*
+更新商品, 审核通过的商品仅允许更新价格类型与价格, 审核中的商品不允许更新
+https://developers.weixin.qq.com/miniprogram/dev/platform-capabilities/industry/liveplayer/commodity-api.html
+POST https://api.weixin.qq.com/wxaapi/broadcast/goods/update?access_token=ACCESS_TOKEN
+*/
+func (api *WxaLiveApi) UpdateGoods(ctx context.Context, goods *GoodsInfo) error {
+	return api.Client.HTTPPostJson(ctx, apiUpdateGoods, map[string]interface{}{
+		"goodsInfo": goods,
+	}, nil)
+}
+
+type GoodsWarehouse struct {
+	utils.WeixinError
+	Goods []struct {
+		GoodsID         int     `json:"goods_id"`
+		CoverImgUrl     string  `json:"cover_img_url"`
+		Name            string  `json:"name"`
+		PriceType       int     `json:"price_type"`
+		Price           float64 `json:"price"`
+		Price2          float64 `json:"price2"`
+		Url             string  `json:"url"`
+		AuditStatus     int     `json:"audit_status"` // GoodsAuditStatus*
+		ThirdPartyTag   int     `json:"third_party_tag"`
+		ThirdPartyAppid string  `json:"third_party_appid"`
+	} `json:"goods"`
+	Total int `json:"total"`
+}
+
+/*
+获取商品状态
+https://developers.weixin.qq.com/miniprogram/dev/platform-capabilities/industry/liveplayer/commodity-api.html
+POST https://api.weixin.qq.com/wxa/business/getgoodswarehouse?access_token=ACCESS_TOKEN
+*/
+func (api *WxaLiveApi) GetGoodsWarehouse(ctx context.Context, goodsIDs ...int) (*GoodsWarehouse, error) {
+	result := &GoodsWarehouse{}
+	if err := api.Client.HTTPPostJson(ctx, apiGetGoodsWarehouse, map[string]interface{}{
+		"goods_ids": goodsIDs,
+	}, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type Goods struct {
+	GoodsID         int     `json:"goodsId"`
+	CoverImgUrl     string  `json:"coverImgUrl"`
+	Name            string  `json:"name"`
+	Price           float64 `json:"price"`
+	Price2          float64 `json:"price2"`
+	PriceType       int     `json:"priceType"`
+	Url             string  `json:"url"`
+	ThirdPartyTag   int     `json:"thirdPartyTag"` // 1、2：表示是为 API 添加商品，否则是直播控制台添加的商品
+	ThirdPartyAppid string  `json:"thirdPartyAppid"`
+}
+
+type GoodsList struct {
+	utils.WeixinError
+	Goods []Goods `json:"goods"`
+	Total int     `json:"total"`
+}
+
+/*
+获取商品列表, status 为 GoodsAuditStatus*, limit 最大100
+https://developers.weixin.qq.com/miniprogram/dev/platform-capabilities/industry/liveplayer/commodity-api.html
+GET https://api.weixin.qq.com/wxaapi/broadcast/goods/getapproved?access_token=ACCESS_TOKEN&offset=0&limit=30&status=2
+*/
+func (api *WxaLiveApi) GetGoodsList(ctx context.Context, status, offset, limit int) (*GoodsList, error) {
+	result := &GoodsList{}
+	if err := api.Client.HTTPGetWithParams(ctx, apiGetApprovedGoods, func(params url.Values) {
+		params.Add("status", strconv.Itoa(status))
+		params.Add("offset", strconv.Itoa(offset))
+		params.Add("limit", strconv.Itoa(limit))
+	}, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
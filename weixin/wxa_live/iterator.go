@@ -0,0 +1,108 @@
+package wxa_live
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/lixinio/weixin/utils"
+)
+
+const errCodeRoomListEmpty = 9410000 // 直播间列表为空
+
+// pager 按 offset 翻页, 最后一页不足 pageSize 或者达到 total 时结束
+type pager struct {
+	offset   int
+	pageSize int
+	done     bool
+}
+
+func newPager(pageSize, maxPageSize int) pager {
+	if pageSize <= 0 || pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	return pager{pageSize: pageSize}
+}
+
+// advance 记录一页的结果, 返回 false 表示没有数据
+func (p *pager) advance(count, total int) bool {
+	p.offset += count
+	if count == 0 || count < p.pageSize || p.offset >= total {
+		p.done = true
+	}
+	return count > 0
+}
+
+// RoomIterator 直播间列表迭代器, 自动翻页
+type RoomIterator struct {
+	api   *WxaLiveApi
+	pager pager
+	rooms []RoomInfo
+}
+
+// 遍历直播间列表, pageSize 为每页数量(最大100)
+func (api *WxaLiveApi) NewRoomIterator(pageSize int) *RoomIterator {
+	return &RoomIterator{api: api, pager: newPager(pageSize, maxRoomPageSize)}
+}
+
+// Next 返回下一个直播间, 遍历完毕返回 io.EOF
+func (it *RoomIterator) Next(ctx context.Context) (*RoomInfo, error) {
+	if len(it.rooms) == 0 {
+		if it.pager.done {
+			return nil, io.EOF
+		}
+
+		result, err := it.api.GetRoomList(ctx, it.pager.offset, it.pager.pageSize)
+		if err != nil {
+			weixinErr := &utils.WeixinError{}
+			if errors.As(err, &weixinErr) && weixinErr.ErrCode == errCodeRoomListEmpty {
+				it.pager.done = true
+				return nil, io.EOF
+			}
+			return nil, err
+		}
+		if !it.pager.advance(len(result.RoomInfo), result.Total) {
+			return nil, io.EOF
+		}
+		it.rooms = result.RoomInfo
+	}
+
+	room := it.rooms[0]
+	it.rooms = it.rooms[1:]
+	return &room, nil
+}
+
+// GoodsIterator 商品列表迭代器, 自动翻页
+type GoodsIterator struct {
+	api    *WxaLiveApi
+	status int
+	pager  pager
+	goods  []Goods
+}
+
+// 遍历指定审核状态(GoodsAuditStatus*)的商品, pageSize 为每页数量(最大100)
+func (api *WxaLiveApi) NewGoodsIterator(status, pageSize int) *GoodsIterator {
+	return &GoodsIterator{api: api, status: status, pager: newPager(pageSize, maxGoodsPageSize)}
+}
+
+// Next 返回下一个商品, 遍历完毕返回 io.EOF
+func (it *GoodsIterator) Next(ctx context.Context) (*Goods, error) {
+	if len(it.goods) == 0 {
+		if it.pager.done {
+			return nil, io.EOF
+		}
+
+		result, err := it.api.GetGoodsList(ctx, it.status, it.pager.offset, it.pager.pageSize)
+		if err != nil {
+			return nil, err
+		}
+		if !it.pager.advance(len(result.Goods), result.Total) {
+			return nil, io.EOF
+		}
+		it.goods = result.Goods
+	}
+
+	goods := it.goods[0]
+	it.goods = it.goods[1:]
+	return &goods, nil
+}
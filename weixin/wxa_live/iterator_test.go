@@ -0,0 +1,91 @@
+package wxa_live
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/lixinio/weixin/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// 模拟直播间和商品列表
+func newLiveServer(rooms, goods int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case apiGetLiveInfo:
+			params := map[string]int{}
+			json.NewDecoder(r.Body).Decode(&params)
+			if params["start"] >= rooms {
+				w.Write([]byte(`{"errcode":9410000,"errmsg":"直播间列表为空"}`))
+				return
+			}
+			result := &RoomList{Total: rooms}
+			for i := params["start"]; i < rooms && i < params["start"]+params["limit"]; i++ {
+				result.RoomInfo = append(result.RoomInfo, RoomInfo{RoomID: i + 1})
+			}
+			json.NewEncoder(w).Encode(result)
+		case apiGetApprovedGoods:
+			offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+			limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+			result := &GoodsList{Total: goods}
+			for i := offset; i < goods && i < offset+limit; i++ {
+				result.Goods = append(result.Goods, Goods{GoodsID: i + 1})
+			}
+			json.NewEncoder(w).Encode(result)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestRoomIterator(t *testing.T) {
+	ctx := context.Background()
+	for _, total := range []int{0, 3, 4, 5} {
+		server := newLiveServer(total, 0)
+		api := NewApi(utils.NewClient(server.URL, utils.StaticClientAccessTokenGetter("token")))
+
+		it := api.NewRoomIterator(2)
+		roomIDs := []int{}
+		for {
+			room, err := it.Next(ctx)
+			if err == io.EOF {
+				break
+			}
+			require.Equal(t, nil, err)
+			roomIDs = append(roomIDs, room.RoomID)
+		}
+		require.Equal(t, total, len(roomIDs))
+		for i, roomID := range roomIDs {
+			require.Equal(t, i+1, roomID)
+		}
+		_, err := it.Next(ctx)
+		require.Equal(t, io.EOF, err)
+		server.Close()
+	}
+}
+
+func TestGoodsIterator(t *testing.T) {
+	ctx := context.Background()
+	server := newLiveServer(0, 250)
+	defer server.Close()
+	api := NewApi(utils.NewClient(server.URL, utils.StaticClientAccessTokenGetter("token")))
+
+	it := api.NewGoodsIterator(GoodsAuditStatusApproved, 0)
+	count := 0
+	for {
+		goods, err := it.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		require.Equal(t, nil, err)
+		count++
+		require.Equal(t, count, goods.GoodsID)
+	}
+	require.Equal(t, 250, count)
+}
@@ -0,0 +1,85 @@
+package wxa_live
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"github.com/lixinio/weixin/utils"
+)
+
+const (
+	apiAddRole     = "/wxaapi/broadcast/role/addrole"
+	apiDeleteRole  = "/wxaapi/broadcast/role/deleterole"
+	apiGetRoleList = "/wxaapi/broadcast/role/getrolelist"
+)
+
+// 成员角色
+const (
+	RoleAll        = -1 // 所有成员, 仅查询使用
+	RoleSuperAdmin = 0  // 超级管理员, 仅查询使用
+	RoleAdmin      = 1  // 管理员
+	RoleAnchor     = 2  // 主播
+	RoleOperator   = 3  // 运营者
+)
+
+/*
+设置成员角色, username 为用户的微信号
+https://developers.weixin.qq.com/miniprogram/dev/platform-capabilities/industry/liveplayer/role-manage.html
+POST https://api.weixin.qq.com/wxaapi/broadcast/role/addrole?access_token=ACCESS_TOKEN
+*/
+func (api *WxaLiveApi) AddRole(ctx context.Context, username string, role int) error {
+	return api.Client.HTTPPostJson(ctx, apiAddRole, map[string]interface{}{
+		"username": username,
+		"role":     role,
+	}, nil)
+}
+
+/*
+解除成员角色
+https://developers.weixin.qq.com/miniprogram/dev/platform-capabilities/industry/liveplayer/role-manage.html
+POST https://api.weixin.qq.com/wxaapi/broadcast/role/deleterole?access_token=ACCESS_TOKEN
+*/
+func (api *WxaLiveApi) DeleteRole(ctx context.Context, username string, role int) error {
+	return api.Client.HTTPPostJson(ctx, apiDeleteRole, map[string]interface{}{
+		"username": username,
+		"role":     role,
+	}, nil)
+}
+
+type RoleMember struct {
+	Headingimg      string `json:"headingimg"`
+	NickName        string `json:"nickName"`
+	OpenID          string `json:"openid"`
+	RoleList        []int  `json:"roleList"` // Role*
+	UpdateTimestamp string `json:"updateTimestamp"`
+	Username        string `json:"username"` // 脱敏微信号
+}
+
+type RoleList struct {
+	utils.WeixinError
+	Total int          `json:"total"`
+	List  []RoleMember `json:"list"`
+}
+
+/*
+查询成员列表, keyword 为搜索的微信号或昵称, 可为空
+https://developers.weixin.qq.com/miniprogram/dev/platform-capabilities/industry/liveplayer/role-manage.html
+GET https://api.weixin.qq.com/wxaapi/broadcast/role/getrolelist?access_token=ACCESS_TOKEN&role=-1&offset=0&limit=30&keyword=
+*/
+func (api *WxaLiveApi) GetRoleList(
+	ctx context.Context, role, offset, limit int, keyword string,
+) (*RoleList, error) {
+	result := &RoleList{}
+	if err := api.Client.HTTPGetWithParams(ctx, apiGetRoleList, func(params url.Values) {
+		params.Add("role", strconv.Itoa(role))
+		params.Add("offset", strconv.Itoa(offset))
+		params.Add("limit", strconv.Itoa(limit))
+		if keyword != "" {
+			params.Add("keyword", keyword)
+		}
+	}, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,417 @@
+package wxa_live
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"github.com/lixinio/weixin/utils"
+)
+
+const (
+	apiCreateRoom        = "/wxaapi/broadcast/room/create"
+	apiDeleteRoom        = "/wxaapi/broadcast/room/deleteroom"
+	apiEditRoom          = "/wxaapi/broadcast/room/editroom"
+	apiGetLiveInfo       = "/wxa/business/getliveinfo"
+	apiGetPushUrl        = "/wxaapi/broadcast/room/getpushurl"
+	apiGetSharedCode     = "/wxaapi/broadcast/room/getsharedcode"
+	apiAddAssistant      = "/wxaapi/broadcast/room/addassistant"
+	apiModifyAssistant   = "/wxaapi/broadcast/room/modifyassistant"
+	apiRemoveAssistant   = "/wxaapi/broadcast/room/removeassistant"
+	apiGetAssistantList  = "/wxaapi/broadcast/room/getassistantlist"
+	apiAddSubAnchor      = "/wxaapi/broadcast/room/addsubanchor"
+	apiModifySubAnchor   = "/wxaapi/broadcast/room/modifysubanchor"
+	apiDeleteSubAnchor   = "/wxaapi/broadcast/room/deletesubanchor"
+	apiGetSubAnchor      = "/wxaapi/broadcast/room/getsubanchor"
+	apiAddRoomGoods      = "/wxaapi/broadcast/room/addgoods"
+	apiPushGoods         = "/wxaapi/broadcast/goods/push"
+	apiSortGoods         = "/wxaapi/broadcast/goods/sort"
+	apiDeleteGoodsInRoom = "/wxaapi/broadcast/goods/deleteInRoom"
+	apiOnSaleGoods       = "/wxaapi/broadcast/goods/onsale"
+
+	maxRoomPageSize = 100 // getliveinfo 每页最多100个
+)
+
+// 直播间类型
+const (
+	RoomTypePhone = 0 // 手机直播
+	RoomTypePush  = 1 // 推流
+)
+
+type RoomParams struct {
+	ID              int    `json:"id,omitempty"`              // 直播间id, 仅编辑时使用
+	Name            string `json:"name"`                      // 直播间名字，最短3个汉字，最长17个汉字
+	CoverImg        string `json:"coverImg"`                  // 背景图 media_id(临时素材)
+	StartTime       int64  `json:"startTime"`                 // 开播时间，开播时间需要在当前时间的10分钟后
+	EndTime         int64  `json:"endTime"`                   // 结束时间，开播时间和结束时间间隔不得短于30分钟，不得超过24小时
+	AnchorName      string `json:"anchorName"`                // 主播昵称
+	AnchorWechat    string `json:"anchorWechat"`              // 主播微信号，需实名认证
+	SubAnchorWechat string `json:"subAnchorWechat,omitempty"` // 主播副号微信号
+	CreaterWechat   string `json:"createrWechat,omitempty"`   // 创建者微信号
+	ShareImg        string `json:"shareImg"`                  // 分享图 media_id
+	FeedsImg        string `json:"feedsImg,omitempty"`        // 购物直播频道封面图 media_id
+	IsFeedsPublic   int    `json:"isFeedsPublic"`             // 是否开启官方收录 1 开启，0 关闭
+	Type            int    `json:"type"`                      // RoomType*
+	CloseLike       int    `json:"closeLike"`                 // 是否关闭点赞 【0：开启，1：关闭】
+	CloseGoods      int    `json:"closeGoods"`                // 是否关闭货架
+	CloseComment    int    `json:"closeComment"`              // 是否关闭评论
+	CloseReplay     int    `json:"closeReplay"`               // 是否关闭回放
+	CloseShare      int    `json:"closeShare"`                // 是否关闭分享
+	CloseKf         int    `json:"closeKf"`                   // 是否关闭客服
+}
+
+type CreateRoomResult struct {
+	utils.WeixinError
+	RoomID    int    `json:"roomId"`
+	QrcodeUrl string `json:"qrcode_url"` // 主播微信号未实名认证时返回, 主播扫码认证之后才能开播
+}
+
+/*
+创建直播间
+https://developers.weixin.qq.com/miniprogram/dev/platform-capabilities/industry/liveplayer/studio-api.html
+POST https://api.weixin.qq.com/wxaapi/broadcast/room/create?access_token=ACCESS_TOKEN
+*/
+func (api *WxaLiveApi) CreateRoom(ctx context.Context, params *RoomParams) (*CreateRoomResult, error) {
+	result := &CreateRoomResult{}
+	if err := api.Client.HTTPPostJson(ctx, apiCreateRoom, params, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+/*
+删除直播间
+https://developers.weixin.qq.com/miniprogram/dev/platform-capabilities/industry/liveplayer/studio-api.html
+POST https://api.weixin.qq.com/wxaapi/broadcast/room/deleteroom?access_token=ACCESS_TOKEN
+*/
+func (api *WxaLiveApi) DeleteRoom(ctx context.Context, roomID int) error {
+	return api.Client.HTTPPostJson(ctx, apiDeleteRoom, map[string]int{
+		"id": roomID,
+	}, nil)
+}
+
+/*
+编辑直播间, params.ID 必填
+https://developers.weixin.qq.com/miniprogram/dev/platform-capabilities/industry/liveplayer/studio-api.html
+POST https://api.weixin.qq.com/wxaapi/broadcast/room/editroom?access_token=ACCESS_TOKEN
+*/
+func (api *WxaLiveApi) EditRoom(ctx context.Context, params *RoomParams) error {
+	return api.Client.HTTPPostJson(ctx, apiEditRoom, params, nil)
+}
+
+type RoomGoods struct {
+	CoverImg        string `json:"cover_img"`
+	Url             string `json:"url"` // 小程序路径
+	Name            string `json:"name"`
+	Price           int    `json:"price"`      // 价格(分)
+	Price2          int    `json:"price2"`     // 价格区间的最高价或者折扣价(分)
+	PriceType       int    `json:"price_type"` // PriceType*
+	GoodsID         int    `json:"goods_id"`
+	ThirdPartyAppid string `json:"third_party_appid"`
+}
+
+type RoomInfo struct {
+	Name          string      `json:"name"`
+	RoomID        int         `json:"roomid"`
+	CoverImg      string      `json:"cover_img"`
+	ShareImg      string      `json:"share_img"`
+	LiveStatus    int         `json:"live_status"` // LiveStatus*
+	StartTime     int64       `json:"start_time"`
+	EndTime       int64       `json:"end_time"`
+	AnchorName    string      `json:"anchor_name"`
+	Goods         []RoomGoods `json:"goods"`
+	LiveType      int         `json:"live_type"` // RoomType*
+	CloseLike     int         `json:"close_like"`
+	CloseGoods    int         `json:"close_goods"`
+	CloseComment  int         `json:"close_comment"`
+	CloseKf       int         `json:"close_kf"`
+	CloseReplay   int         `json:"close_replay"`
+	IsFeedsPublic int         `json:"is_feeds_public"`
+	CreaterOpenID string      `json:"creater_openid"`
+	FeedsImg      string      `json:"feeds_img"`
+}
+
+type RoomList struct {
+	utils.WeixinError
+	RoomInfo []RoomInfo `json:"room_info"`
+	Total    int        `json:"total"`
+}
+
+/*
+获取直播间列表, limit 最大100
+https://developers.weixin.qq.com/miniprogram/dev/platform-capabilities/industry/liveplayer/studio-api.html
+POST https://api.weixin.qq.com/wxa/business/getliveinfo?access_token=ACCESS_TOKEN
+*/
+func (api *WxaLiveApi) GetRoomList(ctx context.Context, start, limit int) (*RoomList, error) {
+	result := &RoomList{}
+	if err := api.Client.HTTPPostJson(ctx, apiGetLiveInfo, map[string]int{
+		"start": start,
+		"limit": limit,
+	}, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type LiveReplay struct {
+	utils.WeixinError
+	LiveReplay []struct {
+		ExpireTime string `json:"expire_time"` // 回放视频 url 过期时间
+		CreateTime string `json:"create_time"` // 回放视频创建时间
+		MediaUrl   string `json:"media_url"`   // 回放视频链接
+	} `json:"live_replay"`
+	Total int `json:"total"`
+}
+
+/*
+获取直播间回放
+https://developers.weixin.qq.com/miniprogram/dev/platform-capabilities/industry/liveplayer/studio-api.html
+POST https://api.weixin.qq.com/wxa/business/getliveinfo?access_token=ACCESS_TOKEN
+*/
+func (api *WxaLiveApi) GetReplay(ctx context.Context, roomID, start, limit int) (*LiveReplay, error) {
+	result := &LiveReplay{}
+	if err := api.Client.HTTPPostJson(ctx, apiGetLiveInfo, map[string]interface{}{
+		"action":  "get_replay",
+		"room_id": roomID,
+		"start":   start,
+		"limit":   limit,
+	}, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func roomQuery(roomID int) func(url.Values) {
+	return func(params url.Values) {
+		params.Add("roomId", strconv.Itoa(roomID))
+	}
+}
+
+/*
+获取直播间推流地址
+https://developers.weixin.qq.com/miniprogram/dev/platform-capabilities/industry/liveplayer/studio-api.html
+GET https://api.weixin.qq.com/wxaapi/broadcast/room/getpushurl?access_token=ACCESS_TOKEN&roomId=ROOMID
+*/
+func (api *WxaLiveApi) GetPushUrl(ctx context.Context, roomID int) (string, error) {
+	result := &struct {
+		utils.WeixinError
+		PushAddr string `json:"pushAddr"`
+	}{}
+	if err := api.Client.HTTPGetWithParams(ctx, apiGetPushUrl, roomQuery(roomID), result); err != nil {
+		return "", err
+	}
+	return result.PushAddr, nil
+}
+
+type SharedCode struct {
+	utils.WeixinError
+	CdnUrl    string `json:"cdnUrl"`    // 分享二维码
+	PagePath  string `json:"pagePath"`  // 分享路径
+	PosterUrl string `json:"posterUrl"` // 分享海报
+}
+
+/*
+获取直播间分享二维码, customParams 为自定义参数(json), 可为空
+https://developers.weixin.qq.com/miniprogram/dev/platform-capabilities/industry/liveplayer/studio-api.html
+GET https://api.weixin.qq.com/wxaapi/broadcast/room/getsharedcode?access_token=ACCESS_TOKEN&roomId=ROOMID&params=PARAMS
+*/
+func (api *WxaLiveApi) GetSharedCode(ctx context.Context, roomID int, customParams string) (*SharedCode, error) {
+	result := &SharedCode{}
+	if err := api.Client.HTTPGetWithParams(ctx, apiGetSharedCode, func(params url.Values) {
+		params.Add("roomId", strconv.Itoa(roomID))
+		if customParams != "" {
+			params.Add("params", customParams)
+		}
+	}, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type Assistant struct {
+	Username string `json:"username"` // 用户微信号
+	Nickname string `json:"nickname"` // 用户微信昵称
+}
+
+/*
+添加管理直播间小助手, 每个直播间最多设置10个
+https://developers.weixin.qq.com/miniprogram/dev/platform-capabilities/industry/liveplayer/studio-api.html
+POST https://api.weixin.qq.com/wxaapi/broadcast/room/addassistant?access_token=ACCESS_TOKEN
+*/
+func (api *WxaLiveApi) AddAssistant(ctx context.Context, roomID int, users ...Assistant) error {
+	return api.Client.HTTPPostJson(ctx, apiAddAssistant, map[string]interface{}{
+		"roomId": roomID,
+		"users":  users,
+	}, nil)
+}
+
+/*
+修改直播间小助手昵称
+https://developers.weixin.qq.com/miniprogram/dev/platform-capabilities/industry/liveplayer/studio-api.html
+POST https://api.weixin.qq.com/wxaapi/broadcast/room/modifyassistant?access_token=ACCESS_TOKEN
+*/
+func (api *WxaLiveApi) ModifyAssistant(ctx context.Context, roomID int, username, nickname string) error {
+	return api.Client.HTTPPostJson(ctx, apiModifyAssistant, map[string]interface{}{
+		"roomId":   roomID,
+		"username": username,
+		"nickname": nickname,
+	}, nil)
+}
+
+/*
+删除直播间小助手
+https://developers.weixin.qq.com/miniprogram/dev/platform-capabilities/industry/liveplayer/studio-api.html
+POST https://api.weixin.qq.com/wxaapi/broadcast/room/removeassistant?access_token=ACCESS_TOKEN
+*/
+func (api *WxaLiveApi) RemoveAssistant(ctx context.Context, roomID int, username string) error {
+	return api.Client.HTTPPostJson(ctx, apiRemoveAssistant, map[string]interface{}{
+		"roomId":   roomID,
+		"username": username,
+	}, nil)
+}
+
+type AssistantList struct {
+	utils.WeixinError
+	List []struct {
+		Timestamp int64  `json:"timestamp"` // 修改时间
+		Headimg   string `json:"headimg"`
+		Nickname  string `json:"nickname"`
+		Alias     string `json:"alias"` // 微信号
+		OpenID    string `json:"openid"`
+	} `json:"list"`
+	Count    int `json:"count"`
+	MaxCount int `json:"maxCount"` // 小助手最大个数
+}
+
+/*
+查询直播间小助手
+https://developers.weixin.qq.com/miniprogram/dev/platform-capabilities/industry/liveplayer/studio-api.html
+GET https://api.weixin.qq.com/wxaapi/broadcast/room/getassistantlist?access_token=ACCESS_TOKEN&roomId=ROOMID
+*/
+func (api *WxaLiveApi) GetAssistantList(ctx context.Context, roomID int) (*AssistantList, error) {
+	result := &AssistantList{}
+	if err := api.Client.HTTPGetWithParams(ctx, apiGetAssistantList, roomQuery(roomID), result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+/*
+添加主播副号
+https://developers.weixin.qq.com/miniprogram/dev/platform-capabilities/industry/liveplayer/studio-api.html
+POST https://api.weixin.qq.com/wxaapi/broadcast/room/addsubanchor?access_token=ACCESS_TOKEN
+*/
+func (api *WxaLiveApi) AddSubAnchor(ctx context.Context, roomID int, username string) error {
+	return api.Client.HTTPPostJson(ctx, apiAddSubAnchor, map[string]interface{}{
+		"roomId":   roomID,
+		"username": username,
+	}, nil)
+}
+
+/*
+修改主播副号
+https://developers.weixin.qq.com/miniprogram/dev/platform-capabilities/industry/liveplayer/studio-api.html
+POST https://api.weixin.qq.com/wxaapi/broadcast/room/modifysubanchor?access_token=ACCESS_TOKEN
+*/
+func (api *WxaLiveApi) ModifySubAnchor(ctx context.Context, roomID int, username string) error {
+	return api.Client.HTTPPostJson(ctx, apiModifySubAnchor, map[string]interface{}{
+		"roomId":   roomID,
+		"username": username,
+	}, nil)
+}
+
+/*
+删除主播副号
+https://developers.weixin.qq.com/miniprogram/dev/platform-capabilities/industry/liveplayer/studio-api.html
+POST https://api.weixin.qq.com/wxaapi/broadcast/room/deletesubanchor?access_token=ACCESS_TOKEN
+*/
+func (api *WxaLiveApi) DeleteSubAnchor(ctx context.Context, roomID int) error {
+	return api.Client.HTTPPostJson(ctx, apiDeleteSubAnchor, map[string]int{
+		"roomId": roomID,
+	}, nil)
+}
+
+/*
+获取主播副号
+https://developers.weixin.qq.com/miniprogram/dev/platform-capabilities/industry/liveplayer/studio-api.html
+GET https://api.weixin.qq.com/wxaapi/broadcast/room/getsubanchor?access_token=ACCESS_TOKEN&roomId=ROOMID
+*/
+func (api *WxaLiveApi) GetSubAnchor(ctx context.Context, roomID int) (string, error) {
+	result := &struct {
+		utils.WeixinError
+		Username string `json:"username"`
+	}{}
+	if err := api.Client.HTTPGetWithParams(ctx, apiGetSubAnchor, roomQuery(roomID), result); err != nil {
+		return "", err
+	}
+	return result.Username, nil
+}
+
+/*
+直播间导入商品, 商品需要审核通过
+https://developers.weixin.qq.com/miniprogram/dev/platform-capabilities/industry/liveplayer/studio-api.html
+POST https://api.weixin.qq.com/wxaapi/broadcast/room/addgoods?access_token=ACCESS_TOKEN
+*/
+func (api *WxaLiveApi) AddRoomGoods(ctx context.Context, roomID int, goodsIDs ...int) error {
+	return api.Client.HTTPPostJson(ctx, apiAddRoomGoods, map[string]interface{}{
+		"roomId": roomID,
+		"ids":    goodsIDs,
+	}, nil)
+}
+
+/*
+推送商品(直播中弹出商品卡片)
+https://developers.weixin.qq.com/miniprogram/dev/platform-capabilities/industry/liveplayer/studio-api.html
+POST https://api.weixin.qq.com/wxaapi/broadcast/goods/push?access_token=ACCESS_TOKEN
+*/
+func (api *WxaLiveApi) PushGoods(ctx context.Context, roomID, goodsID int) error {
+	return api.Client.HTTPPostJson(ctx, apiPushGoods, map[string]int{
+		"roomId":  roomID,
+		"goodsId": goodsID,
+	}, nil)
+}
+
+/*
+直播间商品排序, goodsIDs 为排序之后的商品
+https://developers.weixin.qq.com/miniprogram/dev/platform-capabilities/industry/liveplayer/studio-api.html
+POST https://api.weixin.qq.com/wxaapi/broadcast/goods/sort?access_token=ACCESS_TOKEN
+*/
+func (api *WxaLiveApi) SortGoods(ctx context.Context, roomID int, goodsIDs ...int) error {
+	goods := make([]map[string]string, 0, len(goodsIDs))
+	for _, goodsID := range goodsIDs {
+		goods = append(goods, map[string]string{"goodsId": strconv.Itoa(goodsID)})
+	}
+	return api.Client.HTTPPostJson(ctx, apiSortGoods, map[string]interface{}{
+		"roomId": roomID,
+		"goods":  goods,
+	}, nil)
+}
+
+/*
+删除直播间商品
+https://developers.weixin.qq.com/miniprogram/dev/platform-capabilities/industry/liveplayer/studio-api.html
+POST https://api.weixin.qq.com/wxaapi/broadcast/goods/deleteInRoom?access_token=ACCESS_TOKEN
+*/
+func (api *WxaLiveApi) DeleteGoodsInRoom(ctx context.Context, roomID, goodsID int) error {
+	return api.Client.HTTPPostJson(ctx, apiDeleteGoodsInRoom, map[string]int{
+		"roomId":  roomID,
+		"goodsId": goodsID,
+	}, nil)
+}
+
+/*
+上下架直播间商品
+https://developers.weixin.qq.com/miniprogram/dev/platform-capabilities/industry/liveplayer/studio-api.html
+POST https://api.weixin.qq.com/wxaapi/broadcast/goods/onsale?access_token=ACCESS_TOKEN
+*/
+func (api *WxaLiveApi) OnSaleGoods(ctx context.Context, roomID, goodsID int, onSale bool) error {
+	status := 0
+	if onSale {
+		status = 1
+	}
+	return api.Client.HTTPPostJson(ctx, apiOnSaleGoods, map[string]int{
+		"roomId":  roomID,
+		"goodsId": goodsID,
+		"onSale":  status,
+	}, nil)
+}
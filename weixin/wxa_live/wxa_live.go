@@ -0,0 +1,27 @@
+// 小程序直播
+// https://developers.weixin.qq.com/miniprogram/dev/framework/liveplayer/studio-api.html
+
+package wxa_live
+
+import (
+	"github.com/lixinio/weixin/utils"
+)
+
+// 直播间状态
+const (
+	LiveStatusLiving   = 101 // 直播中
+	LiveStatusNotStart = 102 // 未开始
+	LiveStatusEnded    = 103 // 已结束
+	LiveStatusBanned   = 104 // 禁播
+	LiveStatusPaused   = 105 // 暂停
+	LiveStatusError    = 106 // 异常
+	LiveStatusExpired  = 107 // 已过期
+)
+
+type WxaLiveApi struct {
+	*utils.Client
+}
+
+func NewApi(client *utils.Client) *WxaLiveApi {
+	return &WxaLiveApi{Client: client}
+}
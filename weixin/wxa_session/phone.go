@@ -0,0 +1,40 @@
+package wxa_session
+
+import (
+	"context"
+
+	"github.com/lixinio/weixin/utils"
+)
+
+const (
+	apiGetUserPhoneNumber = "/wxa/business/getuserphonenumber"
+)
+
+type WxaSessionApi struct {
+	*utils.Client
+}
+
+func NewApi(client *utils.Client) *WxaSessionApi {
+	return &WxaSessionApi{Client: client}
+}
+
+/*
+code换取用户手机号
+每个 code 只能使用一次，code的有效期为5min
+See: https://developers.weixin.qq.com/miniprogram/dev/api-backend/open-api/phonenumber/phonenumber.getPhoneNumber.html
+POST https://api.weixin.qq.com/wxa/business/getuserphonenumber?access_token=ACCESS_TOKEN
+*/
+func (api *WxaSessionApi) GetUserPhoneNumber(ctx context.Context, code string) (*PhoneInfo, error) {
+	param := &struct {
+		Code string `json:"code"`
+	}{code}
+
+	result := &struct {
+		utils.WeixinError
+		PhoneInfo *PhoneInfo `json:"phone_info"`
+	}{}
+	if err := api.Client.HTTPPostJson(ctx, apiGetUserPhoneNumber, param, result); err != nil {
+		return nil, err
+	}
+	return result.PhoneInfo, nil
+}
@@ -0,0 +1,222 @@
+package wxa_session
+
+// Package wxa_session 小程序开放数据解密
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrInvalidSessionKey = errors.New("invalid session key")
+	ErrInvalidIV         = errors.New("invalid iv")
+	ErrInvalidData       = errors.New("invalid encrypted data")
+	ErrInvalidSignature  = errors.New("invalid signature")
+	ErrAppIDMismatch     = errors.New("watermark appid mismatch")
+	ErrWatermarkExpired  = errors.New("watermark expired")
+)
+
+// Watermark 敏感数据水印
+type Watermark struct {
+	AppID     string `json:"appid"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+type watermarker interface {
+	watermark() *Watermark
+}
+
+// UserInfo wx.getUserInfo 解密后的用户信息
+type UserInfo struct {
+	OpenID    string    `json:"openId"`
+	NickName  string    `json:"nickName"`
+	Gender    int       `json:"gender"`
+	City      string    `json:"city"`
+	Province  string    `json:"province"`
+	Country   string    `json:"country"`
+	AvatarUrl string    `json:"avatarUrl"`
+	UnionID   string    `json:"unionId"`
+	Language  string    `json:"language"`
+	Watermark Watermark `json:"watermark"`
+}
+
+func (u *UserInfo) watermark() *Watermark {
+	return &u.Watermark
+}
+
+// PhoneInfo getPhoneNumber 解密后的手机号
+type PhoneInfo struct {
+	PhoneNumber     string    `json:"phoneNumber"`     // 用户绑定的手机号（国外手机号会有区号）
+	PurePhoneNumber string    `json:"purePhoneNumber"` // 没有区号的手机号
+	CountryCode     string    `json:"countryCode"`     // 区号
+	Watermark       Watermark `json:"watermark"`
+}
+
+func (p *PhoneInfo) watermark() *Watermark {
+	return &p.Watermark
+}
+
+// ShareInfo wx.getShareInfo 解密后的群信息
+type ShareInfo struct {
+	OpenGID   string    `json:"openGId"` // 群对当前小程序的唯一 ID
+	Watermark Watermark `json:"watermark"`
+}
+
+func (s *ShareInfo) watermark() *Watermark {
+	return &s.Watermark
+}
+
+// WeRunData wx.getWeRunData 解密后的微信运动步数
+type WeRunData struct {
+	StepInfoList []struct {
+		Timestamp int64 `json:"timestamp"` // 时间戳，表示数据对应的时间
+		Step      int   `json:"step"`      // 微信运动步数
+	} `json:"stepInfoList"`
+	Watermark Watermark `json:"watermark"`
+}
+
+func (w *WeRunData) watermark() *Watermark {
+	return &w.Watermark
+}
+
+// Session 小程序登录会话, 用于校验/解密前端通过 wx.login 之后获取的开放数据
+// https://developers.weixin.qq.com/miniprogram/dev/framework/open-ability/signature.html
+type Session struct {
+	AppID      string
+	SessionKey string
+	// 水印时间戳的有效期, 为0时不校验
+	WatermarkMaxAge time.Duration
+}
+
+func New(appID, sessionKey string) *Session {
+	return &Session{
+		AppID:      appID,
+		SessionKey: sessionKey,
+	}
+}
+
+// 校验 rawData 签名 signature = sha1( rawData + session_key )
+func (s *Session) CheckSignature(rawData, signature string) error {
+	sum := fmt.Sprintf("%x", sha1.Sum([]byte(rawData+s.SessionKey)))
+	if subtle.ConstantTimeCompare([]byte(sum), []byte(signature)) != 1 {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// 解密 encryptedData, 返回明文 json
+// 对称解密使用的算法为 AES-128-CBC，数据采用PKCS#7填充
+func (s *Session) Decrypt(encryptedData, iv string) ([]byte, error) {
+	aesKey, err := base64.StdEncoding.DecodeString(s.SessionKey)
+	if err != nil || len(aesKey) != 16 {
+		return nil, ErrInvalidSessionKey
+	}
+	aesIV, err := base64.StdEncoding.DecodeString(iv)
+	if err != nil || len(aesIV) != aes.BlockSize {
+		return nil, ErrInvalidIV
+	}
+	cipherText, err := base64.StdEncoding.DecodeString(encryptedData)
+	if err != nil || len(cipherText) == 0 || len(cipherText)%aes.BlockSize != 0 {
+		return nil, ErrInvalidData
+	}
+
+	block, err := aes.NewCipher(aesKey)
+	if err != nil {
+		return nil, err
+	}
+	plaintext := make([]byte, len(cipherText))
+	cipher.NewCBCDecrypter(block, aesIV).CryptBlocks(plaintext, cipherText)
+
+	// PKCS#7 去除补位
+	amountToPad := int(plaintext[len(plaintext)-1])
+	if amountToPad < 1 || amountToPad > aes.BlockSize {
+		return nil, ErrInvalidData
+	}
+	for _, b := range plaintext[len(plaintext)-amountToPad:] {
+		if int(b) != amountToPad {
+			return nil, ErrInvalidData
+		}
+	}
+	return plaintext[:len(plaintext)-amountToPad], nil
+}
+
+// 解密并校验水印
+func (s *Session) decryptTo(encryptedData, iv string, result watermarker) error {
+	plaintext, err := s.Decrypt(encryptedData, iv)
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(plaintext, result); err != nil {
+		return err
+	}
+	return s.CheckWatermark(result.watermark())
+}
+
+// 校验水印的 appid 和 时间戳
+func (s *Session) CheckWatermark(watermark *Watermark) error {
+	if watermark.AppID != s.AppID {
+		return fmt.Errorf("%w, expect %s, got %s", ErrAppIDMismatch, s.AppID, watermark.AppID)
+	}
+	if s.WatermarkMaxAge > 0 {
+		ts := time.Unix(watermark.Timestamp, 0)
+		if time.Since(ts) > s.WatermarkMaxAge {
+			return fmt.Errorf("%w, timestamp %d", ErrWatermarkExpired, watermark.Timestamp)
+		}
+	}
+	return nil
+}
+
+/*
+解密用户信息
+See: https://developers.weixin.qq.com/miniprogram/dev/api/open-api/user-info/wx.getUserInfo.html
+*/
+func (s *Session) DecryptUserInfo(encryptedData, iv string) (*UserInfo, error) {
+	result := &UserInfo{}
+	if err := s.decryptTo(encryptedData, iv, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+/*
+解密手机号
+See: https://developers.weixin.qq.com/miniprogram/dev/framework/open-ability/getPhoneNumber.html
+*/
+func (s *Session) DecryptPhoneInfo(encryptedData, iv string) (*PhoneInfo, error) {
+	result := &PhoneInfo{}
+	if err := s.decryptTo(encryptedData, iv, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+/*
+解密群分享信息
+See: https://developers.weixin.qq.com/miniprogram/dev/api/share/wx.getShareInfo.html
+*/
+func (s *Session) DecryptShareInfo(encryptedData, iv string) (*ShareInfo, error) {
+	result := &ShareInfo{}
+	if err := s.decryptTo(encryptedData, iv, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+/*
+解密微信运动步数
+See: https://developers.weixin.qq.com/miniprogram/dev/api/open-api/werun/wx.getWeRunData.html
+*/
+func (s *Session) DecryptWeRunData(encryptedData, iv string) (*WeRunData, error) {
+	result := &WeRunData{}
+	if err := s.decryptTo(encryptedData, iv, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,107 @@
+package wxa_session
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha1"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lixinio/weixin/test"
+	"github.com/lixinio/weixin/utils/redis"
+	"github.com/lixinio/weixin/weixin/official_account"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testAppID      = "wx4f4bc4dec97d474b"
+	testSessionKey = "tiihtNczf5v6AKRyjwEUhQ=="
+	testIV         = "r7BXXKkLb8qrSNn05n0qiA=="
+)
+
+func encrypt(t *testing.T, plaintext string) string {
+	key, _ := base64.StdEncoding.DecodeString(testSessionKey)
+	iv, _ := base64.StdEncoding.DecodeString(testIV)
+
+	pad := aes.BlockSize - len(plaintext)%aes.BlockSize
+	data := append([]byte(plaintext), bytes.Repeat([]byte{byte(pad)}, pad)...)
+
+	block, err := aes.NewCipher(key)
+	require.Equal(t, nil, err)
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(data, data)
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+func TestDecrypt(t *testing.T) {
+	session := New(testAppID, testSessionKey)
+	now := time.Now().Unix()
+
+	phone, err := session.DecryptPhoneInfo(encrypt(t, fmt.Sprintf(
+		`{"phoneNumber":"13580006666","purePhoneNumber":"13580006666","countryCode":"86","watermark":{"appid":"%s","timestamp":%d}}`,
+		testAppID, now,
+	)), testIV)
+	require.Equal(t, nil, err)
+	require.Equal(t, "13580006666", phone.PurePhoneNumber)
+	require.Equal(t, now, phone.Watermark.Timestamp)
+
+	user, err := session.DecryptUserInfo(encrypt(t, fmt.Sprintf(
+		`{"openId":"oGZUI0egBJY1zhBYw2KhdUfwVJJE","nickName":"Band","gender":1,"unionId":"ocMvos6NjeKLIBqg5Mr9QjxrP1FA","watermark":{"appid":"%s","timestamp":%d}}`,
+		testAppID, now,
+	)), testIV)
+	require.Equal(t, nil, err)
+	require.Equal(t, "Band", user.NickName)
+	require.Equal(t, "ocMvos6NjeKLIBqg5Mr9QjxrP1FA", user.UnionID)
+
+	run, err := session.DecryptWeRunData(encrypt(t, fmt.Sprintf(
+		`{"stepInfoList":[{"timestamp":1445866601,"step":100},{"timestamp":1445876601,"step":120}],"watermark":{"appid":"%s","timestamp":%d}}`,
+		testAppID, now,
+	)), testIV)
+	require.Equal(t, nil, err)
+	require.Equal(t, 2, len(run.StepInfoList))
+	require.Equal(t, 120, run.StepInfoList[1].Step)
+
+	// appid 不匹配
+	_, err = session.DecryptShareInfo(encrypt(t, fmt.Sprintf(
+		`{"openGId":"OPENGID","watermark":{"appid":"other","timestamp":%d}}`, now,
+	)), testIV)
+	require.True(t, errors.Is(err, ErrAppIDMismatch))
+
+	// 水印过期
+	session.WatermarkMaxAge = time.Minute
+	_, err = session.DecryptShareInfo(encrypt(t, fmt.Sprintf(
+		`{"openGId":"OPENGID","watermark":{"appid":"%s","timestamp":%d}}`, testAppID, now-3600,
+	)), testIV)
+	require.True(t, errors.Is(err, ErrWatermarkExpired))
+
+	// 错误的 session key
+	_, err = New(testAppID, "abcd").Decrypt(encrypt(t, "{}"), testIV)
+	require.Equal(t, ErrInvalidSessionKey, err)
+
+	// 错误的 iv 导致补位错误或者 json 无法解析
+	_, err = session.DecryptShareInfo(encrypt(t, "{}"), "AAAAAAAAAAAAAAAAAAAAAA==")
+	require.NotEqual(t, nil, err)
+}
+
+func TestCheckSignature(t *testing.T) {
+	session := New(testAppID, testSessionKey)
+	rawData := `{"nickName":"Band","gender":1}`
+	signature := fmt.Sprintf("%x", sha1.Sum([]byte(rawData+testSessionKey)))
+
+	require.Equal(t, nil, session.CheckSignature(rawData, signature))
+	require.Equal(t, ErrInvalidSignature, session.CheckSignature(rawData+" ", signature))
+}
+
+func TestGetUserPhoneNumber(t *testing.T) {
+	redis := redis.NewRedis(&redis.Config{RedisUrl: test.CacheUrl})
+	wxa := official_account.New(redis, redis, &official_account.Config{
+		Appid:  test.WxaAppid,
+		Secret: test.WxaSecret,
+	})
+	_, err := NewApi(wxa.Client).GetUserPhoneNumber(context.Background(), "code")
+	require.NotEqual(t, nil, err)
+}
@@ -0,0 +1,56 @@
+package wxa_shipping
+
+import (
+	"context"
+	"io"
+)
+
+// OrderIterator 订单列表迭代器, 自动翻页
+type OrderIterator struct {
+	api       *WxaShippingApi
+	params    GetOrderListParams
+	pageIndex string // 获取当前页使用的 last_index
+	orders    []Order
+	done      bool
+}
+
+// 遍历订单列表, params.LastIndex 为上次遍历到的位置, 为空从头开始
+func (api *WxaShippingApi) NewOrderIterator(params *GetOrderListParams) *OrderIterator {
+	it := &OrderIterator{api: api}
+	if params != nil {
+		it.params = *params
+	}
+	return it
+}
+
+// Next 返回下一个订单, 遍历完毕返回 io.EOF
+func (it *OrderIterator) Next(ctx context.Context) (*Order, error) {
+	for len(it.orders) == 0 {
+		if it.done {
+			return nil, io.EOF
+		}
+
+		it.pageIndex = it.params.LastIndex
+		result, err := it.api.GetOrderList(ctx, &it.params)
+		if err != nil {
+			return nil, err
+		}
+		if !result.HasMore || result.LastIndex == "" || result.LastIndex == it.params.LastIndex {
+			it.done = true
+		}
+		it.params.LastIndex = result.LastIndex
+		it.orders = result.OrderList
+	}
+
+	order := it.orders[0]
+	it.orders = it.orders[1:]
+	return &order, nil
+}
+
+// Checkpoint 用于中断之后继续遍历的 last_index, 当前页没有遍历完时重新获取当前页
+func (it *OrderIterator) Checkpoint() string {
+	if len(it.orders) > 0 {
+		return it.pageIndex
+	}
+	return it.params.LastIndex
+}
@@ -0,0 +1,92 @@
+package wxa_shipping
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/lixinio/weixin/utils"
+	"github.com/lixinio/weixin/weixin/server_api"
+	"github.com/stretchr/testify/require"
+)
+
+// 模拟订单列表, last_index 为已返回的数量
+func newOrderServer(total int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		params := &GetOrderListParams{}
+		json.NewDecoder(r.Body).Decode(params)
+		begin, _ := strconv.Atoi(params.LastIndex)
+		end := begin + params.PageSize
+		if end > total {
+			end = total
+		}
+
+		result := &OrderList{LastIndex: strconv.Itoa(end), HasMore: end < total}
+		for i := begin; i < end; i++ {
+			result.OrderList = append(result.OrderList, Order{
+				TransactionID: fmt.Sprintf("42000%05d", i),
+				OrderState:    OrderStateWaitShipping,
+			})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(result)
+	}))
+}
+
+func TestOrderIterator(t *testing.T) {
+	ctx := context.Background()
+	server := newOrderServer(7)
+	defer server.Close()
+	api := NewApi(utils.NewClient(server.URL, utils.StaticClientAccessTokenGetter("token")))
+
+	it := api.NewOrderIterator(&GetOrderListParams{PageSize: 3})
+	for i := 0; i < 4; i++ {
+		order, err := it.Next(ctx)
+		require.Equal(t, nil, err)
+		require.Equal(t, fmt.Sprintf("42000%05d", i), order.TransactionID)
+	}
+	// 第二页还没有遍历完, 从第二页开始
+	require.Equal(t, "3", it.Checkpoint())
+
+	it = api.NewOrderIterator(&GetOrderListParams{PageSize: 3, LastIndex: it.Checkpoint()})
+	count := 3
+	for {
+		order, err := it.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		require.Equal(t, nil, err)
+		require.Equal(t, fmt.Sprintf("42000%05d", count), order.TransactionID)
+		count++
+	}
+	require.Equal(t, 7, count)
+	require.Equal(t, "7", it.Checkpoint())
+}
+
+func TestOrderSettlementEvent(t *testing.T) {
+	body := `<xml>
+	<ToUserName><![CDATA[gh_xxxxxxxxxxxx]]></ToUserName>
+	<FromUserName><![CDATA[oXXXXXXXXXXXXXXXXXXXXXXXXXXX]]></FromUserName>
+	<CreateTime>1680000000</CreateTime>
+	<MsgType><![CDATA[event]]></MsgType>
+	<Event><![CDATA[trade_manage_order_settlement]]></Event>
+	<transaction_id><![CDATA[4200000000000000000000000000]]></transaction_id>
+	<merchant_id><![CDATA[1230000109]]></merchant_id>
+	<merchant_trade_no><![CDATA[20230101000001]]></merchant_trade_no>
+	<pay_time>1680000000</pay_time>
+	<confirm_receive_method>2</confirm_receive_method>
+	<settlement_time>1680086400</settlement_time>
+</xml>`
+	event := &server_api.EventTradeManageOrderSettlement{}
+	require.Equal(t, nil, xml.Unmarshal([]byte(body), event))
+	require.Equal(t, server_api.EventTypeTradeManageOrderSettlement, event.Event.Event)
+	require.Equal(t, "4200000000000000000000000000", event.TransactionID)
+	require.Equal(t, 2, event.ConfirmReceiveMethod)
+	require.Equal(t, int64(1680086400), event.SettlementTime)
+}
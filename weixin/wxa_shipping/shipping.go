@@ -0,0 +1,267 @@
+// 小程序发货信息管理服务
+// https://developers.weixin.qq.com/miniprogram/dev/platform-capabilities/business-capabilities/order-shipping/order-shipping.html
+
+package wxa_shipping
+
+import (
+	"context"
+
+	"github.com/lixinio/weixin/utils"
+)
+
+const (
+	apiUploadShippingInfo     = "/wxa/sec/order/upload_shipping_info"
+	apiUploadCombinedShipping = "/wxa/sec/order/upload_combined_shipping_info"
+	apiGetOrder               = "/wxa/sec/order/get_order"
+	apiGetOrderList           = "/wxa/sec/order/get_order_list"
+	apiNotifyConfirmReceive   = "/wxa/sec/order/notify_confirm_receive"
+	apiSetMsgJumpPath         = "/wxa/sec/order/set_msg_jump_path"
+	apiIsTradeManaged         = "/wxa/sec/order/is_trade_managed"
+	apiIsTradeManageCompleted = "/wxa/sec/order/is_trade_management_confirmation_completed"
+)
+
+// 订单单号类型
+const (
+	OrderNumberTypeMerchant    = 1 // 使用下单商户号和商户侧单号
+	OrderNumberTypeTransaction = 2 // 使用微信支付单号
+)
+
+// 物流模式
+const (
+	LogisticsTypeExpress = 1 // 实体物流配送采用快递公司进行实体物流配送形式
+	LogisticsTypeLocal   = 2 // 同城配送
+	LogisticsTypeVirtual = 3 // 虚拟商品，虚拟商品，例如话费充值，点卡等，无实体配送形式
+	LogisticsTypePickup  = 4 // 用户自提
+)
+
+// 发货模式
+const (
+	DeliveryModeUnified = 1 // 统一发货
+	DeliveryModeSplit   = 2 // 分拆发货
+)
+
+// 订单状态
+const (
+	OrderStateWaitShipping = 1 // 待发货
+	OrderStateShipped      = 2 // 已发货
+	OrderStateReceived     = 3 // 确认收货
+	OrderStateCompleted    = 4 // 交易完成
+	OrderStateRefunded     = 5 // 已退款
+	OrderStateSettling     = 6 // 资金待结算
+)
+
+type WxaShippingApi struct {
+	*utils.Client
+}
+
+func NewApi(client *utils.Client) *WxaShippingApi {
+	return &WxaShippingApi{Client: client}
+}
+
+// OrderKey 订单, 通过微信支付单号或者商户号+商户单号确定
+type OrderKey struct {
+	OrderNumberType int    `json:"order_number_type"` // OrderNumberType*
+	TransactionID   string `json:"transaction_id,omitempty"`
+	Mchid           string `json:"mchid,omitempty"`
+	OutTradeNo      string `json:"out_trade_no,omitempty"`
+}
+
+type ShippingContact struct {
+	ConsignorContact string `json:"consignor_contact,omitempty"` // 寄件人联系方式，采用掩码传输，最后4位数字不能打掩码
+	ReceiverContact  string `json:"receiver_contact,omitempty"`  // 收件人联系方式
+}
+
+type Shipping struct {
+	TrackingNo     string           `json:"tracking_no,omitempty"`     // 物流单号，物流快递发货时必填
+	ExpressCompany string           `json:"express_company,omitempty"` // 物流公司编码，快递公司ID
+	ItemDesc       string           `json:"item_desc"`                 // 商品信息，例如：微信红包抱枕*1个，限120个字以内
+	Contact        *ShippingContact `json:"contact,omitempty"`         // 顺丰必填
+}
+
+type ShippingInfo struct {
+	OrderKey       OrderKey   `json:"order_key"`
+	LogisticsType  int        `json:"logistics_type"`             // LogisticsType*
+	DeliveryMode   int        `json:"delivery_mode"`              // DeliveryMode*
+	IsAllDelivered bool       `json:"is_all_delivered,omitempty"` // 分拆发货模式时必填，用于标识分拆发货模式下是否已全部发货完成
+	ShippingList   []Shipping `json:"shipping_list"`              // 物流信息列表，最多10个
+	UploadTime     string     `json:"upload_time"`                // 上传时间，RFC 3339 格式，例如 2022-12-15T13:29:35.120+08:00
+	Payer          struct {
+		OpenID string `json:"openid"` // 支付者openid
+	} `json:"payer"`
+}
+
+/*
+发货信息录入
+https://developers.weixin.qq.com/miniprogram/dev/platform-capabilities/business-capabilities/order-shipping/order-shipping.html#一、发货信息录入接口
+POST https://api.weixin.qq.com/wxa/sec/order/upload_shipping_info?access_token=ACCESS_TOKEN
+*/
+func (api *WxaShippingApi) UploadShippingInfo(ctx context.Context, info *ShippingInfo) error {
+	return api.Client.HTTPPostJson(ctx, apiUploadShippingInfo, info, nil)
+}
+
+type SubOrderShippingInfo struct {
+	OrderKey       OrderKey   `json:"order_key"`
+	LogisticsType  int        `json:"logistics_type"` // LogisticsType*
+	DeliveryMode   int        `json:"delivery_mode"`  // DeliveryMode*
+	IsAllDelivered bool       `json:"is_all_delivered,omitempty"`
+	ShippingList   []Shipping `json:"shipping_list"`
+}
+
+type CombinedShippingInfo struct {
+	OrderKey   OrderKey               `json:"order_key"` // 合单订单
+	SubOrders  []SubOrderShippingInfo `json:"sub_orders"`
+	UploadTime string                 `json:"upload_time"`
+	Payer      struct {
+		OpenID string `json:"openid"`
+	} `json:"payer"`
+}
+
+/*
+发货信息合单录入
+POST https://api.weixin.qq.com/wxa/sec/order/upload_combined_shipping_info?access_token=ACCESS_TOKEN
+*/
+func (api *WxaShippingApi) UploadCombinedShippingInfo(ctx context.Context, info *CombinedShippingInfo) error {
+	return api.Client.HTTPPostJson(ctx, apiUploadCombinedShipping, info, nil)
+}
+
+type OrderShippingItem struct {
+	TrackingNo     string           `json:"tracking_no"`
+	ExpressCompany string           `json:"express_company"`
+	GoodsDesc      string           `json:"goods_desc"`
+	UploadTime     int64            `json:"upload_time"`
+	Contact        *ShippingContact `json:"contact"`
+}
+
+type Order struct {
+	TransactionID   string `json:"transaction_id"`
+	MerchantID      string `json:"merchant_id"`
+	SubMerchantID   string `json:"sub_merchant_id"`
+	MerchantTradeNo string `json:"merchant_trade_no"`
+	Description     string `json:"description"`       // 支付单对应的商品描述
+	PaidAmount      int    `json:"paid_amount"`       // 支付单实际支付金额，整型，单位：分钱
+	OpenID          string `json:"openid"`            // 支付者openid
+	TradeCreateTime int64  `json:"trade_create_time"` // 交易创建时间
+	PayTime         int64  `json:"pay_time"`          // 支付时间
+	OrderState      int    `json:"order_state"`       // OrderState*
+	InComplaint     bool   `json:"in_complaint"`      // 是否处在交易纠纷中
+	Shipping        struct {
+		DeliveryMode        int                 `json:"delivery_mode"`
+		LogisticsType       int                 `json:"logistics_type"`
+		FinishShipping      bool                `json:"finish_shipping"`       // 是否已完成全部发货
+		GoodsDesc           string              `json:"goods_desc"`            // 在小程序后台发货信息录入页录入的商品描述
+		FinishShippingCount int                 `json:"finish_shipping_count"` // 已完成全部发货的次数，未完成时为 0，完成时为 1，重新发货并完成后为 2
+		ShippingList        []OrderShippingItem `json:"shipping_list"`
+	} `json:"shipping"`
+}
+
+// GetOrderParams 查询订单, 微信支付单号和商户号+商户单号二选一
+type GetOrderParams struct {
+	TransactionID   string `json:"transaction_id,omitempty"`
+	MerchantID      string `json:"merchant_id,omitempty"`
+	SubMerchantID   string `json:"sub_merchant_id,omitempty"`
+	MerchantTradeNo string `json:"merchant_trade_no,omitempty"`
+}
+
+/*
+查询订单发货状态
+POST https://api.weixin.qq.com/wxa/sec/order/get_order?access_token=ACCESS_TOKEN
+*/
+func (api *WxaShippingApi) GetOrder(ctx context.Context, params *GetOrderParams) (*Order, error) {
+	result := &struct {
+		utils.WeixinError
+		Order Order `json:"order"`
+	}{}
+	if err := api.Client.HTTPPostJson(ctx, apiGetOrder, params, result); err != nil {
+		return nil, err
+	}
+	return &result.Order, nil
+}
+
+type TimeRange struct {
+	BeginTime int64 `json:"begin_time,omitempty"` // 起始时间，时间戳形式，不填则视为从0开始
+	EndTime   int64 `json:"end_time,omitempty"`   // 结束时间（含），时间戳形式，不填则视为32位无符号整型的最大值
+}
+
+type GetOrderListParams struct {
+	PayTimeRange *TimeRange `json:"pay_time_range,omitempty"` // 支付时间所属范围
+	OrderState   int        `json:"order_state,omitempty"`    // OrderState*, 0 不过滤
+	OpenID       string     `json:"openid,omitempty"`         // 支付者openid
+	LastIndex    string     `json:"last_index,omitempty"`     // 翻页时使用，获取第一页时不用传入，如果查询结果中 has_more 字段为 true，则传入该次查询结果中返回的 last_index 字段可获取下一页
+	PageSize     int        `json:"page_size,omitempty"`      // 翻页时使用，返回列表的长度，默认为100
+}
+
+type OrderList struct {
+	utils.WeixinError
+	LastIndex string  `json:"last_index"`
+	HasMore   bool    `json:"has_more"`
+	OrderList []Order `json:"order_list"`
+}
+
+/*
+查询订单列表
+POST https://api.weixin.qq.com/wxa/sec/order/get_order_list?access_token=ACCESS_TOKEN
+*/
+func (api *WxaShippingApi) GetOrderList(ctx context.Context, params *GetOrderListParams) (*OrderList, error) {
+	result := &OrderList{}
+	if err := api.Client.HTTPPostJson(ctx, apiGetOrderList, params, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type ConfirmReceiveParams struct {
+	GetOrderParams
+	ReceivedTime int64 `json:"received_time"` // 快递签收时间，时间戳形式
+}
+
+/*
+确认收货提醒, 只能在快递签收之后调用一次
+POST https://api.weixin.qq.com/wxa/sec/order/notify_confirm_receive?access_token=ACCESS_TOKEN
+*/
+func (api *WxaShippingApi) NotifyConfirmReceive(ctx context.Context, params *ConfirmReceiveParams) error {
+	return api.Client.HTTPPostJson(ctx, apiNotifyConfirmReceive, params, nil)
+}
+
+/*
+消息跳转路径设置, 用户点击发货消息时跳转的小程序页面
+POST https://api.weixin.qq.com/wxa/sec/order/set_msg_jump_path?access_token=ACCESS_TOKEN
+*/
+func (api *WxaShippingApi) SetMsgJumpPath(ctx context.Context, path string) error {
+	return api.Client.HTTPPostJson(ctx, apiSetMsgJumpPath, map[string]string{
+		"path": path,
+	}, nil)
+}
+
+/*
+查询小程序是否已开通发货信息管理服务
+POST https://api.weixin.qq.com/wxa/sec/order/is_trade_managed?access_token=ACCESS_TOKEN
+*/
+func (api *WxaShippingApi) IsTradeManaged(ctx context.Context, appid string) (bool, error) {
+	result := &struct {
+		utils.WeixinError
+		IsTradeManaged bool `json:"is_trade_managed"`
+	}{}
+	if err := api.Client.HTTPPostJson(ctx, apiIsTradeManaged, map[string]string{
+		"appid": appid,
+	}, result); err != nil {
+		return false, err
+	}
+	return result.IsTradeManaged, nil
+}
+
+/*
+查询小程序是否已完成交易结算管理确认
+POST https://api.weixin.qq.com/wxa/sec/order/is_trade_management_confirmation_completed?access_token=ACCESS_TOKEN
+*/
+func (api *WxaShippingApi) IsTradeManagementConfirmationCompleted(ctx context.Context, appid string) (bool, error) {
+	result := &struct {
+		utils.WeixinError
+		Completed bool `json:"completed"`
+	}{}
+	if err := api.Client.HTTPPostJson(ctx, apiIsTradeManageCompleted, map[string]string{
+		"appid": appid,
+	}, result); err != nil {
+		return false, err
+	}
+	return result.Completed, nil
+}
@@ -0,0 +1,44 @@
+package wxa_shipping
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lixinio/weixin/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUploadCombinedShippingInfo(t *testing.T) {
+	var payload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, apiUploadCombinedShipping, r.URL.Path)
+		json.NewDecoder(r.Body).Decode(&payload)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	}))
+	defer server.Close()
+	api := NewApi(utils.NewClient(server.URL, utils.StaticClientAccessTokenGetter("token")))
+
+	info := &CombinedShippingInfo{
+		OrderKey: OrderKey{OrderNumberType: OrderNumberTypeMerchant, Mchid: "1230000109", OutTradeNo: "combined"},
+		SubOrders: []SubOrderShippingInfo{{
+			OrderKey:      OrderKey{OrderNumberType: OrderNumberTypeMerchant, Mchid: "1230000109", OutTradeNo: "sub"},
+			LogisticsType: LogisticsTypeVirtual,
+			DeliveryMode:  DeliveryModeUnified,
+			ShippingList:  []Shipping{{ItemDesc: "话费充值"}},
+		}},
+		UploadTime: "2022-12-15T13:29:35.120+08:00",
+	}
+	info.Payer.OpenID = "openid"
+	require.Equal(t, nil, api.UploadCombinedShippingInfo(context.Background(), info))
+
+	subOrders := payload["sub_orders"].([]interface{})
+	require.Equal(t, 1, len(subOrders))
+	subOrder := subOrders[0].(map[string]interface{})
+	require.Equal(t, float64(LogisticsTypeVirtual), subOrder["logistics_type"])
+	require.Equal(t, float64(DeliveryModeUnified), subOrder["delivery_mode"])
+	require.Equal(t, "sub", subOrder["order_key"].(map[string]interface{})["out_trade_no"])
+}
@@ -0,0 +1,151 @@
+package wxopen
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/lixinio/weixin/utils"
+	"github.com/lixinio/weixin/weixin/server_api"
+)
+
+var ErrAuthorizerNotFound = errors.New("authorizer not found")
+
+// AuthorizerHandlerFunc 处理授权帐号的消息/事件
+// serverApi 使用服务商的 Token/EncodingAESKey, 用于解析消息以及构造(加密)回复
+type AuthorizerHandlerFunc func(
+	w http.ResponseWriter,
+	r *http.Request,
+	serverApi *server_api.ServerApi,
+	body []byte,
+) error
+
+// AuthorizerTenant 授权帐号对应的租户
+type AuthorizerTenant struct {
+	Appid   string
+	Client  *utils.Client // 授权帐号的 client(例如 authorizer.Authorizer.Client), 可选
+	Handler AuthorizerHandlerFunc
+}
+
+// AuthorizerResolver 根据授权帐号查找租户
+// appid 来自消息回调地址(可能为空), userName 为消息的 ToUserName(原始ID)
+// 未授权/未知的帐号返回 ErrAuthorizerNotFound
+type AuthorizerResolver interface {
+	ResolveAuthorizer(ctx context.Context, appid, userName string) (*AuthorizerTenant, error)
+}
+
+type AuthorizerResolverFunc func(ctx context.Context, appid, userName string) (*AuthorizerTenant, error)
+
+func (f AuthorizerResolverFunc) ResolveAuthorizer(
+	ctx context.Context, appid, userName string,
+) (*AuthorizerTenant, error) {
+	return f(ctx, appid, userName)
+}
+
+// AuthorizerRouterConfig 授权帐号消息路由配置
+type AuthorizerRouterConfig struct {
+	Resolver AuthorizerResolver
+	// 从请求中获取授权帐号的 appid, 默认取路径的最后一段, 例如 /callback/$APPID$
+	AppidFromRequest func(r *http.Request) string
+	// 全网发布检测配置, 为空时不处理测试帐号的消息
+	Release *ReleaseConfig
+	// ServeHTTP 处理失败时回调, 可选
+	OnError func(r *http.Request, err error)
+}
+
+// AuthorizerRouter 授权帐号消息与事件的统一入口 (消息与事件接收URL)
+// 使用服务商的凭证验证签名和解密, 然后根据 appid 分发给租户的处理函数
+// https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/api/Before_Develop/Message_encryption_and_decryption.html
+type AuthorizerRouter struct {
+	wxopen  *WxOpen
+	config  *AuthorizerRouterConfig
+	handler utils.XmlHandlerFunc
+}
+
+func (api *WxOpen) NewAuthorizerRouter(config *AuthorizerRouterConfig) *AuthorizerRouter {
+	if config == nil || config.Resolver == nil {
+		panic("authorizer router resolver is required")
+	}
+
+	router := &AuthorizerRouter{wxopen: api, config: config}
+	router.handler = router.dispatch
+	if config.Release != nil {
+		// 测试帐号不属于任何租户, 在查找租户之前处理
+		router.handler = api.ReleaseMiddleware(
+			router.newServerApi(nil), config.Release,
+		)(router.dispatch)
+	}
+	return router
+}
+
+func (router *AuthorizerRouter) newServerApi(client *utils.Client) *server_api.ServerApi {
+	return server_api.NewApi(
+		router.wxopen.Config.Appid, // 回复加密使用服务商的AppID
+		router.wxopen.Config.Token,
+		router.wxopen.Config.EncodingAESKey,
+		client,
+	)
+}
+
+func (router *AuthorizerRouter) appid(r *http.Request) string {
+	if router.config.AppidFromRequest != nil {
+		return router.config.AppidFromRequest(r)
+	}
+	path := strings.TrimRight(r.URL.Path, "/")
+	return path[strings.LastIndex(path, "/")+1:]
+}
+
+func (router *AuthorizerRouter) dispatch(
+	w http.ResponseWriter, r *http.Request, body []byte,
+) error {
+	message := &server_api.Message{}
+	if err := xml.Unmarshal(body, message); err != nil {
+		utils.HttpAbortBadRequest(w)
+		return err
+	}
+
+	appid := router.appid(r)
+	tenant, err := router.config.Resolver.ResolveAuthorizer(
+		r.Context(), appid, message.ToUserName,
+	)
+	if err != nil {
+		if errors.Is(err, ErrAuthorizerNotFound) {
+			// 避免微信重试, 仍然回复 success
+			io.WriteString(w, "success")
+		} else {
+			utils.HttpAbort(w, http.StatusInternalServerError)
+		}
+		return fmt.Errorf(
+			"resolve authorizer appid '%s' username '%s', error %w",
+			appid, message.ToUserName, err,
+		)
+	}
+	if tenant == nil || tenant.Handler == nil {
+		io.WriteString(w, "success")
+		return fmt.Errorf(
+			"authorizer appid '%s' username '%s' without handler, error %w",
+			appid, message.ToUserName, ErrAuthorizerNotFound,
+		)
+	}
+
+	return tenant.Handler(w, r, router.newServerApi(tenant.Client), body)
+}
+
+// ServeData 验证签名并解密(只解密一次), 然后分发给租户处理
+func (router *AuthorizerRouter) ServeData(w http.ResponseWriter, r *http.Request) error {
+	return router.wxopen.ServeData(w, r, router.handler)
+}
+
+func (router *AuthorizerRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		utils.HttpAbortBadRequest(w)
+		return
+	}
+	if err := router.ServeData(w, r); err != nil && router.config.OnError != nil {
+		router.config.OnError(r, err)
+	}
+}
@@ -0,0 +1,119 @@
+package wxopen
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/lixinio/weixin/utils"
+	"github.com/lixinio/weixin/weixin/server_api"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	routerComponentAppid = "wx_component"
+	routerToken          = "router_token"
+	routerAESKey         = "abcdefghijklmnopqrstuvwxyz0123456789ABCDEFG"
+)
+
+func routerRequest(t *testing.T, path, toUser, content string) *http.Request {
+	body := fmt.Sprintf(`<xml>
+  <ToUserName><![CDATA[%s]]></ToUserName>
+  <FromUserName><![CDATA[openid]]></FromUserName>
+  <CreateTime>1413192605</CreateTime>
+  <MsgType><![CDATA[text]]></MsgType>
+  <Content><![CDATA[%s]]></Content>
+</xml>`, toUser, content)
+	encrypt, err := utils.AESEncryptMsg(
+		[]byte("1234567890123456"), []byte(body), routerComponentAppid, routerAESKey,
+	)
+	require.Equal(t, nil, err)
+
+	query := url.Values{}
+	query.Set("timestamp", "1413192605")
+	query.Set("nonce", "nonce")
+	query.Set("encrypt_type", "aes")
+	query.Set("msg_signature", utils.CalcSignature(routerToken, "1413192605", "nonce", encrypt))
+	return httptest.NewRequest(
+		http.MethodPost,
+		path+"?"+query.Encode(),
+		strings.NewReader(fmt.Sprintf(
+			"<xml><ToUserName><![CDATA[%s]]></ToUserName><Encrypt><![CDATA[%s]]></Encrypt></xml>",
+			toUser, encrypt,
+		)),
+	)
+}
+
+func TestAuthorizerRouter(t *testing.T) {
+	api := &WxOpen{Config: &Config{
+		Appid:          routerComponentAppid,
+		Token:          routerToken,
+		EncodingAESKey: routerAESKey,
+	}}
+
+	tenantHandler := func(
+		w http.ResponseWriter, r *http.Request, serverApi *server_api.ServerApi, body []byte,
+	) error {
+		require.Equal(t, routerComponentAppid, serverApi.AppID)
+		msg := &server_api.MessageText{}
+		if err := xml.Unmarshal(body, msg); err != nil {
+			return err
+		}
+		return serverApi.ResponseText(w, r, &server_api.ReplyMessageText{
+			ReplyMessage: *msg.Reply(),
+			Content:      server_api.CDATA("tenant:" + msg.Content),
+		})
+	}
+
+	var errs []error
+	router := api.NewAuthorizerRouter(&AuthorizerRouterConfig{
+		Resolver: AuthorizerResolverFunc(func(
+			ctx context.Context, appid, userName string,
+		) (*AuthorizerTenant, error) {
+			if appid == "wx_tenant" || userName == "gh_tenant" {
+				return &AuthorizerTenant{Appid: "wx_tenant", Handler: tenantHandler}, nil
+			}
+			return nil, ErrAuthorizerNotFound
+		}),
+		Release: &ReleaseConfig{Apps: ReleaseApps{"wx_test": {UserName: "gh_test"}}},
+		OnError: func(r *http.Request, err error) { errs = append(errs, err) },
+	})
+
+	decrypt := func(w *httptest.ResponseRecorder) string {
+		reply := &server_api.ReplyEncryptMessage{}
+		require.Equal(t, nil, xml.Unmarshal(w.Body.Bytes(), reply))
+		_, body, appid, err := utils.AESDecryptMsg(string(reply.Encrypt), routerAESKey)
+		require.Equal(t, nil, err)
+		require.Equal(t, routerComponentAppid, string(appid))
+		return string(body)
+	}
+
+	// 路径中的 appid
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, routerRequest(t, "/callback/wx_tenant", "gh_tenant", "hello"))
+	require.True(t, strings.Contains(decrypt(w), "tenant:hello"))
+
+	// 全网发布测试帐号
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, routerRequest(t, "/callback/wx_test", "gh_test", releaseTestText))
+	require.True(t, strings.Contains(decrypt(w), releaseTestText+"_callback"))
+
+	// 未知帐号
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, routerRequest(t, "/callback/wx_unknown", "gh_unknown", "hello"))
+	require.Equal(t, "success", w.Body.String())
+	require.Equal(t, 1, len(errs))
+	require.True(t, errors.Is(errs[0], ErrAuthorizerNotFound))
+
+	// 路径中没有 appid 时使用 ToUserName
+	router.config.AppidFromRequest = func(r *http.Request) string { return "" }
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, routerRequest(t, "/callback", "gh_tenant", "world"))
+	require.True(t, strings.Contains(decrypt(w), "tenant:world"))
+}
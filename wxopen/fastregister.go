@@ -0,0 +1,153 @@
+package wxopen
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/lixinio/weixin/utils"
+)
+
+const (
+	apiFastRegisterWeapp         = "/cgi-bin/component/fastregisterweapp"
+	apiFastRegisterPersonalWeapp = "/wxa/component/fastregisterpersonalweapp"
+	apiFastRegisterBetaWeapp     = "/wxa/component/fastregisterbetaweapp"
+	fastRegisterAuthUrl          = "https://mp.weixin.qq.com/cgi-bin/fastregisterauth"
+)
+
+// 企业代码类型
+const (
+	CodeTypeCreditCode = 1 // 统一社会信用代码（18 位）
+	CodeTypeOrgCode    = 2 // 组织机构代码（9 位 xxxxxxxx-x）
+	CodeTypeLicense    = 3 // 营业执照注册号(15 位)
+)
+
+type FastRegisterWeappParams struct {
+	Name               string `json:"name"`                 // 企业名（需与工商部门登记信息一致）
+	Code               string `json:"code"`                 // 企业代码
+	CodeType           int    `json:"code_type"`            // 企业代码类型 CodeType*
+	LegalPersonaWechat string `json:"legal_persona_wechat"` // 法人微信号
+	LegalPersonaName   string `json:"legal_persona_name"`   // 法人姓名（绑定银行卡）
+	ComponentPhone     string `json:"component_phone"`      // 第三方联系电话
+}
+
+/*
+快速注册企业小程序
+法人微信确认之后, 结果通过 EventFastRegister 推送, 其中的 auth_code 用于 QueryAuth 获取授权信息
+https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/api/Register_Mini_Programs/Fast_Registration_Interface_document.html
+POST https://api.weixin.qq.com/cgi-bin/component/fastregisterweapp?action=create&component_access_token=TOKEN
+*/
+func (api *WxOpen) FastRegisterWeapp(ctx context.Context, params *FastRegisterWeappParams) error {
+	return api.Client.HTTPPost(ctx, apiFastRegisterWeapp, params, func(querys url.Values) {
+		querys.Add("action", "create")
+	}, nil, "")
+}
+
+/*
+查询创建任务状态
+https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/api/Register_Mini_Programs/Fast_Registration_Interface_document.html
+POST https://api.weixin.qq.com/cgi-bin/component/fastregisterweapp?action=search&component_access_token=TOKEN
+*/
+func (api *WxOpen) SearchFastRegisterWeapp(
+	ctx context.Context, name, legalPersonaWechat, legalPersonaName string,
+) error {
+	return api.Client.HTTPPost(ctx, apiFastRegisterWeapp, map[string]string{
+		"name":                 name,
+		"legal_persona_wechat": legalPersonaWechat,
+		"legal_persona_name":   legalPersonaName,
+	}, func(params url.Values) {
+		params.Add("action", "search")
+	}, nil, "")
+}
+
+type FastRegisterPersonalParams struct {
+	IDName         string `json:"idname"`                    // 个人用户名字
+	WxUser         string `json:"wxuser"`                    // 个人用户微信号
+	ComponentPhone string `json:"component_phone,omitempty"` // 第三方联系电话
+}
+
+type FastRegisterPersonalResult struct {
+	utils.WeixinError
+	TaskID       string `json:"taskid"`        // 任务 id
+	AuthorizeUrl string `json:"authorize_url"` // 给用户扫码认证的验证 url
+	Status       int    `json:"status"`        // 任务的状态, 仅查询返回
+}
+
+/*
+快速注册个人小程序
+https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/api/Register_Mini_Programs/fastregisterpersonalweapp.html
+POST https://api.weixin.qq.com/wxa/component/fastregisterpersonalweapp?action=create&component_access_token=TOKEN
+*/
+func (api *WxOpen) FastRegisterPersonalWeapp(
+	ctx context.Context, params *FastRegisterPersonalParams,
+) (*FastRegisterPersonalResult, error) {
+	result := &FastRegisterPersonalResult{}
+	if err := api.Client.HTTPPost(ctx, apiFastRegisterPersonalWeapp, params, func(querys url.Values) {
+		querys.Add("action", "create")
+	}, result, ""); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+/*
+查询个人小程序创建任务状态
+https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/api/Register_Mini_Programs/fastregisterpersonalweapp.html
+POST https://api.weixin.qq.com/wxa/component/fastregisterpersonalweapp?action=query&component_access_token=TOKEN
+*/
+func (api *WxOpen) QueryFastRegisterPersonalWeapp(
+	ctx context.Context, taskID string,
+) (*FastRegisterPersonalResult, error) {
+	result := &FastRegisterPersonalResult{}
+	if err := api.Client.HTTPPost(ctx, apiFastRegisterPersonalWeapp, map[string]string{
+		"taskid": taskID,
+	}, func(params url.Values) {
+		params.Add("action", "query")
+	}, result, ""); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type FastRegisterBetaResult struct {
+	utils.WeixinError
+	UniqueID     string `json:"unique_id"`     // 该请求的唯一标识符，用于关联微信用户和后面产生的 appid
+	AuthorizeUrl string `json:"authorize_url"` // 用户授权确认 url，需将该 url 发送给用户，用户进入授权页面完成授权方可创建小程序
+}
+
+/*
+创建试用小程序
+无需主体信息, 用户在微信中确认之后结果通过 EventFastRegisterBeta 推送
+https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/api/beta_Mini_Programs/fastregister.html
+POST https://api.weixin.qq.com/wxa/component/fastregisterbetaweapp?access_token=COMPONENT_ACCESS_TOKEN
+*/
+func (api *WxOpen) FastRegisterBetaWeapp(
+	ctx context.Context, name, openID string,
+) (*FastRegisterBetaResult, error) {
+	result := &FastRegisterBetaResult{}
+	if err := api.tokenClient.HTTPPostJson(ctx, apiFastRegisterBetaWeapp, map[string]string{
+		"name":   name,
+		"openid": openID,
+	}, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+/*
+复用公众号主体快速注册小程序 的授权页面地址
+公众号管理员扫码确认之后跳转到 redirectUri 并带上 ticket 参数, 然后调用公众号的 authorizer.AuthorizerApi.FastRegister 完成注册
+copyWxVerify 为 true 时复用公众号的微信认证(需要公众号已认证)
+https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/api/Official_Accounts/fast_registration_of_mini_program.html
+*/
+func (api *WxOpen) FastRegisterAuthUrl(oaAppID string, copyWxVerify bool, redirectUri string) string {
+	params := url.Values{}
+	params.Add("appid", oaAppID)
+	params.Add("component_appid", api.Config.Appid)
+	if copyWxVerify {
+		params.Add("copy_wx_verify", "1")
+	} else {
+		params.Add("copy_wx_verify", "0")
+	}
+	params.Add("redirect_uri", redirectUri)
+	return fastRegisterAuthUrl + "?" + params.Encode()
+}
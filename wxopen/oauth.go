@@ -14,6 +14,7 @@ const (
 	apiAccessToken  = "/sns/oauth2/component/access_token"
 	apiRefreshToken = "/sns/oauth2/component/refresh_token"
 	apiUserInfo     = "/sns/userinfo"
+	apiAuth         = "/sns/auth"
 )
 
 // 代公众号发起网页授权
@@ -42,6 +43,7 @@ type OauthAccessToken struct {
 	RefreshToken string `json:"refresh_token"`
 	Openid       string `json:"openid"`
 	Scope        string `json:"scope"`
+	Unionid      string `json:"unionid"` // 公众号绑定到开放平台帐号之后才有
 }
 
 // 通过 code 换取 access_token
@@ -112,3 +114,13 @@ func (api *WxOpen) GetUserInfo(
 	}
 	return result, nil
 }
+
+// 检验授权凭证（access_token）是否有效
+func (api *WxOpen) Auth(
+	ctx context.Context, accessToken string, openid string,
+) error {
+	return api.Client.HTTPGetToken(ctx, apiAuth, func(params url.Values) {
+		params.Add("access_token", accessToken)
+		params.Add("openid", openid)
+	}, &utils.WeixinError{})
+}
@@ -0,0 +1,55 @@
+package wxopen
+
+import (
+	"context"
+
+	"github.com/lixinio/weixin/utils/oauthflow"
+)
+
+const scopeSnsapiUserinfo = "snsapi_userinfo"
+
+type oauthProvider struct {
+	wxopen          *WxOpen
+	authorizerAppID string
+	scope           string
+}
+
+// OAuthProvider 第三方平台代公众号发起网页授权, 用于 oauthflow
+func (api *WxOpen) OAuthProvider(authorizerAppID, scope string) oauthflow.Provider {
+	return &oauthProvider{
+		wxopen:          api,
+		authorizerAppID: authorizerAppID,
+		scope:           scope,
+	}
+}
+
+func (p *oauthProvider) AuthorizeUrl(redirectUri, state string) string {
+	return p.wxopen.GetAuthorizeUrl(
+		p.authorizerAppID, redirectUri, p.scope, state,
+	) + "#wechat_redirect"
+}
+
+func (p *oauthProvider) Exchange(ctx context.Context, code string) (*oauthflow.Identity, error) {
+	token, err := p.wxopen.GetSnsAccessToken(ctx, p.authorizerAppID, code)
+	if err != nil {
+		return nil, err
+	}
+
+	identity := &oauthflow.Identity{
+		AppID:        p.authorizerAppID,
+		OpenID:       token.Openid,
+		UnionID:      token.Unionid,
+		Scope:        token.Scope,
+		AccessToken:  token.AccessToken,
+		RefreshToken: token.RefreshToken,
+		ExpiresIn:    token.ExpiresIn,
+	}
+	if p.scope == scopeSnsapiUserinfo {
+		userInfo, err := p.wxopen.GetUserInfo(ctx, token.AccessToken, token.Openid, LANG_zh_CN)
+		if err != nil {
+			return nil, err
+		}
+		identity.UnionID = userInfo.Unionid
+	}
+	return identity, nil
+}
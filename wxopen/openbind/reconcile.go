@@ -0,0 +1,136 @@
+// 开放平台帐号绑定对账: 遍历第三方平台的授权帐号, 找出没有绑定到指定开放平台帐号的公众号/小程序
+
+package openbind
+
+import (
+	"context"
+	"sort"
+
+	"github.com/lixinio/weixin/wxopen"
+)
+
+const maxAuthorizerPageSize = 500 // api_get_authorizer_list 每页最多500个
+
+// Api 授权帐号的开放平台帐号接口, authorizer.AuthorizerApi 实现了该接口
+type Api interface {
+	GetOpenAccount(ctx context.Context, appid string) (string, error)
+	BindOpenAccount(ctx context.Context, appid, openAppid string) error
+}
+
+// AuthorizerLister 授权帐号列表, wxopen.WxOpen 实现了该接口
+type AuthorizerLister interface {
+	GetAuthorizerList(ctx context.Context, offset, count int) ([]wxopen.AuthorizationLite, error)
+}
+
+type Config struct {
+	Authorizers AuthorizerLister // 必填
+	// 根据 appid 获取授权帐号的 Api, 必填
+	Api       func(appid string) Api
+	OpenAppid string // 目标开放平台帐号, 必填, 可以通过 AuthorizerApi.CreateOpenAccount 创建
+	Bind      bool   // 是否将未绑定的帐号绑定到 OpenAppid, 为 false 时只生成报告
+	PageSize  int    // 每页获取的授权帐号数量, 默认500
+}
+
+// Mismatch 已经绑定到其他开放平台帐号的授权帐号, 需要人工解绑之后才能绑定
+type Mismatch struct {
+	Appid     string
+	OpenAppid string
+}
+
+type Failure struct {
+	Appid string
+	Error string
+}
+
+// Report 对账结果, appid 按字典序排列
+type Report struct {
+	Total      int
+	Bound      []string   // 已经绑定到目标开放平台帐号
+	Unbound    []string   // 未绑定任何开放平台帐号(Bind 为 true 且绑定成功时不包含)
+	NewlyBound []string   // 本次绑定成功
+	Foreign    []Mismatch // 绑定到其他开放平台帐号
+	Failed     []Failure  // 查询或绑定失败
+}
+
+// Consistent 所有授权帐号都绑定到了目标开放平台帐号
+func (r *Report) Consistent() bool {
+	return len(r.Unbound) == 0 && len(r.Foreign) == 0 && len(r.Failed) == 0
+}
+
+type Reconciler struct {
+	config *Config
+}
+
+func New(config *Config) *Reconciler {
+	if config.Authorizers == nil || config.Api == nil || config.OpenAppid == "" {
+		panic("openbind: Config.Authorizers, Config.Api and Config.OpenAppid are required")
+	}
+	if config.PageSize <= 0 || config.PageSize > maxAuthorizerPageSize {
+		config.PageSize = maxAuthorizerPageSize
+	}
+	return &Reconciler{config: config}
+}
+
+// Run 遍历所有授权帐号, 单个帐号的错误记录在 Report.Failed 中, 获取授权帐号列表失败时返回错误
+func (r *Reconciler) Run(ctx context.Context) (*Report, error) {
+	appids, err := r.listAuthorizers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{Total: len(appids)}
+	for _, appid := range appids {
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+		r.check(ctx, appid, report)
+	}
+	return report, nil
+}
+
+func (r *Reconciler) check(ctx context.Context, appid string, report *Report) {
+	api := r.config.Api(appid)
+	openAppid, err := api.GetOpenAccount(ctx, appid)
+	if err != nil {
+		report.Failed = append(report.Failed, Failure{Appid: appid, Error: err.Error()})
+		return
+	}
+
+	switch {
+	case openAppid == r.config.OpenAppid:
+		report.Bound = append(report.Bound, appid)
+	case openAppid != "":
+		report.Foreign = append(report.Foreign, Mismatch{Appid: appid, OpenAppid: openAppid})
+	case !r.config.Bind:
+		report.Unbound = append(report.Unbound, appid)
+	default:
+		if err = api.BindOpenAccount(ctx, appid, r.config.OpenAppid); err != nil {
+			report.Unbound = append(report.Unbound, appid)
+			report.Failed = append(report.Failed, Failure{Appid: appid, Error: err.Error()})
+			return
+		}
+		report.NewlyBound = append(report.NewlyBound, appid)
+	}
+}
+
+func (r *Reconciler) listAuthorizers(ctx context.Context) ([]string, error) {
+	appids := []string{}
+	seen := map[string]bool{}
+	for offset := 0; ; offset += r.config.PageSize {
+		list, err := r.config.Authorizers.GetAuthorizerList(ctx, offset, r.config.PageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range list {
+			if !seen[item.AuthorizerAppid] {
+				seen[item.AuthorizerAppid] = true
+				appids = append(appids, item.AuthorizerAppid)
+			}
+		}
+		if len(list) < r.config.PageSize {
+			break
+		}
+	}
+	sort.Strings(appids)
+	return appids, nil
+}
@@ -0,0 +1,88 @@
+package openbind
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/lixinio/weixin/utils"
+	"github.com/lixinio/weixin/weixin/authorizer"
+	"github.com/lixinio/weixin/wxopen"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	_ Api              = (*authorizer.AuthorizerApi)(nil)
+	_ AuthorizerLister = (*wxopen.WxOpen)(nil)
+)
+
+type fakeAuthorizers []string
+
+func (f fakeAuthorizers) GetAuthorizerList(
+	ctx context.Context, offset, count int,
+) ([]wxopen.AuthorizationLite, error) {
+	list := []wxopen.AuthorizationLite{}
+	for i := offset; i < len(f) && i < offset+count; i++ {
+		list = append(list, wxopen.AuthorizationLite{AuthorizerAppid: f[i]})
+	}
+	return list, nil
+}
+
+// 模拟授权帐号绑定的开放平台帐号
+type fakeApi struct {
+	bindings map[string]string
+	failed   map[string]bool
+}
+
+func (f *fakeApi) GetOpenAccount(ctx context.Context, appid string) (string, error) {
+	return f.bindings[appid], nil
+}
+
+func (f *fakeApi) BindOpenAccount(ctx context.Context, appid, openAppid string) error {
+	if f.failed[appid] {
+		return &utils.WeixinError{ErrCode: 89004, ErrMsg: "open account limit"}
+	}
+	f.bindings[appid] = openAppid
+	return nil
+}
+
+func TestReconciler(t *testing.T) {
+	ctx := context.Background()
+	appids := fakeAuthorizers{}
+	for i := 0; i < 5; i++ {
+		appids = append(appids, fmt.Sprintf("wx_%d", i))
+	}
+	api := &fakeApi{
+		bindings: map[string]string{"wx_0": "wx_open", "wx_1": "wx_other"},
+		failed:   map[string]bool{"wx_4": true},
+	}
+	config := &Config{
+		Authorizers: appids,
+		Api:         func(appid string) Api { return api },
+		OpenAppid:   "wx_open",
+		PageSize:    2,
+	}
+
+	report, err := New(config).Run(ctx)
+	require.Equal(t, nil, err)
+	require.Equal(t, 5, report.Total)
+	require.Equal(t, []string{"wx_0"}, report.Bound)
+	require.Equal(t, []string{"wx_2", "wx_3", "wx_4"}, report.Unbound)
+	require.Equal(t, []Mismatch{{Appid: "wx_1", OpenAppid: "wx_other"}}, report.Foreign)
+	require.False(t, report.Consistent())
+
+	config.Bind = true
+	report, err = New(config).Run(ctx)
+	require.Equal(t, nil, err)
+	require.Equal(t, []string{"wx_2", "wx_3"}, report.NewlyBound)
+	require.Equal(t, []string{"wx_4"}, report.Unbound)
+	require.Equal(t, 1, len(report.Failed))
+	require.Equal(t, "wx_open", api.bindings["wx_3"])
+
+	api.failed["wx_4"] = false
+	api.bindings["wx_1"] = ""
+	report, err = New(config).Run(ctx)
+	require.Equal(t, nil, err)
+	require.True(t, report.Consistent())
+	require.Equal(t, []string{"wx_0", "wx_2", "wx_3"}, report.Bound)
+}
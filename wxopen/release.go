@@ -4,21 +4,41 @@ import (
 	"context"
 	"encoding/xml"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/lixinio/weixin/utils"
 	"github.com/lixinio/weixin/weixin/message_api"
 	"github.com/lixinio/weixin/weixin/server_api"
 )
 
+const (
+	releaseTestText        = "TESTCOMPONENT_MSG_TYPE_TEXT"
+	releaseQueryAuthPrefix = "QUERY_AUTH_CODE:"
+	releaseSendTimeout     = 30 * time.Second
+)
+
 type ReleaseApp struct {
 	UserName string // 例如 gh_8dad206e9538
 	IsMp     bool   // true: 小程序，false，公众号
 }
 
+// ReleaseApps 全网发布检测使用的测试帐号, key 为 appid
 type ReleaseApps map[string]*ReleaseApp
 
+// Lookup 根据原始ID(消息的 ToUserName)查找测试帐号
+func (apps ReleaseApps) Lookup(userName string) (string, *ReleaseApp, bool) {
+	for appid, app := range apps {
+		if app.UserName == userName {
+			return appid, app, true
+		}
+	}
+	return "", nil, false
+}
+
+// ReleaseAppIDS 开放平台公布的全网发布测试帐号, 微信调整之后可通过 ReleaseConfig.Apps 覆盖
 var ReleaseAppIDS = ReleaseApps{
 	"wx570bc396a51b8ff8": {
 		UserName: "gh_3c884a361561",
@@ -62,49 +82,119 @@ var ReleaseAppIDS = ReleaseApps{
 	},
 }
 
-// 全网发布
+// ReleaseConfig 全网发布检测配置
+type ReleaseConfig struct {
+	Apps ReleaseApps // 测试帐号, 默认 ReleaseAppIDS
+	// 使用 query_auth_code 换取测试帐号的授权并发送客服消息, 默认通过 QueryAuth + 客服消息接口发送
+	Sender func(ctx context.Context, authCode, toUser, content string) error
+	// 客服消息发送完成之后回调(异步), err 为空表示成功, 可选
+	OnSent func(authCode string, err error)
+}
+
+/*
+全网发布检测中间件
+测试帐号(ReleaseConfig.Apps)的消息按照检测用例回复, 其他帐号的消息交给 next 处理
+
+  - 事件: 回复文本 "{Event}from_callback"
+  - 文本 TESTCOMPONENT_MSG_TYPE_TEXT: 回复文本 "TESTCOMPONENT_MSG_TYPE_TEXT_callback"
+  - 文本 QUERY_AUTH_CODE:$query_auth_code$: 立即回复空串, 然后异步使用授权码调用客服消息接口回复 "$query_auth_code$_from_api"
+
+https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/operation/thirdparty/releases_instructions.html
+*/
+func (api *WxOpen) ReleaseMiddleware(
+	serverApi *server_api.ServerApi, config *ReleaseConfig,
+) func(next utils.XmlHandlerFunc) utils.XmlHandlerFunc {
+	apps := ReleaseAppIDS
+	if config != nil && config.Apps != nil {
+		apps = config.Apps
+	}
+	release := api.releaseHandler(serverApi, config)
+
+	return func(next utils.XmlHandlerFunc) utils.XmlHandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request, body []byte) error {
+			message := &server_api.Message{}
+			if err := xml.Unmarshal(body, message); err != nil {
+				utils.HttpAbortBadRequest(w)
+				return err
+			}
+			if _, _, ok := apps.Lookup(message.ToUserName); !ok {
+				return next(w, r, body)
+			}
+			return release(w, r, body)
+		}
+	}
+}
+
+// 全网发布, 所有消息都按照检测用例处理, 只用于测试帐号的消息回调地址
+// 与正常的消息回调地址共用时使用 ReleaseMiddleware
 // https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/operation/thirdparty/releases_instructions.html
 func (api *WxOpen) ServeRelease(
 	serverApi *server_api.ServerApi,
 ) utils.XmlHandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request, body []byte) (err error) {
+	return api.releaseHandler(serverApi, nil)
+}
+
+func (api *WxOpen) releaseHandler(
+	serverApi *server_api.ServerApi, config *ReleaseConfig,
+) utils.XmlHandlerFunc {
+	sender := api.sendReleaseMessage
+	var onSent func(string, error)
+	if config != nil {
+		if config.Sender != nil {
+			sender = config.Sender
+		}
+		onSent = config.OnSent
+	}
+
+	return func(w http.ResponseWriter, r *http.Request, body []byte) error {
 		message := &server_api.Message{}
-		if err = xml.Unmarshal(body, message); err != nil {
+		if err := xml.Unmarshal(body, message); err != nil {
 			utils.HttpAbortBadRequest(w)
-			return
+			return err
 		}
 
 		switch message.MsgType {
 		case server_api.MsgTypeText:
 			msg := &server_api.MessageText{}
-			if err = xml.Unmarshal(body, msg); err != nil {
+			if err := xml.Unmarshal(body, msg); err != nil {
 				utils.HttpAbortBadRequest(w)
-				return
+				return err
 			}
-			if msg.Content == "TESTCOMPONENT_MSG_TYPE_TEXT" {
-				serverApi.ResponseText(w, r, &server_api.ReplyMessageText{
+			if msg.Content == releaseTestText {
+				return serverApi.ResponseText(w, r, &server_api.ReplyMessageText{
 					ReplyMessage: *msg.Reply(),
-					Content:      server_api.CDATA("TESTCOMPONENT_MSG_TYPE_TEXT_callback"),
+					Content:      server_api.CDATA(releaseTestText + "_callback"),
 				})
-				return nil
 			}
-
-			if err = processOtherText(r.Context(), api, msg); err != nil {
-				return
+			if strings.HasPrefix(msg.Content, releaseQueryAuthPrefix) {
+				authCode := strings.TrimPrefix(msg.Content, releaseQueryAuthPrefix)
+				// 先回复空串, 客服消息在请求结束之后发送
+				go func(toUser string) {
+					ctx, cancel := context.WithTimeout(context.Background(), releaseSendTimeout)
+					defer cancel()
+					err := sender(ctx, authCode, toUser, authCode+"_from_api")
+					if onSent != nil {
+						onSent(authCode, err)
+					}
+				}(msg.FromUserName)
+				w.WriteHeader(http.StatusOK)
+				return nil
 			}
-			w.WriteHeader(http.StatusOK)
 		case server_api.MsgTypeEvent:
 			event := &server_api.Event{}
-			if err = xml.Unmarshal(body, event); err != nil {
+			if err := xml.Unmarshal(body, event); err != nil {
 				utils.HttpAbortBadRequest(w)
-				return
+				return err
 			}
 			return serverApi.ResponseText(w, r, &server_api.ReplyMessageText{
 				ReplyMessage: *event.Reply(),
 				Content:      server_api.CDATA(fmt.Sprintf("%sfrom_callback", event.Event)),
 			})
 		}
-		return nil
+
+		// 检测用例之外的消息
+		_, err := io.WriteString(w, "success")
+		return err
 	}
 }
 
@@ -116,15 +206,8 @@ func newStaticClient(accessToken string) *utils.Client {
 	return client
 }
 
-func processOtherText(
-	ctx context.Context, api *WxOpen, msg *server_api.MessageText,
-) (err error) {
-	items := strings.Split(msg.Content, ":")
-	if len(items) != 2 || items[0] != "QUERY_AUTH_CODE" {
-		return fmt.Errorf("invalid query auth code %s", items)
-	}
-
-	authCode := items[1]
+// sendReleaseMessage 使用授权码换取测试帐号的 access_token, 然后发送客服消息
+func (api *WxOpen) sendReleaseMessage(ctx context.Context, authCode, toUser, content string) error {
 	authInfo, err := api.QueryAuth(ctx, authCode)
 	if err != nil {
 		return err
@@ -132,15 +215,10 @@ func processOtherText(
 
 	client := newStaticClient(authInfo.AuthorizerAccessToken)
 	messageApi := message_api.NewApi(client)
-	err = messageApi.SendCustomTextMessage(
-		ctx,
-		msg.FromUserName,
-		fmt.Sprintf("%s_from_api", authCode),
-	)
-	if err != nil {
+	if err = messageApi.SendCustomTextMessage(ctx, toUser, content); err != nil {
 		return fmt.Errorf("error send custom text message by token '%s', error %w",
 			authInfo.AuthorizerAccessToken, err,
 		)
 	}
-	return
+	return nil
 }
@@ -0,0 +1,82 @@
+package wxopen
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lixinio/weixin/weixin/server_api"
+	"github.com/stretchr/testify/require"
+)
+
+func releaseMessage(toUser, msgType, content string) []byte {
+	return []byte(fmt.Sprintf(`<xml>
+  <ToUserName><![CDATA[%s]]></ToUserName>
+  <FromUserName><![CDATA[ozy4qt1eDxSxzCr0aNT0mXCWfrDE]]></FromUserName>
+  <CreateTime>1413192605</CreateTime>
+  <MsgType><![CDATA[%s]]></MsgType>
+  %s
+</xml>`, toUser, msgType, content))
+}
+
+func TestReleaseMiddleware(t *testing.T) {
+	open := &WxOpen{}
+	serverApi := server_api.NewApi("wx_component", "token", "", nil)
+	sent := make(chan string, 1)
+	middleware := open.ReleaseMiddleware(serverApi, &ReleaseConfig{
+		Apps: ReleaseApps{"wx_test": {UserName: "gh_test", IsMp: true}},
+		Sender: func(ctx context.Context, authCode, toUser, content string) error {
+			sent <- toUser + ":" + content
+			return nil
+		},
+	})
+	handler := middleware(func(w http.ResponseWriter, r *http.Request, body []byte) error {
+		_, err := io.WriteString(w, "normal")
+		return err
+	})
+
+	serve := func(body []byte) string {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/callback", nil)
+		require.Equal(t, nil, handler(w, r, body))
+		require.Equal(t, http.StatusOK, w.Code)
+		return w.Body.String()
+	}
+
+	// 非测试帐号交给正常的处理逻辑
+	output := serve(releaseMessage("gh_normal", "text", "<Content><![CDATA[TESTCOMPONENT_MSG_TYPE_TEXT]]></Content>"))
+	require.Equal(t, "normal", output)
+
+	output = serve(releaseMessage("gh_test", "text", "<Content><![CDATA[TESTCOMPONENT_MSG_TYPE_TEXT]]></Content>"))
+	require.True(t, strings.Contains(output, "TESTCOMPONENT_MSG_TYPE_TEXT_callback"), output)
+	require.True(t, strings.Contains(output, "<ToUserName><![CDATA[ozy4qt1eDxSxzCr0aNT0mXCWfrDE]]></ToUserName>"), output)
+
+	output = serve(releaseMessage("gh_test", "event", "<Event><![CDATA[LOCATION]]></Event>"))
+	require.True(t, strings.Contains(output, "LOCATIONfrom_callback"), output)
+
+	output = serve(releaseMessage("gh_test", "text", "<Content><![CDATA[QUERY_AUTH_CODE:auth_code_xxx]]></Content>"))
+	require.Equal(t, "", output)
+	select {
+	case message := <-sent:
+		require.Equal(t, "ozy4qt1eDxSxzCr0aNT0mXCWfrDE:auth_code_xxx_from_api", message)
+	case <-time.After(time.Second):
+		t.Fatal("custom message not sent")
+	}
+
+	require.Equal(t, "success", serve(releaseMessage("gh_test", "image", "")))
+}
+
+func TestReleaseAppsLookup(t *testing.T) {
+	appid, app, ok := ReleaseAppIDS.Lookup("gh_8dad206e9538")
+	require.True(t, ok)
+	require.Equal(t, "wxd101a85aa106f53e", appid)
+	require.True(t, app.IsMp)
+
+	_, _, ok = ReleaseAppIDS.Lookup("gh_unknown")
+	require.False(t, ok)
+}
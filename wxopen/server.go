@@ -95,6 +95,18 @@ func (wxopen *WxOpen) ParseXML(body []byte) (m interface{}, err error) {
 			return
 		}
 		return msg, nil
+	case EventTypeFastRegister:
+		msg := &EventFastRegister{}
+		if err = xml.Unmarshal(body, msg); err != nil {
+			return
+		}
+		return msg, nil
+	case EventTypeFastRegisterBeta:
+		msg := &EventFastRegisterBeta{}
+		if err = xml.Unmarshal(body, msg); err != nil {
+			return
+		}
+		return msg, nil
 	}
 	return
 }
@@ -0,0 +1,63 @@
+package wxopen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFastRegisterEvent(t *testing.T) {
+	open := &WxOpen{}
+	event, err := open.ParseXML([]byte(`<xml>
+  <AppId><![CDATA[wx_component]]></AppId>
+  <CreateTime>1535442403</CreateTime>
+  <InfoType><![CDATA[notify_third_fasteregister]]></InfoType>
+  <appid>wx_weapp</appid>
+  <status>0</status>
+  <auth_code>auth_code_xxx</auth_code>
+  <msg>OK</msg>
+  <info>
+    <name><![CDATA[企业名称]]></name>
+    <code><![CDATA[企业代码]]></code>
+    <code_type>1</code_type>
+    <legal_persona_wechat><![CDATA[法人微信号]]></legal_persona_wechat>
+    <legal_persona_name><![CDATA[法人姓名]]></legal_persona_name>
+    <component_phone><![CDATA[1234567]]></component_phone>
+  </info>
+</xml>`))
+	require.Equal(t, nil, err)
+	msg, ok := event.(*EventFastRegister)
+	require.True(t, ok)
+	require.Equal(t, "wx_component", msg.AppId)
+	require.Equal(t, "wx_weapp", msg.Appid)
+	require.Equal(t, "auth_code_xxx", msg.AuthCode)
+	require.Equal(t, CodeTypeCreditCode, msg.Info.CodeType)
+	require.Equal(t, "法人姓名", msg.Info.LegalPersonaName)
+
+	event, err = open.ParseXML([]byte(`<xml>
+  <AppId><![CDATA[wx_component]]></AppId>
+  <CreateTime>1535442403</CreateTime>
+  <InfoType><![CDATA[notify_third_fastregisterbetaapp]]></InfoType>
+  <appid>wx_beta</appid>
+  <status>0</status>
+  <msg>OK</msg>
+  <info>
+    <unique_id><![CDATA[unique_id_xxx]]></unique_id>
+    <name><![CDATA[试用小程序]]></name>
+  </info>
+</xml>`))
+	require.Equal(t, nil, err)
+	beta, ok := event.(*EventFastRegisterBeta)
+	require.True(t, ok)
+	require.Equal(t, "wx_beta", beta.Appid)
+	require.Equal(t, "unique_id_xxx", beta.Info.UniqueID)
+}
+
+func TestFastRegisterAuthUrl(t *testing.T) {
+	open := &WxOpen{Config: &Config{Appid: "wx_component"}}
+	require.Equal(
+		t,
+		"https://mp.weixin.qq.com/cgi-bin/fastregisterauth?appid=wx_oa&component_appid=wx_component&copy_wx_verify=1&redirect_uri=https%3A%2F%2Fexample.com%2Fcallback",
+		open.FastRegisterAuthUrl("wx_oa", true, "https://example.com/callback"),
+	)
+}
@@ -25,6 +25,8 @@ const (
 	EventTypeAuthorized            = "authorized"
 	EventTypeUnauthorized          = "unauthorized"
 	EventTypeUpdateAuthorized      = "updateauthorized"
+	EventTypeFastRegister          = "notify_third_fasteregister"       // 快速注册企业/个人小程序结果
+	EventTypeFastRegisterBeta      = "notify_third_fastregisterbetaapp" // 注册试用小程序结果
 )
 
 type Event struct {
@@ -100,3 +102,70 @@ type EventUpdateAuthorized struct {
 	AuthorizationCodeExpiredTime string
 	PreAuthCode                  string
 }
+
+// 快速注册小程序的申请信息, 企业和个人小程序的字段不同
+type FastRegisterInfo struct {
+	Name               string `xml:"name"`                 // 企业名称
+	Code               string `xml:"code"`                 // 企业代码
+	CodeType           int    `xml:"code_type"`            // 企业代码类型
+	LegalPersonaWechat string `xml:"legal_persona_wechat"` // 法人微信号
+	LegalPersonaName   string `xml:"legal_persona_name"`   // 法人姓名
+	WxUser             string `xml:"wxuser"`               // 个人小程序 用户微信号
+	IDName             string `xml:"idname"`               // 个人小程序 用户姓名
+	ComponentPhone     string `xml:"component_phone"`      // 第三方联系电话
+	UniqueID           string `xml:"unique_id"`            // 试用小程序 创建的唯一 id
+}
+
+/*
+快速注册小程序结果通知
+https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/api/Register_Mini_Programs/Fast_Registration_Interface_document.html
+<xml>
+  <AppId><![CDATA[第三方平台appid]]></AppId>
+  <CreateTime>1535442403</CreateTime>
+  <InfoType><![CDATA[notify_third_fasteregister]]></InfoType>
+  <appid>创建小程序appid</appid>
+  <status>0</status>
+  <auth_code>xxxxx第三方授权码</auth_code>
+  <msg>OK</msg>
+  <info>
+    <name><![CDATA[企业名称]]></name>
+    <code><![CDATA[企业代码]]></code>
+    <code_type>1</code_type>
+    <legal_persona_wechat><![CDATA[法人微信号]]></legal_persona_wechat>
+    <legal_persona_name><![CDATA[法人姓名]]></legal_persona_name>
+    <component_phone><![CDATA[第三方联系电话]]></component_phone>
+  </info>
+</xml>
+*/
+type EventFastRegister struct {
+	Event
+	Appid    string           `xml:"appid"`     // 创建的小程序 appid
+	Status   int              `xml:"status"`    // 0 成功, 其他为错误码
+	AuthCode string           `xml:"auth_code"` // 第三方授权码, 用于 QueryAuth 换取授权信息
+	Msg      string           `xml:"msg"`
+	Info     FastRegisterInfo `xml:"info"`
+}
+
+/*
+注册试用小程序结果通知
+https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/2.0/api/beta_Mini_Programs/fastregister.html
+<xml>
+  <AppId><![CDATA[第三方平台appid]]></AppId>
+  <CreateTime>1535442403</CreateTime>
+  <InfoType><![CDATA[notify_third_fastregisterbetaapp]]></InfoType>
+  <appid>创建试用小程序appid</appid>
+  <status>0</status>
+  <msg>OK</msg>
+  <info>
+    <unique_id><![CDATA[unique_id]]></unique_id>
+    <name><![CDATA[小程序名称]]></name>
+  </info>
+</xml>
+*/
+type EventFastRegisterBeta struct {
+	Event
+	Appid  string           `xml:"appid"`
+	Status int              `xml:"status"`
+	Msg    string           `xml:"msg"`
+	Info   FastRegisterInfo `xml:"info"`
+}
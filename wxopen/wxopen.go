@@ -36,8 +36,10 @@ type Config struct {
 }
 
 type WxOpen struct {
-	Config           *Config
-	Client           *utils.Client
+	Config *Config
+	Client *utils.Client
+	// 部分接口(例如试用小程序)的 component_access_token 参数名为 access_token
+	tokenClient      *utils.Client
 	ticketCache      *utils.AccessTokenCache
 	accessTokenCache *utils.AccessTokenCache
 }
@@ -50,6 +52,7 @@ func New(cache utils.Cache, locker utils.Lock, config *Config) *WxOpen {
 	instance := &WxOpen{
 		Config:           config,
 		Client:           utils.NewClient(WXServerUrl, accessTokenCache),
+		tokenClient:      utils.NewClient(WXServerUrl, accessTokenCache),
 		ticketCache:      ticketCache,
 		accessTokenCache: accessTokenCache,
 	}
@@ -63,11 +66,13 @@ func NewLite(
 	appID string,
 ) *WxOpen {
 	config := &Config{Appid: appID}
+	accessTokenCache := utils.NewAccessTokenCache(
+		newAccessTokenAdaptor(config, nil), cache, locker,
+	)
 	instance := &WxOpen{
-		Config: config,
-		Client: utils.NewClient(WXServerUrl, utils.NewAccessTokenCache(
-			newAccessTokenAdaptor(config, nil), cache, locker,
-		)),
+		Config:      config,
+		Client:      utils.NewClient(WXServerUrl, accessTokenCache),
+		tokenClient: utils.NewClient(WXServerUrl, accessTokenCache),
 	}
 	instance.Client.UpdateAccessTokenKey(accessTokenKey) // token的名称不一样
 	return instance
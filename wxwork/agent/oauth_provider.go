@@ -0,0 +1,29 @@
+package agent
+
+import (
+	"context"
+
+	"github.com/lixinio/weixin/utils/oauthflow"
+)
+
+type oauthProvider struct {
+	agent *Agent
+}
+
+// OAuthProvider 企业微信自建应用网页授权, 用于 oauthflow
+func (agent *Agent) OAuthProvider() oauthflow.Provider {
+	return &oauthProvider{agent: agent}
+}
+
+func (p *oauthProvider) AuthorizeUrl(redirectUri, state string) string {
+	return p.agent.GetAuthorizeUrl(redirectUri, state)
+}
+
+func (p *oauthProvider) Exchange(ctx context.Context, code string) (*oauthflow.Identity, error) {
+	userInfo, err := p.agent.GetUserInfo(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	// userid 只在企业内唯一, 需要同时记录 corpid
+	return &oauthflow.Identity{CorpID: p.agent.CorpID(), UserID: userInfo.UserID}, nil
+}
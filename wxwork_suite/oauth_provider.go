@@ -0,0 +1,35 @@
+package wxwork_suite
+
+import (
+	"context"
+
+	"github.com/lixinio/weixin/utils/oauthflow"
+)
+
+type oauthProvider struct {
+	suite *WxWorkSuite
+	scope string
+}
+
+// OAuthProvider 企业微信第三方应用网页授权, 用于 oauthflow
+func (suite *WxWorkSuite) OAuthProvider(scope string) oauthflow.Provider {
+	return &oauthProvider{suite: suite, scope: scope}
+}
+
+func (p *oauthProvider) AuthorizeUrl(redirectUri, state string) string {
+	return p.suite.GetAuthorizeUrl(redirectUri, p.scope, state)
+}
+
+func (p *oauthProvider) Exchange(ctx context.Context, code string) (*oauthflow.Identity, error) {
+	userInfo, err := p.suite.GetUserInfo3rd(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	return &oauthflow.Identity{
+		CorpID:     userInfo.CorpID,
+		UserID:     userInfo.UserID,
+		OpenUserID: userInfo.OpenUserID,
+		Scope:      p.scope,
+		UserTicket: userInfo.UserTicket,
+	}, nil
+}